@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	privatearchive "github.com/ethereum/go-ethereum/private/archive"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/syndtr/goleveldb/leveldb/util"
 	"gopkg.in/urfave/cli.v1"
@@ -70,6 +71,8 @@ It expects the genesis file as argument.`,
 			utils.GCModeFlag,
 			utils.CacheDatabaseFlag,
 			utils.CacheGCFlag,
+			utils.PrivateArchiveFlag,
+			utils.PrivateArchiveKeyFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -77,7 +80,12 @@ The import command imports blocks from an RLP-encoded form. The form can be one
 with several RLP-encoded blocks, or several files can be used.
 
 If only one file is used, import error will result in failure. If several files are used,
-processing will proceed even if an individual RLP-file import failure occurs.`,
+processing will proceed even if an individual RLP-file import failure occurs.
+
+With --private.archive, the private state is rebuilt from the payloads of the given
+archive instead of the transaction manager, the archive being decrypted with the key
+of --private.archive.key. The private state root of each block is checked against the
+archived one.`,
 	}
 	exportCommand = cli.Command{
 		Action:    utils.MigrateFlags(exportChain),
@@ -88,6 +96,8 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 			utils.DataDirFlag,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
+			utils.PrivateArchiveFlag,
+			utils.PrivateArchiveKeyFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -95,7 +105,12 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last block to write. In this mode, the file will be appended
 if already existing. If the file ends with .gz, the output will
-be gzipped.`,
+be gzipped.
+
+With --private.archive, the private state root and the private
+payloads the node is party to are also exported for the same
+blocks, into the given archive encrypted with the key of
+--private.archive.key. The archive is always overwritten.`,
 	}
 	importPreimagesCommand = cli.Command{
 		Action:    utils.MigrateFlags(importPreimages),
@@ -235,6 +250,15 @@ func importChain(ctx *cli.Context) error {
 	chain, chainDb := utils.MakeChain(ctx, stack)
 	defer chainDb.Close()
 
+	archive, closeArchive := openPrivateArchive(ctx)
+	defer closeArchive()
+
+	importFile := func(fn string) error {
+		if archive != nil {
+			return utils.ImportPrivateChain(chain, chainDb, fn, archive)
+		}
+		return utils.ImportChain(chain, fn)
+	}
 	// Start periodically gathering memory profiles
 	var peakMemAlloc, peakMemSys uint64
 	go func() {
@@ -254,12 +278,12 @@ func importChain(ctx *cli.Context) error {
 	start := time.Now()
 
 	if len(ctx.Args()) == 1 {
-		if err := utils.ImportChain(chain, ctx.Args().First()); err != nil {
+		if err := importFile(ctx.Args().First()); err != nil {
 			log.Error("Import error", "err", err)
 		}
 	} else {
 		for _, arg := range ctx.Args() {
-			if err := utils.ImportChain(chain, arg); err != nil {
+			if err := importFile(arg); err != nil {
 				log.Error("Import error", "file", arg, "err", err)
 			}
 		}
@@ -267,20 +291,22 @@ func importChain(ctx *cli.Context) error {
 	chain.Stop()
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
-	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		utils.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
+	// Output pre-compaction stats mostly to see the import trashing, the stats
+	// and compaction being leveldb specific
+	db, leveldb := chainDb.(*ethdb.LDBDatabase)
+	if leveldb {
+		stats, err := db.LDB().GetProperty("leveldb.stats")
+		if err != nil {
+			utils.Fatalf("Failed to read database stats: %v", err)
+		}
+		fmt.Println(stats)
 
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		utils.Fatalf("Failed to read database iostats: %v", err)
+		ioStats, err := db.LDB().GetProperty("leveldb.iostats")
+		if err != nil {
+			utils.Fatalf("Failed to read database iostats: %v", err)
+		}
+		fmt.Println(ioStats)
 	}
-	fmt.Println(ioStats)
 
 	fmt.Printf("Trie cache misses:  %d\n", trie.CacheMisses())
 	fmt.Printf("Trie cache unloads: %d\n\n", trie.CacheUnloads())
@@ -294,25 +320,25 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Allocations:   %.3f million\n", float64(mem.Mallocs)/1000000)
 	fmt.Printf("GC pause:      %v\n\n", time.Duration(mem.PauseTotalNs))
 
-	if ctx.GlobalIsSet(utils.NoCompactionFlag.Name) {
+	if ctx.GlobalIsSet(utils.NoCompactionFlag.Name) || !leveldb {
 		return nil
 	}
 
 	// Compact the entire database to more accurately measure disk io and print the stats
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
+	if err := db.LDB().CompactRange(util.Range{}); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 
-	stats, err = db.LDB().GetProperty("leveldb.stats")
+	stats, err := db.LDB().GetProperty("leveldb.stats")
 	if err != nil {
 		utils.Fatalf("Failed to read database stats: %v", err)
 	}
 	fmt.Println(stats)
 
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
+	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
 	if err != nil {
 		utils.Fatalf("Failed to read database iostats: %v", err)
 	}
@@ -325,41 +351,87 @@ func exportChain(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
 	}
+	var archiveKey []byte
+	if ctx.String(utils.PrivateArchiveFlag.Name) != "" {
+		archiveKey = loadPrivateArchiveKey(ctx)
+	}
 	stack := makeFullNode(ctx)
-	chain, _ := utils.MakeChain(ctx, stack)
+	chain, chainDb := utils.MakeChain(ctx, stack)
 	start := time.Now()
 
-	var err error
+	var (
+		err         error
+		first, last = uint64(0), chain.CurrentBlock().NumberU64()
+	)
 	fp := ctx.Args().First()
 	if len(ctx.Args()) < 3 {
 		err = utils.ExportChain(chain, fp)
 	} else {
 		// This can be improved to allow for numbers larger than 9223372036854775807
-		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
-		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		firstNum, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		lastNum, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
 		if ferr != nil || lerr != nil {
 			utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
 		}
-		if first < 0 || last < 0 {
+		if firstNum < 0 || lastNum < 0 {
 			utils.Fatalf("Export error: block number must be greater than 0\n")
 		}
-		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+		first, last = uint64(firstNum), uint64(lastNum)
+		err = utils.ExportAppendChain(chain, fp, first, last)
 	}
 
 	if err != nil {
 		utils.Fatalf("Export error: %v\n", err)
 	}
+	if fn := ctx.String(utils.PrivateArchiveFlag.Name); fn != "" {
+		if err := utils.ExportPrivateState(chain, chainDb, fn, archiveKey, first, last); err != nil {
+			utils.Fatalf("Private state export error: %v\n", err)
+		}
+	}
 	fmt.Printf("Export done in %v\n", time.Since(start))
 	return nil
 }
 
+// loadPrivateArchiveKey reads the key of the private state archive.
+func loadPrivateArchiveKey(ctx *cli.Context) []byte {
+	fn := ctx.String(utils.PrivateArchiveKeyFlag.Name)
+	if fn == "" {
+		utils.Fatalf("The private state archive requires a key (--%s)", utils.PrivateArchiveKeyFlag.Name)
+	}
+	key, err := privatearchive.LoadKey(fn)
+	if err != nil {
+		utils.Fatalf("Failed to load the private state archive key: %v", err)
+	}
+	return key
+}
+
+// openPrivateArchive opens the private state archive to import, if any.
+func openPrivateArchive(ctx *cli.Context) (*privatearchive.Reader, func()) {
+	fn := ctx.String(utils.PrivateArchiveFlag.Name)
+	if fn == "" {
+		return nil, func() {}
+	}
+	key := loadPrivateArchiveKey(ctx)
+
+	fh, err := os.Open(fn)
+	if err != nil {
+		utils.Fatalf("Failed to open the private state archive: %v", err)
+	}
+	archive, err := privatearchive.NewReader(fh, key)
+	if err != nil {
+		fh.Close()
+		utils.Fatalf("Failed to read the private state archive: %v", err)
+	}
+	return archive, func() { fh.Close() }
+}
+
 // importPreimages imports preimage data from the specified file.
 func importPreimages(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -375,7 +447,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -398,7 +470,7 @@ func copyDb(ctx *cli.Context) error {
 	dl := downloader.New(syncmode, chainDb, new(event.TypeMux), chain, nil, nil)
 
 	// Create a source peer to satisfy downloader requests from
-	db, err := ethdb.NewLDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	db, err := ethdb.NewDatabase("", ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
 	if err != nil {
 		return err
 	}
@@ -423,12 +495,14 @@ func copyDb(ctx *cli.Context) error {
 	fmt.Printf("Database copy done in %v\n", time.Since(start))
 
 	// Compact the entire database to remove any sync overhead
-	start = time.Now()
-	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
-		utils.Fatalf("Compaction failed: %v", err)
+	if chainDb, ok := chainDb.(*ethdb.LDBDatabase); ok {
+		start = time.Now()
+		fmt.Println("Compacting entire database...")
+		if err = chainDb.LDB().CompactRange(util.Range{}); err != nil {
+			utils.Fatalf("Compaction failed: %v", err)
+		}
+		fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 
 	return nil
 }
@@ -215,16 +215,43 @@ func dumpConfig(ctx *cli.Context) error {
 
 func RegisterRaftService(stack *node.Node, ctx *cli.Context, cfg gethConfig, ethChan <-chan *eth.Ethereum) {
 	blockTimeMillis := ctx.GlobalInt(utils.RaftBlockTimeFlag.Name)
+	maxLatencyMillis := ctx.GlobalInt(utils.RaftMaxLatencyFlag.Name)
+	maxBatchTxs := ctx.GlobalInt(utils.RaftMaxBatchTxsFlag.Name)
+	maxBatchGas := ctx.GlobalUint64(utils.RaftMaxBatchGasFlag.Name)
 	datadir := ctx.GlobalString(utils.DataDirFlag.Name)
 	joinExistingId := ctx.GlobalInt(utils.RaftJoinExistingFlag.Name)
+	forceNewCluster := ctx.GlobalBool(utils.RaftForceNewClusterFlag.Name)
 	useDns := ctx.GlobalBool(utils.RaftDNSEnabledFlag.Name)
+	tlsConfig := &raft.TLSConfig{
+		Enabled:  ctx.GlobalBool(utils.RaftTLSFlag.Name),
+		CertFile: ctx.GlobalString(utils.RaftTLSCertFlag.Name),
+		KeyFile:  ctx.GlobalString(utils.RaftTLSKeyFlag.Name),
+		CAFile:   ctx.GlobalString(utils.RaftTLSCAFlag.Name),
+	}
 
 	raftPort := uint16(ctx.GlobalInt(utils.RaftPortFlag.Name))
 
+	if maxLatencyMillis != 0 && maxLatencyMillis < blockTimeMillis {
+		utils.Fatalf("--%s (%d) must not be lower than --%s (%d)", utils.RaftMaxLatencyFlag.Name, maxLatencyMillis, utils.RaftBlockTimeFlag.Name, blockTimeMillis)
+	}
+
 	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		privkey := cfg.Node.NodeKey()
 		strId := enode.PubkeyToIDV4(&privkey.PublicKey).String()
 		blockTimeNanos := time.Duration(blockTimeMillis) * time.Millisecond
+		var mintingPolicy raft.MintingPolicy
+		if maxLatencyMillis != 0 || maxBatchTxs != 0 || maxBatchGas != 0 {
+			maxLatency := blockTimeNanos
+			if maxLatencyMillis != 0 {
+				maxLatency = time.Duration(maxLatencyMillis) * time.Millisecond
+			}
+			mintingPolicy = &raft.AdaptiveMintingPolicy{
+				MinInterval: blockTimeNanos,
+				MaxLatency:  maxLatency,
+				MaxBatchTxs: maxBatchTxs,
+				MaxBatchGas: maxBatchGas,
+			}
+		}
 		peers := cfg.Node.StaticNodes()
 
 		var myId uint16
@@ -256,7 +283,7 @@ func RegisterRaftService(stack *node.Node, ctx *cli.Context, cfg gethConfig, eth
 		}
 
 		ethereum := <-ethChan
-		return raft.New(ctx, ethereum.ChainConfig(), myId, raftPort, joinExisting, blockTimeNanos, ethereum, peers, datadir, useDns)
+		return raft.New(ctx, ethereum.ChainConfig(), myId, raftPort, joinExisting, forceNewCluster, blockTimeNanos, mintingPolicy, ethereum, peers, datadir, useDns, tlsConfig)
 	}); err != nil {
 		utils.Fatalf("Failed to register the Raft service: %v", err)
 	}
@@ -0,0 +1,82 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Low level database operations",
+		Category: "DATABASE COMMANDS",
+		Description: `
+
+Operate on the chain database of a stopped node.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "inspect",
+				Usage:  "Report the storage taken by each category of database content",
+				Action: utils.MigrateFlags(dbInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+				},
+				Description: `
+Iterate over the entire chain database and report the number of entries and the
+storage taken by each key prefix, including the private state roots and blooms
+of the Quorum private state. Entries whose key matches no known prefix are
+reported as unaccounted.`,
+			},
+		},
+	}
+)
+
+func dbInspect(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	stats, err := rawdb.InspectDatabase(chainDb)
+	if err != nil {
+		utils.Fatalf("Failed to inspect database: %v", err)
+	}
+	var (
+		count int
+		total common.StorageSize
+		table = tablewriter.NewWriter(os.Stdout)
+	)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeader([]string{"Category", "Entries", "Size"})
+	for _, stat := range stats {
+		table.Append([]string{stat.Category, fmt.Sprint(stat.Count), stat.Size.String()})
+		count += stat.Count
+		total += stat.Size
+	}
+	table.SetFooter([]string{"Total", fmt.Sprint(count), total.String()})
+	table.Render()
+	return nil
+}
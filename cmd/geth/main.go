@@ -61,6 +61,7 @@ var (
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
+		utils.DatabaseEngineFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
 		utils.DashboardEnabledFlag,
@@ -86,6 +87,12 @@ var (
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
 		utils.GCModeFlag,
+		utils.FreezerThresholdFlag,
+		utils.CheckpointFlag,
+		utils.CheckpointPrivateRootFlag,
+		utils.CheckpointPrivatePeerFlag,
+		utils.CheckpointPrivateBackupFlag,
+		utils.CheckpointPrivateClientsFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
 		utils.LightKDFFlag,
@@ -137,14 +144,29 @@ var (
 		configFileFlag,
 		// Quorum
 		utils.EnableNodePermissionFlag,
+		utils.TenantsFlag,
+		utils.TenantTokensFlag,
 		utils.RaftModeFlag,
 		utils.RaftBlockTimeFlag,
+		utils.RaftMaxLatencyFlag,
+		utils.RaftMaxBatchTxsFlag,
+		utils.RaftMaxBatchGasFlag,
 		utils.RaftJoinExistingFlag,
+		utils.RaftForceNewClusterFlag,
 		utils.RaftPortFlag,
 		utils.RaftDNSEnabledFlag,
+		utils.RaftTLSFlag,
+		utils.RaftTLSCertFlag,
+		utils.RaftTLSKeyFlag,
+		utils.RaftTLSCAFlag,
 		utils.EmitCheckpointsFlag,
 		utils.IstanbulRequestTimeoutFlag,
 		utils.IstanbulBlockPeriodFlag,
+		utils.IstanbulRoundChangeGrowthFlag,
+		utils.IstanbulRoundChangeIncrementFlag,
+		utils.IstanbulMaxRoundChangeTimeoutFlag,
+		utils.IstanbulRoundChangeResetFlag,
+		utils.IstanbulRelayFlag,
 		// End-Quorum
 	}
 
@@ -211,6 +233,10 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		// See raftcmd.go:
+		raftCommand,
+		// See dbcmd.go:
+		dbCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -0,0 +1,213 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/raft"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	raftEntriesFlag = cli.BoolFlag{
+		Name:  "entries",
+		Usage: "List every raft log entry following the snapshot",
+	}
+
+	raftCommand = cli.Command{
+		Name:     "raft",
+		Usage:    "Inspect and repair the persisted raft state",
+		Category: "RAFT COMMANDS",
+		Description: `
+
+Inspect, verify and repair the raft write-ahead log, snapshots and applied index
+stored under <DATADIR>/raft-wal, <DATADIR>/raft-snap and <DATADIR>/quorum-raft-state.
+
+All subcommands operate on a stopped node. Back up the data directory before
+running any of the repair subcommands.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "inspect",
+				Usage:  "Dump the raft snapshot, conf state, WAL entries and applied index",
+				Action: utils.MigrateFlags(raftInspect),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					raftEntriesFlag,
+				},
+				Description: `
+Print the latest raft snapshot (index, term, conf state, cluster members, removed
+peers and head block), the WAL hard state, a summary of the WAL entries following
+the snapshot and the persisted applied index.`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Cross-check the raft state against the chain head",
+				Action: utils.MigrateFlags(raftVerify),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+				},
+				Description: `
+Check that the applied index, the snapshot and the WAL are consistent with each
+other and with the local chain. Exits with an error if the node would not be able
+to restart correctly.`,
+			},
+			{
+				Name:   "compact",
+				Usage:  "Snapshot the raft state at the applied index",
+				Action: utils.MigrateFlags(raftCompact),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+					utils.RaftDNSEnabledFlag,
+				},
+				Description: `
+Take a new raft snapshot at the applied index, recording the cluster membership
+and chain head at that index, so that a restart only replays the WAL entries
+after it. Use --raftdnsenable if the node runs with DNS enabled.`,
+			},
+			{
+				Name:      "reset-applied-index",
+				Usage:     "Overwrite the persisted raft applied index",
+				ArgsUsage: "[<index>]",
+				Action:    utils.MigrateFlags(raftResetAppliedIndex),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.SyncModeFlag,
+				},
+				Description: `
+Overwrite the applied index stored in quorum-raft-state, so that the raft entries
+after it are re-applied to the chain on restart. Without an argument the index is
+set to the raft entry carrying the current chain head. The index must lie between
+the latest snapshot and the durably committed index.`,
+			},
+		},
+	}
+)
+
+func readRaftState(ctx *cli.Context) *raft.RaftState {
+	state, err := raft.ReadRaftState(ctx.GlobalString(utils.DataDirFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read raft state: %v", err)
+	}
+	return state
+}
+
+func raftInspect(ctx *cli.Context) error {
+	state := readRaftState(ctx)
+
+	if state.Snapshot == nil {
+		fmt.Println("Snapshot:           none")
+	} else {
+		meta := state.Snapshot.Metadata
+		fmt.Printf("Snapshot index:     %d\n", meta.Index)
+		fmt.Printf("Snapshot term:      %d\n", meta.Term)
+		fmt.Printf("Conf state:         %v\n", meta.ConfState.Nodes)
+		fmt.Printf("Snapshot head:      %x\n", state.SnapshotData.HeadBlockHash)
+	}
+	fmt.Printf("Hard state:         term=%d vote=%d commit=%d\n", state.HardState.Term, state.HardState.Vote, state.HardState.Commit)
+	fmt.Printf("Applied index:      %d\n", state.AppliedIndex)
+	fmt.Printf("WAL entries:        %d (last index %d)\n", len(state.Entries), state.LastIndex())
+
+	addresses, removed := state.Membership(state.LastIndex())
+	fmt.Printf("Removed peers:      %v\n", removed)
+	fmt.Println("Cluster members:")
+	for _, address := range addresses {
+		fmt.Printf("  %d: enode://%x@%s:%d?raftport=%d\n", address.RaftId, address.NodeId[:], address.Hostname, address.P2pPort, address.RaftPort)
+	}
+
+	if ctx.Bool(raftEntriesFlag.Name) {
+		out, err := json.MarshalIndent(state.DescribeEntries(), "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to encode raft entries: %v", err)
+		}
+		fmt.Printf("Entries:\n%s\n", out)
+	}
+	return nil
+}
+
+func raftVerify(ctx *cli.Context) error {
+	state := readRaftState(ctx)
+
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	issues := state.Verify(chain)
+	critical := 0
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.Critical {
+			critical++
+		}
+	}
+	if critical > 0 {
+		utils.Fatalf("Raft state is inconsistent with the chain (%d errors)", critical)
+	}
+	fmt.Println("Raft state is consistent with the chain")
+	return nil
+}
+
+func raftCompact(ctx *cli.Context) error {
+	state := readRaftState(ctx)
+
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	snapshot, err := state.Compact(chain, ctx.GlobalBool(utils.RaftDNSEnabledFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to compact raft state: %v", err)
+	}
+	fmt.Printf("Saved raft snapshot at index %d (term %d)\n", snapshot.Metadata.Index, snapshot.Metadata.Term)
+	return nil
+}
+
+func raftResetAppliedIndex(ctx *cli.Context) error {
+	state := readRaftState(ctx)
+
+	var index uint64
+	if ctx.NArg() > 0 {
+		var err error
+		if index, err = strconv.ParseUint(ctx.Args().First(), 10, 64); err != nil {
+			utils.Fatalf("Invalid index %q: %v", ctx.Args().First(), err)
+		}
+	} else {
+		stack := makeFullNode(ctx)
+		chain, chainDb := utils.MakeChain(ctx, stack)
+		headIndex, err := state.ChainHeadIndex(chain)
+		chainDb.Close()
+		if err != nil {
+			utils.Fatalf("Failed to locate chain head in the raft log: %v", err)
+		}
+		index = headIndex
+	}
+
+	previous := state.AppliedIndex
+	if err := state.ResetAppliedIndex(index); err != nil {
+		utils.Fatalf("Failed to reset applied index: %v", err)
+	}
+	fmt.Printf("Applied index reset from %d to %d\n", previous, index)
+	return nil
+}
@@ -69,6 +69,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			configFileFlag,
 			utils.DataDirFlag,
+			utils.DatabaseEngineFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.NetworkIdFlag,
@@ -77,6 +78,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.OttomanFlag,
 			utils.SyncModeFlag,
 			utils.GCModeFlag,
+			utils.FreezerThresholdFlag,
+			utils.CheckpointFlag,
+			utils.CheckpointPrivateRootFlag,
+			utils.CheckpointPrivatePeerFlag,
+			utils.CheckpointPrivateBackupFlag,
+			utils.CheckpointPrivateClientsFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightServFlag,
@@ -152,6 +159,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "QUORUM",
 		Flags: []cli.Flag{
 			utils.EnableNodePermissionFlag,
+			utils.TenantsFlag,
+			utils.TenantTokensFlag,
 		},
 	},
 	{
@@ -159,9 +168,17 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.RaftModeFlag,
 			utils.RaftBlockTimeFlag,
+			utils.RaftMaxLatencyFlag,
+			utils.RaftMaxBatchTxsFlag,
+			utils.RaftMaxBatchGasFlag,
 			utils.RaftJoinExistingFlag,
+			utils.RaftForceNewClusterFlag,
 			utils.RaftPortFlag,
 			utils.RaftDNSEnabledFlag,
+			utils.RaftTLSFlag,
+			utils.RaftTLSCertFlag,
+			utils.RaftTLSKeyFlag,
+			utils.RaftTLSCAFlag,
 		},
 	},
 	{
@@ -276,6 +293,11 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.IstanbulRequestTimeoutFlag,
 			utils.IstanbulBlockPeriodFlag,
+			utils.IstanbulRoundChangeGrowthFlag,
+			utils.IstanbulRoundChangeIncrementFlag,
+			utils.IstanbulMaxRoundChangeTimeoutFlag,
+			utils.IstanbulRoundChangeResetFlag,
+			utils.IstanbulRelayFlag,
 		},
 	},
 	{
@@ -19,6 +19,7 @@ package utils
 
 import (
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -36,6 +37,8 @@ import (
 	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/private"
+	privatearchive "github.com/ethereum/go-ethereum/private/archive"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -86,6 +89,27 @@ func StartNode(stack *node.Node) {
 }
 
 func ImportChain(chain *core.BlockChain, fn string) error {
+	return importChain(chain, fn, nil)
+}
+
+// ImportPrivateChain imports a blockchain the way ImportChain does, rebuilding
+// the private state from the payloads of the given private state archive instead
+// of the transaction manager. The private state root of each imported block is
+// checked against the archived one. The archive is read as the import progresses,
+// so that it may span several chain files imported in order.
+func ImportPrivateChain(chain *core.BlockChain, db ethdb.Database, fn string, archive *privatearchive.Reader) error {
+	importer := &privateImporter{
+		db:      db,
+		archive: archive,
+		store:   privatearchive.NewStore(),
+	}
+	defer func(manager private.PrivateTransactionManager) { private.P = manager }(private.P)
+	private.P = importer.store
+
+	return importChain(chain, fn, importer)
+}
+
+func importChain(chain *core.BlockChain, fn string, importer *privateImporter) error {
 	// Watch for Ctrl-C while the import is running.
 	// If a signal is received, the import will stop at the next batch.
 	interrupt := make(chan os.Signal, 1)
@@ -161,9 +185,75 @@ func ImportChain(chain *core.BlockChain, fn string) error {
 			log.Info("Skipping batch as all blocks present", "batch", batch, "first", blocks[0].Hash(), "last", blocks[i-1].Hash())
 			continue
 		}
+		if importer != nil {
+			if err := importer.load(missing); err != nil {
+				return err
+			}
+		}
 		if _, err := chain.InsertChain(missing); err != nil {
 			return fmt.Errorf("invalid block %d: %v", n, err)
 		}
+		if importer != nil {
+			if err := importer.verify(missing); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// privateImporter feeds the payloads of a private state archive to the blocks
+// being imported.
+type privateImporter struct {
+	db      ethdb.Database
+	archive *privatearchive.Reader
+	store   *privatearchive.Store
+
+	next    *privatearchive.Entry            // Entry read past the current batch
+	entries map[uint64]*privatearchive.Entry // Entries of the current batch
+}
+
+// load reads the archive entries of the given blocks, serving their payloads in
+// place of the transaction manager.
+func (p *privateImporter) load(blocks []*types.Block) error {
+	p.store.Reset()
+	p.entries = make(map[uint64]*privatearchive.Entry)
+
+	last := blocks[len(blocks)-1].NumberU64()
+	for {
+		if p.next == nil {
+			entry, err := p.archive.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			p.next = entry
+		}
+		if p.next.Number > last {
+			return nil
+		}
+		p.entries[p.next.Number] = p.next
+		p.store.Add(p.next)
+		p.next = nil
+	}
+}
+
+// verify checks the private state roots of the given imported blocks against
+// the archived ones.
+func (p *privateImporter) verify(blocks []*types.Block) error {
+	for _, block := range blocks {
+		entry := p.entries[block.NumberU64()]
+		if entry == nil {
+			return fmt.Errorf("block %d missing from the private state archive", block.NumberU64())
+		}
+		if entry.Hash != block.Hash() {
+			return fmt.Errorf("private state archive mismatch at block %d: archived %x, imported %x", block.NumberU64(), entry.Hash, block.Hash())
+		}
+		if root := core.GetPrivateStateRoot(p.db, block.Root()); root != entry.PrivateRoot {
+			return fmt.Errorf("private state root mismatch at block %d: have %x, want %x", block.NumberU64(), root, entry.PrivateRoot)
+		}
 	}
 	return nil
 }
@@ -237,8 +327,67 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 	return nil
 }
 
+// ExportPrivateState exports the private state root and the decrypted private
+// payloads of the given blocks into the specified file, encrypted with the given
+// operator key and truncating any data already present in the file. The payloads
+// are retrieved from the transaction manager of the node.
+func ExportPrivateState(blockchain *core.BlockChain, db ethdb.Database, fn string, key []byte, first uint64, last uint64) error {
+	if private.P == nil {
+		return errors.New("exporting the private state requires a transaction manager")
+	}
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	log.Info("Exporting private state", "file", fn, "first", first, "last", last)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	archive, err := privatearchive.NewWriter(fh, key)
+	if err != nil {
+		return err
+	}
+	payloads := 0
+	for nr := first; nr <= last; nr++ {
+		block := blockchain.GetBlockByNumber(nr)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		entry := &privatearchive.Entry{
+			Number:      nr,
+			Hash:        block.Hash(),
+			PrivateRoot: core.GetPrivateStateRoot(db, block.Root()),
+		}
+		for _, tx := range block.Transactions() {
+			if !tx.IsPrivate() {
+				continue
+			}
+			data, err := private.P.Receive(tx.Data())
+			if err != nil {
+				return fmt.Errorf("export failed on #%d: transaction %x: %v", nr, tx.Hash(), err)
+			}
+			// The node isn't party to the transactions without payload
+			if len(data) > 0 {
+				entry.Payloads = append(entry.Payloads, privatearchive.Payload{Hash: tx.Data(), Data: data})
+			}
+		}
+		if err := archive.Write(entry); err != nil {
+			return err
+		}
+		payloads += len(entry.Payloads)
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	log.Info("Exported private state", "file", fn, "blocks", last-first+1, "payloads", payloads)
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
-func ImportPreimages(db *ethdb.LDBDatabase, fn string) error {
+func ImportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
 
 	// Open the file handle and potentially unwrap the gzip stream
@@ -285,7 +434,7 @@ func ImportPreimages(db *ethdb.LDBDatabase, fn string) error {
 
 // ExportPreimages exports all known hash preimages into the specified file,
 // truncating any data already present in the file.
-func ExportPreimages(db *ethdb.LDBDatabase, fn string) error {
+func ExportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Exporting preimages", "file", fn)
 
 	// Open the file handle and potentially wrap with a gzip stream
@@ -0,0 +1,153 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
+	privatearchive "github.com/ethereum/go-ethereum/private/archive"
+)
+
+// stubPrivateTransactionManager serves the given payloads, failing to retrieve
+// any other one as a node not party to the transaction would.
+type stubPrivateTransactionManager struct {
+	payloads map[string][]byte
+}
+
+func (ptm *stubPrivateTransactionManager) Send(data []byte, from string, to []string) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ptm *stubPrivateTransactionManager) SendSignedTx(data []byte, to []string) ([]byte, error) {
+	return nil, errors.New("not supported")
+}
+
+func (ptm *stubPrivateTransactionManager) Receive(data []byte) ([]byte, error) {
+	if payload, ok := ptm.payloads[string(data)]; ok {
+		return payload, nil
+	}
+	return nil, errors.New("not a recipient")
+}
+
+func (ptm *stubPrivateTransactionManager) ReceiveFor(data []byte, to string) ([]byte, error) {
+	return ptm.Receive(data)
+}
+
+// Tests that a chain imported along with its private state archive rebuilds the
+// private state without the transaction manager.
+func TestPrivateChainExportImport(t *testing.T) {
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Generate a chain with a private contract storing 10 at slot 0
+	var (
+		gspec   = &core.Genesis{Config: params.QuorumTestChainConfig}
+		gendb   = ethdb.NewMemDatabase()
+		genesis = gspec.MustCommit(gendb)
+		key, _  = crypto.GenerateKey()
+		hash    = crypto.Keccak512([]byte("payload"))
+		party   = &stubPrivateTransactionManager{payloads: map[string][]byte{string(hash): common.Hex2Bytes("600a60005560016000f3")}}
+	)
+	tx, _ := types.SignTx(types.NewContractCreation(0, new(big.Int), 1000000, new(big.Int), hash), types.HomesteadSigner{}, key)
+	tx.SetPrivate()
+
+	private.P = &stubPrivateTransactionManager{}
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), gendb, 3, func(i int, b *core.BlockGen) {
+		if i == 1 {
+			b.AddTx(tx)
+		}
+	})
+	// Import it on a node party to the contract and export it
+	private.P = party
+
+	srcdb := ethdb.NewMemDatabase()
+	gspec.MustCommit(srcdb)
+	src, _ := core.NewBlockChain(srcdb, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer src.Stop()
+
+	if n, err := src.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	var (
+		chainFile   = filepath.Join(dir, "chain.rlp")
+		archiveFile = filepath.Join(dir, "private.archive")
+		archiveKey  = bytes.Repeat([]byte{0x01}, privatearchive.KeyLength)
+	)
+	if err := ExportChain(src, chainFile); err != nil {
+		t.Fatalf("failed to export chain: %v", err)
+	}
+	if err := ExportPrivateState(src, srcdb, archiveFile, archiveKey, 0, src.CurrentBlock().NumberU64()); err != nil {
+		t.Fatalf("failed to export private state: %v", err)
+	}
+	// Import the chain on a node without transaction manager
+	private.P = nil
+
+	dstdb := ethdb.NewMemDatabase()
+	gspec.MustCommit(dstdb)
+	dst, _ := core.NewBlockChain(dstdb, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer dst.Stop()
+
+	fh, err := os.Open(archiveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+
+	archive, err := privatearchive.NewReader(fh, archiveKey)
+	if err != nil {
+		t.Fatalf("failed to open private state archive: %v", err)
+	}
+	if err := ImportPrivateChain(dst, dstdb, chainFile, archive); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if private.P != nil {
+		t.Errorf("transaction manager not restored")
+	}
+	if dst.CurrentBlock().Hash() != blocks[2].Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", dst.CurrentBlock().Hash(), blocks[2].Hash())
+	}
+	_, privateState, err := dst.State()
+	if err != nil {
+		t.Fatalf("failed to open imported state: %v", err)
+	}
+	contract := crypto.CreateAddress(crypto.PubkeyToAddress(key.PublicKey), 0)
+	if have, want := privateState.GetState(contract, common.Hash{}), common.BigToHash(big.NewInt(10)); have != want {
+		t.Errorf("private storage mismatch: have %x, want %x", have, want)
+	}
+}
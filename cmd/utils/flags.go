@@ -38,7 +38,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -122,6 +124,10 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	DatabaseEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Database engine (" + strings.Join(ethdb.Engines, ", ") + "), defaulting to the engine of an existing database or leveldb",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -175,6 +181,30 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	FreezerThresholdFlag = cli.Uint64Flag{
+		Name:  "freezer.threshold",
+		Usage: "Number of recent blocks kept in the database, older ones being moved to the ancient store (0 = disabled)",
+	}
+	CheckpointFlag = cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: `Trusted block ("<number>:<hash>") whose state is fast synced instead of replaying the chain up to it`,
+	}
+	CheckpointPrivateRootFlag = cli.StringFlag{
+		Name:  "checkpoint.privateroot",
+		Usage: "Private state root of the checkpoint block",
+	}
+	CheckpointPrivatePeerFlag = cli.StringFlag{
+		Name:  "checkpoint.privatepeer",
+		Usage: "Enode URL of the party peer serving the private state of the checkpoint",
+	}
+	CheckpointPrivateBackupFlag = cli.StringFlag{
+		Name:  "checkpoint.privatebackup",
+		Usage: "Chain database holding a backup of the private state of the checkpoint",
+	}
+	CheckpointPrivateClientsFlag = cli.StringFlag{
+		Name:  "checkpoint.privateclients",
+		Usage: "Comma separated enode URLs of the party peers allowed to fetch the private state of a checkpoint from this node",
+	}
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
 		Usage: "Maximum percentage of time allowed for serving LES requests (0-90)",
@@ -414,6 +444,14 @@ var (
 		Name:  "nocompaction",
 		Usage: "Disables db compaction after import",
 	}
+	PrivateArchiveFlag = cli.StringFlag{
+		Name:  "private.archive",
+		Usage: "Encrypted archive of the private state written by export, and read by import in place of the transaction manager",
+	}
+	PrivateArchiveKeyFlag = cli.StringFlag{
+		Name:  "private.archive.key",
+		Usage: "File holding the hex encoded 256-bit key encrypting the private state archive",
+	}
 	// RPC settings
 	RPCEnabledFlag = cli.BoolFlag{
 		Name:  "rpc",
@@ -589,6 +627,21 @@ var (
 		Usage: "Amount of time between raft block creations in milliseconds",
 		Value: 50,
 	}
+	RaftMaxLatencyFlag = cli.IntFlag{
+		Name:  "raftmaxlatency",
+		Usage: "Maximum time in milliseconds a pending transaction waits to be minted when batching adaptively (0 = raftblocktime)",
+		Value: 0,
+	}
+	RaftMaxBatchTxsFlag = cli.IntFlag{
+		Name:  "raftmaxbatchtxs",
+		Usage: "Number of pending transactions which triggers minting a block without waiting for raftmaxlatency (0 = no limit)",
+		Value: 0,
+	}
+	RaftMaxBatchGasFlag = cli.Uint64Flag{
+		Name:  "raftmaxbatchgas",
+		Usage: "Amount of pending gas which triggers minting a block without waiting for raftmaxlatency (0 = no limit)",
+		Value: 0,
+	}
 	RaftJoinExistingFlag = cli.IntFlag{
 		Name:  "raftjoinexisting",
 		Usage: "The raft ID to assume when joining an pre-existing cluster",
@@ -607,12 +660,40 @@ var (
 		Name: "raftdnsenable",
 		Usage: "Enable DNS resolution of peers",
 	}
+	RaftForceNewClusterFlag = cli.BoolFlag{
+		Name:  "raft.forcenewcluster",
+		Usage: "Restart from the existing raft state as the sole member of a new cluster, removing all other members (disaster recovery only)",
+	}
+	RaftTLSFlag = cli.BoolFlag{
+		Name:  "raft.tls",
+		Usage: "Secure the raft transport with mutually authenticated TLS (must be enabled on every node of the cluster)",
+	}
+	RaftTLSCertFlag = cli.StringFlag{
+		Name:  "raft.tlscert",
+		Usage: "PEM certificate for the raft transport (derived from the node key if not set)",
+	}
+	RaftTLSKeyFlag = cli.StringFlag{
+		Name:  "raft.tlskey",
+		Usage: "PEM private key for --raft.tlscert",
+	}
+	RaftTLSCAFlag = cli.StringFlag{
+		Name:  "raft.tlsca",
+		Usage: "PEM CA bundle used to verify the raft TLS certificates of peers",
+	}
 
 	// Quorum
 	EnableNodePermissionFlag = cli.BoolFlag{
 		Name:  "permissioned",
 		Usage: "If enabled, the node will allow only a defined list of nodes to connect",
 	}
+	TenantsFlag = cli.StringFlag{
+		Name:  "tenants",
+		Usage: "Comma separated enclave public keys of the tenants hosted by the node, each with a private state of its own",
+	}
+	TenantTokensFlag = cli.StringFlag{
+		Name:  "tenanttokens",
+		Usage: "File of the API tokens selecting the private state of the tenants over HTTP-RPC, one \"<public key> <token>\" per line, \"operator <token>\" for the default private state",
+	}
 
 	// Istanbul settings
 	IstanbulRequestTimeoutFlag = cli.Uint64Flag{
@@ -625,6 +706,30 @@ var (
 		Usage: "Default minimum difference between two consecutive block's timestamps in seconds",
 		Value: eth.DefaultConfig.Istanbul.BlockPeriod,
 	}
+	IstanbulRoundChangeGrowthFlag = cli.StringFlag{
+		Name:  "istanbul.roundchangegrowth",
+		Usage: "Growth of the Istanbul round change timeout with the round number (exponential, linear or capped)",
+		Value: string(istanbul.ExponentialGrowth),
+	}
+	IstanbulRoundChangeIncrementFlag = cli.Uint64Flag{
+		Name:  "istanbul.roundchangeincrement",
+		Usage: "Increment of the Istanbul round change timeout in milliseconds",
+		Value: istanbul.DefaultRoundChangeTimeoutIncrement,
+	}
+	IstanbulMaxRoundChangeTimeoutFlag = cli.Uint64Flag{
+		Name:  "istanbul.maxroundchangetimeout",
+		Usage: "Maximum Istanbul round change timeout in milliseconds (0 = the genesis setting, or unlimited except for the capped growth)",
+		Value: eth.DefaultConfig.Istanbul.MaxRoundChangeTimeout,
+	}
+	IstanbulRoundChangeResetFlag = cli.StringFlag{
+		Name:  "istanbul.roundchangereset",
+		Usage: "When the Istanbul round change timeout goes back to the request timeout (sequence or gradual)",
+		Value: string(istanbul.SequenceReset),
+	}
+	IstanbulRelayFlag = cli.BoolFlag{
+		Name:  "istanbul.relay",
+		Usage: "Forward Istanbul consensus messages between validators when not validating",
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = cli.BoolFlag{
@@ -953,6 +1058,31 @@ func MakePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// loadTenantTokens reads the API tokens of the tenants from the given file, one
+// "<public key> <token>" per line. The operator token, given as "operator <token>",
+// selects the default private state and is stored as the one of the empty tenant.
+func loadTenantTokens(path string) map[string]string {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		Fatalf("Failed to read tenant tokens file: %v", err)
+	}
+	tokens := make(map[string]string)
+	for i, line := range strings.Split(string(text), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			Fatalf("Invalid tenant token at line %d of %s", i+1, path)
+		}
+		if fields[0] == "operator" {
+			fields[0] = ""
+		}
+		tokens[fields[0]] = fields[1]
+	}
+	return tokens
+}
+
 func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setNodeKey(ctx, cfg)
 	setNAT(ctx, cfg)
@@ -1043,6 +1173,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.DataDir = filepath.Join(node.DefaultDataDir(), "ottoman")
 	}
 
+	if ctx.GlobalIsSet(DatabaseEngineFlag.Name) {
+		cfg.DatabaseEngine = ctx.GlobalString(DatabaseEngineFlag.Name)
+	}
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
 	}
@@ -1134,6 +1267,29 @@ func setIstanbul(ctx *cli.Context, cfg *eth.Config) {
 	if ctx.GlobalIsSet(IstanbulBlockPeriodFlag.Name) {
 		cfg.Istanbul.BlockPeriod = ctx.GlobalUint64(IstanbulBlockPeriodFlag.Name)
 	}
+	if ctx.GlobalIsSet(IstanbulRoundChangeGrowthFlag.Name) {
+		growth, err := istanbul.ParseTimeoutGrowth(ctx.GlobalString(IstanbulRoundChangeGrowthFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", IstanbulRoundChangeGrowthFlag.Name, err)
+		}
+		cfg.Istanbul.RoundChangeTimeoutGrowth = growth
+	}
+	if ctx.GlobalIsSet(IstanbulRoundChangeIncrementFlag.Name) {
+		cfg.Istanbul.RoundChangeTimeoutIncrement = ctx.GlobalUint64(IstanbulRoundChangeIncrementFlag.Name)
+	}
+	if ctx.GlobalIsSet(IstanbulMaxRoundChangeTimeoutFlag.Name) {
+		cfg.Istanbul.MaxRoundChangeTimeout = ctx.GlobalUint64(IstanbulMaxRoundChangeTimeoutFlag.Name)
+	}
+	if ctx.GlobalIsSet(IstanbulRoundChangeResetFlag.Name) {
+		reset, err := istanbul.ParseTimeoutReset(ctx.GlobalString(IstanbulRoundChangeResetFlag.Name))
+		if err != nil {
+			Fatalf("Invalid --%s: %v", IstanbulRoundChangeResetFlag.Name, err)
+		}
+		cfg.Istanbul.RoundChangeTimeoutReset = reset
+	}
+	if ctx.GlobalIsSet(IstanbulRelayFlag.Name) {
+		cfg.Istanbul.Relay = ctx.GlobalBool(IstanbulRelayFlag.Name)
+	}
 }
 
 // checkExclusive verifies that only a single instance of the provided flags was
@@ -1190,6 +1346,43 @@ func SetShhConfig(ctx *cli.Context, stack *node.Node, cfg *whisper.Config) {
 	}
 }
 
+// setCheckpoint creates the trusted checkpoint from the command line flags, which
+// requires fast sync.
+func setCheckpoint(ctx *cli.Context, cfg *eth.Config) {
+	if !ctx.GlobalIsSet(CheckpointFlag.Name) {
+		for _, flag := range []cli.StringFlag{CheckpointPrivateRootFlag, CheckpointPrivatePeerFlag, CheckpointPrivateBackupFlag} {
+			if ctx.GlobalIsSet(flag.Name) {
+				Fatalf("--%s requires --%s", flag.Name, CheckpointFlag.Name)
+			}
+		}
+		return
+	}
+	parts := strings.Split(ctx.GlobalString(CheckpointFlag.Name), ":")
+	if len(parts) != 2 {
+		Fatalf("--%s must be <number>:<hash>", CheckpointFlag.Name)
+	}
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		Fatalf("Invalid --%s block number: %v", CheckpointFlag.Name, err)
+	}
+	checkpoint := &downloader.Checkpoint{
+		Number:        number,
+		Hash:          common.HexToHash(parts[1]),
+		PrivatePeer:   ctx.GlobalString(CheckpointPrivatePeerFlag.Name),
+		PrivateBackup: ctx.GlobalString(CheckpointPrivateBackupFlag.Name),
+	}
+	if ctx.GlobalIsSet(CheckpointPrivateRootFlag.Name) {
+		checkpoint.PrivateRoot = common.HexToHash(ctx.GlobalString(CheckpointPrivateRootFlag.Name))
+	}
+	if cfg.SyncMode != downloader.FastSync {
+		if ctx.GlobalIsSet(SyncModeFlag.Name) {
+			Fatalf("--%s requires fast sync", CheckpointFlag.Name)
+		}
+		cfg.SyncMode = downloader.FastSync
+	}
+	cfg.Checkpoint = checkpoint
+}
+
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	// Avoid conflicting network flags
@@ -1206,6 +1399,10 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
+	setCheckpoint(ctx, cfg)
+	if ctx.GlobalIsSet(CheckpointPrivateClientsFlag.Name) {
+		cfg.PrivateStatePeers = strings.Split(ctx.GlobalString(CheckpointPrivateClientsFlag.Name), ",")
+	}
 	if ctx.GlobalIsSet(LightServFlag.Name) {
 		cfg.LightServ = ctx.GlobalInt(LightServFlag.Name)
 	}
@@ -1225,6 +1422,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
+	if ctx.GlobalIsSet(FreezerThresholdFlag.Name) {
+		cfg.FreezerThreshold = ctx.GlobalUint64(FreezerThresholdFlag.Name)
+	}
 
 
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
@@ -1236,6 +1436,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.GlobalString(DocRootFlag.Name)
 	}
+	if ctx.GlobalIsSet(TenantsFlag.Name) {
+		cfg.Tenants = strings.Split(ctx.GlobalString(TenantsFlag.Name), ",")
+	}
+	if ctx.GlobalIsSet(TenantTokensFlag.Name) {
+		cfg.TenantTokens = loadTenantTokens(ctx.GlobalString(TenantTokensFlag.Name))
+	}
 	if ctx.GlobalIsSet(MinerLegacyExtraDataFlag.Name) {
 		cfg.MinerExtraData = []byte(ctx.GlobalString(MinerLegacyExtraDataFlag.Name))
 	}
@@ -1448,6 +1654,11 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
+	// Serve the blocks a node moved to the freezer, without freezing more
+	chainDb, err = rawdb.NewDatabaseWithFreezer(chainDb, stack.ResolvePath(filepath.Join(name, "ancient")), 0)
+	if err != nil {
+		Fatalf("Could not open ancient database: %v", err)
+	}
 	return chainDb
 }
 
@@ -452,6 +452,17 @@ func (c *Clique) snapshot(chain consensus.ChainReader, number uint64, hash commo
 	return snap, err
 }
 
+// Signers retrieves the authorized signers after the given block, the caller
+// may optionally pass in a batch of parents (ascending order) to avoid looking
+// those up from the database.
+func (c *Clique) Signers(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) ([]common.Address, error) {
+	snap, err := c.snapshot(chain, number, hash, parents)
+	if err != nil {
+		return nil, err
+	}
+	return snap.signers(), nil
+}
+
 // VerifyUncles implements consensus.Engine, always returning an error for any
 // uncles as this consensus mechanism doesn't permit uncles.
 func (c *Clique) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
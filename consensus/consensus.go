@@ -123,6 +123,16 @@ type Handler interface {
 	SetBroadcaster(Broadcaster)
 }
 
+// Overlay should be implemented if the consensus maintains its own connections
+// between the nodes taking part in the consensus
+type Overlay interface {
+	// StartOverlay starts maintaining the connections through the given connector
+	StartOverlay(chain ChainReader, connector PeerConnector) error
+
+	// StopOverlay stops maintaining the connections
+	StopOverlay() error
+}
+
 // PoW is a consensus engine based on proof-of-work.
 type PoW interface {
 	Engine
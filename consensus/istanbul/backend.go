@@ -52,6 +52,9 @@ type Backend interface {
 	// Sign signs input data with the backend's private key
 	Sign([]byte) ([]byte, error)
 
+	// SignCommittedSeal returns the backend's committed seal of the given proposal
+	SignCommittedSeal(proposal Proposal) ([]byte, error)
+
 	// CheckSignature verifies the signature by checking if it's signed by
 	// the given validator
 	CheckSignature(data []byte, addr common.Address, sig []byte) error
@@ -71,5 +74,8 @@ type Backend interface {
 	// HasBadBlock returns whether the block with the hash is a bad block
 	HasBadProposal(hash common.Hash) bool
 
+	// ReportEquivocation hands over the evidence of a validator equivocating
+	ReportEquivocation(evidence *Evidence)
+
 	Close() error
 }
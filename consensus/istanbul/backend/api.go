@@ -18,7 +18,10 @@ package backend
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -42,6 +45,35 @@ func (api *API) NodeAddress() common.Address {
 	return api.istanbul.Address()
 }
 
+// BlsPublicKey returns the BLS public key derived from the node key, followed by
+// its proof of possession, as registered in the blsKeys of the genesis.
+func (api *API) BlsPublicKey() hexutil.Bytes {
+	return blsRegistration(api.istanbul.blsKey)
+}
+
+// BlsRegistration returns the BLS public key derived from the node key and its
+// proof of possession, signed by the node key, for validators to register the
+// key of the node once the network is running through RegisterBlsKey.
+func (api *API) BlsRegistration() (hexutil.Bytes, error) {
+	return signedBLSRegistration(api.istanbul.blsKey, api.istanbul.privateKey)
+}
+
+// RegisterBlsKey makes the validator carry the given BLS key registration, as
+// returned by BlsRegistration, in the blocks it proposes until the key is
+// registered. From the BLS seal fork on, candidates are only voted in, or
+// taken from the validator contract, with a registered BLS key.
+func (api *API) RegisterBlsKey(registration hexutil.Bytes) (common.Address, error) {
+	addr, _, err := recoverBLSRegistration(registration)
+	if err != nil {
+		return common.Address{}, err
+	}
+	api.istanbul.candidatesLock.Lock()
+	defer api.istanbul.candidatesLock.Unlock()
+
+	api.istanbul.blsRegistrations[addr] = common.CopyBytes(registration)
+	return addr, nil
+}
+
 // GetSignersFromBlock returns the signers and minter for a given block number, or the
 // latest block available if none is specified
 func (api *API) GetSignersFromBlock(number *rpc.BlockNumber) (*BlockSigners, error) {
@@ -76,7 +108,7 @@ func (api *API) signers(header *types.Header) (*BlockSigners, error) {
 		return nil, err
 	}
 
-	committers, err := api.istanbul.Signers(header)
+	committers, err := api.istanbul.committers(api.chain, header)
 	if err != nil {
 		return nil, err
 	}
@@ -175,4 +207,50 @@ func (api *API) Discard(address common.Address) {
 	defer api.istanbul.candidatesLock.Unlock()
 
 	delete(api.istanbul.candidates, address)
+	delete(api.istanbul.blsRegistrations, address)
+}
+
+// GetSignerStats returns, for each validator, the number of blocks proposed,
+// the number of committed seals included and missed, and the longest run of
+// consecutive missed blocks between the given blocks, both included. The latest
+// block is assumed if toBlock isn't specified. Statistics are served from an
+// index of 64 block sections where available.
+func (api *API) GetSignerStats(fromBlock rpc.BlockNumber, toBlock *rpc.BlockNumber) (*SignerStats, error) {
+	to := api.chain.CurrentHeader().Number.Uint64()
+	if toBlock != nil && toBlock.Int64() >= 0 {
+		to = uint64(toBlock.Int64())
+	}
+	from := to
+	if fromBlock.Int64() >= 0 {
+		from = uint64(fromBlock.Int64())
+	}
+	if from > to {
+		return nil, errInvalidBlockRange
+	}
+	return api.istanbul.signerStats(api.chain, from, to)
+}
+
+// GetEvidence returns the evidence of validators caught equivocating, oldest
+// first, of the given validator only if specified.
+func (api *API) GetEvidence(validator *common.Address) ([]*Evidence, error) {
+	return api.istanbul.evidence(validator)
+}
+
+// Status returns the state of the current consensus round: the sequence and
+// round, the proposal and locked proposal, the messages received from each
+// validator, and the number of future messages queued per validator.
+func (api *API) Status() (*istanbulCore.Status, error) {
+	api.istanbul.coreMu.RLock()
+	defer api.istanbul.coreMu.RUnlock()
+
+	if !api.istanbul.coreStarted {
+		return nil, istanbul.ErrStoppedEngine
+	}
+	return api.istanbul.core.Status(), nil
+}
+
+// RoundHistory returns the most recent consensus rounds, oldest first, with
+// their timings and the reason each of them ended.
+func (api *API) RoundHistory() []*istanbulCore.RoundInfo {
+	return api.istanbul.core.RoundHistory()
 }
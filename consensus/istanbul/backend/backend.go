@@ -24,15 +24,19 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
 	"github.com/ethereum/go-ethereum/consensus/istanbul/validator"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	lru "github.com/hashicorp/golang-lru"
 )
 
@@ -57,11 +61,21 @@ func New(config *istanbul.Config, privateKey *ecdsa.PrivateKey, db ethdb.Databas
 		commitCh:         make(chan *types.Block, 1),
 		recents:          recents,
 		candidates:       make(map[common.Address]bool),
+		blsRegistrations: make(map[common.Address][]byte),
 		coreStarted:      false,
 		recentMessages:   recentMessages,
 		knownMessages:    knownMessages,
+		announcements:    make(map[common.Address]*announcement),
+		validatorPeers:   make(map[common.Address]*enode.Node),
 	}
+	backend.blsKey = deriveBLSKey(backend)
+	backend.blsKeys = parseBLSKeys(config.BLSPublicKeys)
 	backend.core = istanbulCore.New(backend, backend.config)
+	if config.RaftBlock != nil {
+		// Raft mints and orders the blocks itself, they are processed the way the
+		// Raft service expects them to be
+		backend.raft = ethash.NewFullFaker()
+	}
 	return backend
 }
 
@@ -84,10 +98,16 @@ type backend struct {
 	proposedBlockHash common.Hash
 	sealMu            sync.Mutex
 	coreStarted       bool
+	coreDeferred      bool // the core starts with the first block after the transition
 	coreMu            sync.RWMutex
 
+	clique *clique.Clique   // the engine sealing the blocks before the transition, if any
+	raft   consensus.Engine // the engine processing the blocks minted by Raft after the transition, if any
+
 	// Current list of candidates we are pushing
 	candidates map[common.Address]bool
+	// BLS key registrations to carry in the proposed blocks
+	blsRegistrations map[common.Address][]byte
 	// Protects the signer fields
 	candidatesLock sync.RWMutex
 	// Snapshots for recent block to speed up reorgs
@@ -98,10 +118,34 @@ type backend struct {
 
 	recentMessages *lru.ARCCache // the cache of peer's messages
 	knownMessages  *lru.ARCCache // the cache of self messages
+
+	signerIndexer     *core.ChainIndexer // the index of the validators' signing statistics
+	signerIndexerOnce sync.Once
+
+	blsKey  *bls.SecretKey                    // the BLS key derived from the node key
+	blsKeys map[common.Address]*bls.PublicKey // the BLS keys of the validators registered in the genesis
+
+	// the overlay of connections between validators
+	overlayChain   consensus.ChainReader
+	connector      consensus.PeerConnector
+	overlayQuit    chan struct{}
+	overlayMu      sync.RWMutex
+	announcements  map[common.Address]*announcement // the latest announcement of each validator
+	validatorPeers map[common.Address]*enode.Node   // the validators connected to by the overlay
+	announceMu     sync.Mutex
+
+	evidenceFeed event.Feed // the feed of validators caught equivocating
+	evidenceMu   sync.Mutex // protects the evidence stored in the database
 }
 
 // zekun: HACK
 func (sb *backend) CalcDifficulty(chain consensus.ChainReader, time uint64, parent *types.Header) *big.Int {
+	if c := sb.legacy(new(big.Int).Add(parent.Number, common.Big1)); c != nil {
+		return c.CalcDifficulty(chain, time, parent)
+	}
+	if r := sb.successor(new(big.Int).Add(parent.Number, common.Big1)); r != nil {
+		return r.CalcDifficulty(chain, time, parent)
+	}
 	return new(big.Int)
 }
 
@@ -142,22 +186,11 @@ func (sb *backend) Gossip(valSet istanbul.ValidatorSet, payload []byte) error {
 	if sb.broadcaster != nil && len(targets) > 0 {
 		ps := sb.broadcaster.FindPeers(targets)
 		for addr, p := range ps {
-			ms, ok := sb.recentMessages.Get(addr)
-			var m *lru.ARCCache
-			if ok {
-				m, _ = ms.(*lru.ARCCache)
-				if _, k := m.Get(hash); k {
-					// This peer had this event, skip it
-					continue
-				}
-			} else {
-				m, _ = lru.NewARC(inmemoryMessages)
-			}
-
-			m.Add(hash, true)
-			sb.recentMessages.Add(addr, m)
-
-			go p.Send(istanbulMsg, payload)
+			sb.sendOnce(addr, p, istanbulMsg, hash, payload)
+		}
+		// Reach the validators without a direct link through relaying peers
+		if len(ps) < len(targets) {
+			sb.relay(common.Address{}, istanbulMsg, payload)
 		}
 	}
 	return nil
@@ -174,9 +207,16 @@ func (sb *backend) Commit(proposal istanbul.Proposal, seals [][]byte) error {
 	}
 
 	h := block.Header()
-	// Append seals into extra-data
-	err := writeCommittedSeals(h, seals)
-	if err != nil {
+	// Append seals into extra-data, aggregating BLS ones into a single signature
+	if sb.config.IsBLSSeal(h.Number) {
+		aggregated, err := sb.aggregateCommittedSeals(h, seals)
+		if err != nil {
+			return err
+		}
+		if err := setCommittedSeals(h, aggregated); err != nil {
+			return err
+		}
+	} else if err := writeCommittedSeals(h, seals); err != nil {
 		return err
 	}
 	// update block's header
@@ -317,5 +357,11 @@ func (sb *backend) HasBadProposal(hash common.Hash) bool {
 }
 
 func (sb *backend) Close() error {
+	if sb.clique != nil {
+		sb.clique.Close()
+	}
+	if sb.signerIndexer != nil {
+		return sb.signerIndexer.Close()
+	}
 	return nil
 }
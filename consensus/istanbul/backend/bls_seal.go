@@ -0,0 +1,297 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// From the BLS seal fork block, the committed seals of a header's extra data
+// are replaced by two entries: a bitmap of the validators which committed the
+// block, in the order of the validator set, followed by the aggregation of
+// their BLS signatures of the block hash. The committed seals of COMMIT
+// messages are the sender's address followed by its BLS signature.
+const (
+	blsSealBitmap = iota
+	blsSealSignature
+	blsSealLength
+)
+
+var (
+	// errMissingBLSKey is returned when signing a BLS committed seal without a
+	// registered BLS key.
+	errMissingBLSKey = errors.New("no BLS key registered for validator")
+	// errInvalidBLSRegistration is returned if a block carries a malformed BLS
+	// key registration, or carries one before the BLS seal fork.
+	errInvalidBLSRegistration = errors.New("invalid BLS key registration")
+	// errUnregisteredCandidate is returned if a block votes, from the BLS seal
+	// fork on, to authorize a validator without a registered BLS key.
+	errUnregisteredCandidate = errors.New("candidate without a registered BLS key")
+)
+
+// blsRegistration returns the registration of the BLS key derived from the
+// node key: its public key followed by a proof of possession.
+func blsRegistration(key *bls.SecretKey) []byte {
+	return append(key.PublicKey().Marshal(), key.ProvePossession().Marshal()...)
+}
+
+// signedBLSRegistration returns the registration of the BLS key carried in
+// blocks: the registration signed by the node key, which identifies the
+// validator registering the key.
+func signedBLSRegistration(key *bls.SecretKey, nodeKey *ecdsa.PrivateKey) ([]byte, error) {
+	registration := blsRegistration(key)
+	sig, err := crypto.Sign(crypto.Keccak256(registration), nodeKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(registration, sig...), nil
+}
+
+// parseBLSKey decodes a registered BLS public key, checking its proof of
+// possession, without which the key could be made up from the keys of other
+// validators to forge aggregated seals.
+func parseBLSKey(registration []byte) (*bls.PublicKey, error) {
+	if len(registration) != bls.PublicKeyLength+bls.SignatureLength {
+		return nil, errInvalidBLSRegistration
+	}
+	key, err := bls.UnmarshalPublicKey(registration[:bls.PublicKeyLength])
+	if err != nil {
+		return nil, err
+	}
+	proof, err := bls.UnmarshalSignature(registration[bls.PublicKeyLength:])
+	if err != nil || !key.VerifyPossession(proof) {
+		return nil, errInvalidBLSRegistration
+	}
+	return key, nil
+}
+
+// recoverBLSRegistration decodes a signed registration of a BLS key, returning
+// the validator which signed it along with the key.
+func recoverBLSRegistration(signed []byte) (common.Address, *bls.PublicKey, error) {
+	if len(signed) != bls.PublicKeyLength+bls.SignatureLength+types.IstanbulExtraSeal {
+		return common.Address{}, nil, errInvalidBLSRegistration
+	}
+	registration := signed[:bls.PublicKeyLength+bls.SignatureLength]
+	key, err := parseBLSKey(registration)
+	if err != nil {
+		return common.Address{}, nil, errInvalidBLSRegistration
+	}
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(registration), signed[len(registration):])
+	if err != nil {
+		return common.Address{}, nil, errInvalidBLSRegistration
+	}
+	return crypto.PubkeyToAddress(*pubkey), key, nil
+}
+
+// parseBLSKeys decodes the BLS public keys registered in the genesis, skipping
+// the ones with an invalid proof of possession.
+func parseBLSKeys(registrations map[common.Address][]byte) map[common.Address]*bls.PublicKey {
+	keys := make(map[common.Address]*bls.PublicKey)
+	for addr, registration := range registrations {
+		key, err := parseBLSKey(registration)
+		if err != nil {
+			log.Error("Invalid BLS key registration", "validator", addr, "err", err)
+			continue
+		}
+		keys[addr] = key
+	}
+	return keys
+}
+
+// blsRegistrationsOf returns the validators whose BLS keys are registered by
+// the given extra-data, leaving out invalid registrations.
+func blsRegistrationsOf(extra *types.IstanbulExtra) map[common.Address]bool {
+	registered := make(map[common.Address]bool)
+	for _, registration := range extra.BLSRegistrations {
+		if addr, _, err := recoverBLSRegistration(registration); err == nil {
+			registered[addr] = true
+		}
+	}
+	return registered
+}
+
+// verifyBLSRegistrations checks the BLS key registrations carried by a header,
+// and that, from the BLS seal fork on, it doesn't vote to authorize a validator
+// which would have no BLS key to sign committed seals with.
+func (sb *backend) verifyBLSRegistrations(snap *Snapshot, header *types.Header) error {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	if !sb.config.IsBLSSeal(header.Number) {
+		if len(extra.BLSRegistrations) > 0 {
+			return errInvalidBLSRegistration
+		}
+		return nil
+	}
+	for _, registration := range extra.BLSRegistrations {
+		if _, _, err := recoverBLSRegistration(registration); err != nil {
+			return err
+		}
+	}
+	if sb.config.IsValidatorContract(header.Number) || !bytes.Equal(header.Nonce[:], nonceAuthVote) {
+		return nil
+	}
+	if snap.BLSKeys[header.Coinbase] == nil && !blsRegistrationsOf(extra)[header.Coinbase] {
+		return errUnregisteredCandidate
+	}
+	return nil
+}
+
+// pendingBLSRegistration returns the registration of the given validator's BLS
+// key to carry in a proposed block, nil if it needs none. The second result is
+// false if the key is neither registered nor known to the node.
+func (sb *backend) pendingBLSRegistration(snap *Snapshot, addr common.Address) ([]byte, bool) {
+	if snap.BLSKeys[addr] != nil {
+		return nil, true
+	}
+	registration, ok := sb.blsRegistrations[addr]
+	return registration, ok
+}
+
+// SignCommittedSeal implements istanbul.Backend.SignCommittedSeal
+func (sb *backend) SignCommittedSeal(proposal istanbul.Proposal) ([]byte, error) {
+	seal := istanbulCore.PrepareCommittedSeal(proposal.Hash())
+	if !sb.config.IsBLSSeal(proposal.Number()) {
+		return sb.Sign(seal)
+	}
+	block, ok := proposal.(*types.Block)
+	if !ok {
+		return nil, errInvalidProposal
+	}
+	snap, err := sb.snapshot(sb.chain, block.NumberU64()-1, block.ParentHash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if snap.BLSKeys[sb.address] == nil {
+		return nil, errMissingBLSKey
+	}
+	return append(sb.address.Bytes(), sb.blsKey.Sign(seal).Marshal()...), nil
+}
+
+// aggregateCommittedSeals turns the committed seals of the COMMIT messages of
+// a block into the bitmap and aggregated signature stored in its header. The
+// invalid seals are left out.
+func (sb *backend) aggregateCommittedSeals(header *types.Header, seals [][]byte) ([][]byte, error) {
+	snap, err := sb.snapshot(sb.chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		hash       = istanbulCore.PrepareCommittedSeal(header.Hash())
+		bitmap     = make([]byte, (snap.ValSet.Size()+7)/8)
+		signatures []*bls.Signature
+	)
+	for _, seal := range seals {
+		if len(seal) != common.AddressLength+bls.SignatureLength {
+			continue
+		}
+		addr := common.BytesToAddress(seal[:common.AddressLength])
+		index, _ := snap.ValSet.GetByAddress(addr)
+		key := snap.BLSKeys[addr]
+		if index < 0 || key == nil || bitmap[index/8]&(1<<uint(index%8)) != 0 {
+			continue
+		}
+		signature, err := bls.UnmarshalSignature(seal[common.AddressLength:])
+		if err != nil || !key.Verify(hash, signature) {
+			sb.logger.Warn("Invalid BLS committed seal", "validator", addr, "number", header.Number)
+			continue
+		}
+		bitmap[index/8] |= 1 << uint(index%8)
+		signatures = append(signatures, signature)
+	}
+	if len(signatures) <= snap.ValSet.F() {
+		return nil, errInvalidCommittedSeals
+	}
+	aggregated := make([][]byte, blsSealLength)
+	aggregated[blsSealBitmap] = bitmap
+	aggregated[blsSealSignature] = bls.AggregateSignatures(signatures).Marshal()
+	return aggregated, nil
+}
+
+// blsCommitters returns the validators whose signatures are aggregated in the
+// committed seals of the given header, after checking the aggregated signature.
+func (sb *backend) blsCommitters(snap *Snapshot, header *types.Header, seals [][]byte) ([]common.Address, error) {
+	if len(seals) != blsSealLength {
+		return nil, errInvalidCommittedSeals
+	}
+	validators := snap.ValSet.List()
+	bitmap := seals[blsSealBitmap]
+	if len(bitmap) != (len(validators)+7)/8 {
+		return nil, errInvalidCommittedSeals
+	}
+	var (
+		committers []common.Address
+		keys       []*bls.PublicKey
+	)
+	for i := 0; i < len(bitmap)*8; i++ {
+		if bitmap[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+		if i >= len(validators) {
+			return nil, errInvalidCommittedSeals
+		}
+		addr := validators[i].Address()
+		key := snap.BLSKeys[addr]
+		if key == nil {
+			return nil, errInvalidCommittedSeals
+		}
+		committers = append(committers, addr)
+		keys = append(keys, key)
+	}
+	signature, err := bls.UnmarshalSignature(seals[blsSealSignature])
+	if err != nil {
+		return nil, errInvalidCommittedSeals
+	}
+	if !bls.AggregatePublicKeys(keys).Verify(istanbulCore.PrepareCommittedSeal(header.Hash()), signature) {
+		return nil, errInvalidCommittedSeals
+	}
+	return committers, nil
+}
+
+// committers returns the validators which committed the given block, whether
+// they sealed it with ECDSA or BLS committed seals.
+func (sb *backend) committers(chain consensus.ChainReader, header *types.Header) ([]common.Address, error) {
+	if !sb.config.IsBLSSeal(header.Number) {
+		return sb.Signers(header)
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, err
+	}
+	return sb.blsCommitters(snap, header, extra.CommittedSeal)
+}
+
+// deriveBLSKey derives the node's BLS key from its node key.
+func deriveBLSKey(sb *backend) *bls.SecretKey {
+	return bls.DeriveKey(crypto.FromECDSA(sb.privateKey))
+}
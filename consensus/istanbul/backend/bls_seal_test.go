@@ -0,0 +1,214 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls"
+)
+
+func TestBLSCommittedSeals(t *testing.T) {
+	chain, engine := newBlockChain(4)
+	defer engine.Stop()
+
+	config := *engine.config
+	config.BLSSealBlock = big.NewInt(1)
+	engine.config = &config
+
+	// Register the BLS keys of every validator
+	snap, err := engine.snapshot(chain, 0, chain.Genesis().Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registrations := make(map[common.Address][]byte)
+	secrets := make(map[common.Address]*bls.SecretKey)
+	for i, val := range snap.ValSet.List() {
+		key := bls.DeriveKey([]byte{byte(i)})
+		registrations[val.Address()] = blsRegistration(key)
+		secrets[val.Address()] = key
+	}
+	// A key without a valid proof of possession is ignored
+	forged := blsRegistration(bls.DeriveKey([]byte{4}))
+	copy(forged[bls.PublicKeyLength:], registrations[snap.ValSet.GetByIndex(0).Address()][bls.PublicKeyLength:])
+	registrations[common.Address{}] = forged
+	snap.BLSKeys = parseBLSKeys(registrations)
+	if len(snap.BLSKeys) != 4 {
+		t.Fatalf("registered keys mismatch: have %d, want 4", len(snap.BLSKeys))
+	}
+
+	block := makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	header := block.Header()
+	hash := istanbulCore.PrepareCommittedSeal(header.Hash())
+	var seals [][]byte
+	for _, val := range snap.ValSet.List()[:3] {
+		seals = append(seals, append(val.Address().Bytes(), secrets[val.Address()].Sign(hash).Marshal()...))
+	}
+	// An invalid seal is left out of the aggregation
+	seals = append(seals, append(snap.ValSet.GetByIndex(3).Address().Bytes(), secrets[snap.ValSet.GetByIndex(0).Address()].Sign(hash).Marshal()...))
+
+	if _, err := engine.aggregateCommittedSeals(header, seals[:1]); err != errInvalidCommittedSeals {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidCommittedSeals)
+	}
+	aggregated, err := engine.aggregateCommittedSeals(header, seals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := aggregated[blsSealBitmap], []byte{0x07}; !reflect.DeepEqual(have, want) {
+		t.Errorf("bitmap mismatch: have %x, want %x", have, want)
+	}
+	if err := setCommittedSeals(header, aggregated); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.verifyCommittedSeals(chain, header, nil); err != nil {
+		t.Errorf("error mismatch: have %v, want nil", err)
+	}
+	committers, err := engine.committers(chain, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Address{snap.ValSet.GetByIndex(0).Address(), snap.ValSet.GetByIndex(1).Address(), snap.ValSet.GetByIndex(2).Address()}
+	if !reflect.DeepEqual(committers, want) {
+		t.Errorf("committers mismatch: have %v, want %v", committers, want)
+	}
+
+	// Claiming a validator committed without its signature being aggregated fails
+	aggregated[blsSealBitmap] = []byte{0x0f}
+	if err := setCommittedSeals(header, aggregated); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.verifyCommittedSeals(chain, header, nil); err != errInvalidCommittedSeals {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidCommittedSeals)
+	}
+}
+
+func TestSignCommittedSeal(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	defer engine.Stop()
+
+	config := *engine.config
+	config.BLSSealBlock = big.NewInt(1)
+	engine.config = &config
+
+	block := makeBlockWithoutSeal(chain, engine, chain.Genesis())
+	if _, err := engine.SignCommittedSeal(block); err != errMissingBLSKey {
+		t.Errorf("error mismatch: have %v, want %v", err, errMissingBLSKey)
+	}
+	engine.blsKey = bls.DeriveKey(crypto.FromECDSA(engine.privateKey))
+	snap, err := engine.snapshot(chain, 0, chain.Genesis().Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap.BLSKeys = parseBLSKeys(map[common.Address][]byte{engine.Address(): blsRegistration(engine.blsKey)})
+
+	seal, err := engine.SignCommittedSeal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToAddress(seal[:common.AddressLength]) != engine.Address() {
+		t.Errorf("seal address mismatch")
+	}
+	signature, err := bls.UnmarshalSignature(seal[common.AddressLength:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.BLSKeys[engine.Address()].Verify(istanbulCore.PrepareCommittedSeal(block.Hash()), signature) {
+		t.Errorf("invalid BLS committed seal")
+	}
+}
+
+func TestBLSKeyRegistration(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	defer engine.Stop()
+
+	config := *engine.config
+	config.BLSSealBlock = big.NewInt(1)
+	engine.config = &config
+	api := &API{chain: chain, istanbul: engine}
+
+	candidateKey, _ := crypto.GenerateKey()
+	candidate := crypto.PubkeyToAddress(candidateKey.PublicKey)
+	registration, err := signedBLSRegistration(bls.DeriveKey(crypto.FromECDSA(candidateKey)), candidateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := engine.snapshot(chain, 0, chain.Genesis().Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A candidate without a registered BLS key isn't voted in
+	api.Propose(candidate, true)
+	if header := makeBlockWithoutSeal(chain, engine, chain.Genesis()).Header(); header.Coinbase != (common.Address{}) {
+		t.Errorf("vote for an unregistered candidate: have %x", header.Coinbase)
+	}
+	// A block voting one in without a registration is invalid
+	header := makeBlockWithoutSeal(chain, engine, chain.Genesis()).Header()
+	header.Coinbase = candidate
+	copy(header.Nonce[:], nonceAuthVote)
+	if err := engine.verifyBLSRegistrations(snap, header); err != errUnregisteredCandidate {
+		t.Errorf("error mismatch: have %v, want %v", err, errUnregisteredCandidate)
+	}
+
+	// Once the registration is known, the block voting the candidate in carries it
+	if _, err := api.RegisterBlsKey(registration[1:]); err != errInvalidBLSRegistration {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidBLSRegistration)
+	}
+	if addr, err := api.RegisterBlsKey(registration); err != nil || addr != candidate {
+		t.Fatalf("registration mismatch: have %x, %v, want %x", addr, err, candidate)
+	}
+	header = makeBlockWithoutSeal(chain, engine, chain.Genesis()).Header()
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Coinbase != candidate || !reflect.DeepEqual(extra.BLSRegistrations, [][]byte{registration}) {
+		t.Fatalf("vote mismatch: have %x with %d registrations, want %x with 1", header.Coinbase, len(extra.BLSRegistrations), candidate)
+	}
+	if err := engine.verifyBLSRegistrations(snap, header); err != nil {
+		t.Errorf("error mismatch: have %v, want nil", err)
+	}
+	sig, err := engine.Sign(sigHash(header).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeSeal(header, sig); err != nil {
+		t.Fatal(err)
+	}
+	next, err := snap.apply([]*types.Header{header}, engine.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, v := next.ValSet.GetByAddress(candidate); v == nil {
+		t.Errorf("registered candidate not admitted")
+	}
+	if next.BLSKeys[candidate] == nil {
+		t.Errorf("BLS key not registered")
+	}
+
+	// Registrations aren't allowed before the fork
+	config.BLSSealBlock = big.NewInt(2)
+	if err := engine.verifyBLSRegistrations(snap, header); err != errInvalidBLSRegistration {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidBLSRegistration)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -54,6 +55,9 @@ var (
 	// errUnknownBlock is returned when the list of validators is requested for a block
 	// that is not part of the local blockchain.
 	errUnknownBlock = errors.New("unknown block")
+	// errInvalidBlockRange is returned when statistics are requested for a range of
+	// blocks which ends before it starts.
+	errInvalidBlockRange = errors.New("invalid block range")
 	// errUnauthorized is returned if a header is signed by a non authorized entity.
 	errUnauthorized = errors.New("unauthorized")
 	// errInvalidDifficulty is returned if the difficulty of a block is not 1
@@ -82,6 +86,12 @@ var (
 	errEmptyCommittedSeals = errors.New("zero committed seals")
 	// errMismatchTxhashes is returned if the TxHash in header is mismatch.
 	errMismatchTxhashes = errors.New("mismatch transcations hashes")
+	// errLegacyBlock is returned when the validators are requested for a block
+	// sealed by Clique, before the transition to Istanbul.
+	errLegacyBlock = errors.New("block precedes the transition to Istanbul")
+	// errRaftBlock is returned when a block following the transition to Raft is
+	// to be sealed or its validators requested, as Raft mints these blocks.
+	errRaftBlock = errors.New("block follows the transition to Raft")
 )
 var (
 	defaultDifficulty = big.NewInt(1)
@@ -100,6 +110,12 @@ var (
 // block, which may be different from the header's coinbase if a consensus
 // engine is based on signatures.
 func (sb *backend) Author(header *types.Header) (common.Address, error) {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.Author(header)
+	}
+	if r := sb.successor(header.Number); r != nil {
+		return r.Author(header)
+	}
 	return ecrecover(header)
 }
 
@@ -132,6 +148,12 @@ func (sb *backend) Signers(header *types.Header) ([]common.Address, error) {
 // given engine. Verifying the seal may be done optionally here, or explicitly
 // via the VerifySeal method.
 func (sb *backend) VerifyHeader(chain consensus.ChainReader, header *types.Header, seal bool) error {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.VerifyHeader(chain, header, seal)
+	}
+	if r := sb.successor(header.Number); r != nil {
+		return r.VerifyHeader(chain, header, seal)
+	}
 	return sb.verifyHeader(chain, header, nil)
 }
 
@@ -209,6 +231,9 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	if err := sb.verifySigner(chain, header, parents); err != nil {
 		return err
 	}
+	if err := sb.verifyBLSRegistrations(snap, header); err != nil {
+		return err
+	}
 
 	return sb.verifyCommittedSeals(chain, header, parents)
 }
@@ -221,8 +246,31 @@ func (sb *backend) VerifyHeaders(chain consensus.ChainReader, headers []*types.H
 	abort := make(chan struct{})
 	results := make(chan error, len(headers))
 	go func() {
-		for i, header := range headers {
-			err := sb.verifyHeader(chain, header, headers[:i])
+		// Clique verifies the headers preceding the transition, if any
+		legacy := 0
+		for legacy < len(headers) && sb.legacy(headers[legacy].Number) != nil {
+			legacy++
+		}
+		if legacy > 0 {
+			cliqueAbort, cliqueResults := sb.clique.VerifyHeaders(chain, headers[:legacy], seals[:legacy])
+			for i := 0; i < legacy; i++ {
+				err := <-cliqueResults
+
+				select {
+				case <-abort:
+					close(cliqueAbort)
+					return
+				case results <- err:
+				}
+			}
+		}
+		for i := legacy; i < len(headers); i++ {
+			var err error
+			if r := sb.successor(headers[i].Number); r != nil {
+				err = r.VerifyHeader(chain, headers[i], seals[i])
+			} else {
+				err = sb.verifyHeader(chain, headers[i], headers[:i])
+			}
 
 			select {
 			case <-abort:
@@ -237,6 +285,12 @@ func (sb *backend) VerifyHeaders(chain consensus.ChainReader, headers []*types.H
 // VerifyUncles verifies that the given block's uncles conform to the consensus
 // rules of a given engine.
 func (sb *backend) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
+	if c := sb.legacy(block.Number()); c != nil {
+		return c.VerifyUncles(chain, block)
+	}
+	if r := sb.successor(block.Number()); r != nil {
+		return r.VerifyUncles(chain, block)
+	}
 	if len(block.Uncles()) > 0 {
 		return errInvalidUncleHash
 	}
@@ -296,7 +350,12 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 	validators := snap.ValSet.Copy()
 	// Check whether the committed seals are generated by parent's validators
 	validSeal := 0
-	committers, err := sb.Signers(header)
+	var committers []common.Address
+	if sb.config.IsBLSSeal(header.Number) {
+		committers, err = sb.blsCommitters(snap, header, extra.CommittedSeal)
+	} else {
+		committers, err = sb.Signers(header)
+	}
 	if err != nil {
 		return err
 	}
@@ -319,6 +378,12 @@ func (sb *backend) verifyCommittedSeals(chain consensus.ChainReader, header *typ
 // VerifySeal checks whether the crypto seal on a header is valid according to
 // the consensus rules of the given engine.
 func (sb *backend) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.VerifySeal(chain, header)
+	}
+	if r := sb.successor(header.Number); r != nil {
+		return r.VerifySeal(chain, header)
+	}
 	// get parent header and ensure the signer is in parent's validator set
 	number := header.Number.Uint64()
 	if number == 0 {
@@ -335,6 +400,12 @@ func (sb *backend) VerifySeal(chain consensus.ChainReader, header *types.Header)
 // Prepare initializes the consensus fields of a block header according to the
 // rules of a particular engine. The changes are executed inline.
 func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) error {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.Prepare(chain, header)
+	}
+	if sb.successor(header.Number) != nil {
+		return errRaftBlock
+	}
 	// unused fields, force to set to empty
 	header.Coinbase = common.Address{}
 	header.Nonce = emptyNonce
@@ -355,19 +426,32 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 		return err
 	}
 
-	// get valid candidate list
+	// get valid candidate list, votes are meaningless once the validator
+	// contract manages the validator set. From the BLS seal fork on, only the
+	// validators whose BLS key is registered, or can be, are voted in.
 	sb.candidatesLock.RLock()
 	var addresses []common.Address
 	var authorizes []bool
+	var registrations [][]byte
 	for address, authorize := range sb.candidates {
-		if snap.checkVote(address, authorize) {
-			addresses = append(addresses, address)
-			authorizes = append(authorizes, authorize)
+		if sb.config.IsValidatorContract(header.Number) || !snap.checkVote(address, authorize) {
+			continue
+		}
+		var registration []byte
+		if authorize && sb.config.IsBLSSeal(header.Number) {
+			var ok bool
+			if registration, ok = sb.pendingBLSRegistration(snap, address); !ok {
+				continue
+			}
 		}
+		addresses = append(addresses, address)
+		authorizes = append(authorizes, authorize)
+		registrations = append(registrations, registration)
 	}
 	sb.candidatesLock.RUnlock()
 
 	// pick one of the candidates randomly
+	var registration []byte
 	if len(addresses) > 0 {
 		index := rand.Intn(len(addresses))
 		// add validator voting in coinbase
@@ -377,6 +461,7 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 		} else {
 			copy(header.Nonce[:], nonceDropVote)
 		}
+		registration = registrations[index]
 	}
 
 	// add validators in snapshot to extraData's validators section
@@ -385,6 +470,11 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 		return err
 	}
 	header.Extra = extra
+	if registration != nil {
+		if err := writeBLSRegistrations(header, [][]byte{registration}); err != nil {
+			return err
+		}
+	}
 
 	// set header's timestamp
 	header.Time = new(big.Int).Add(parent.Time, new(big.Int).SetUint64(sb.config.BlockPeriod))
@@ -401,6 +491,18 @@ func (sb *backend) Prepare(chain consensus.ChainReader, header *types.Header) er
 // consensus rules that happen at finalization (e.g. block rewards).
 func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction,
 	uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.Finalize(chain, header, state, txs, uncles, receipts)
+	}
+	if r := sb.successor(header.Number); r != nil {
+		return r.Finalize(chain, header, state, txs, uncles, receipts)
+	}
+	// Announce the validator set managed by the validator contract
+	if sb.config.IsValidatorContract(header.Number) {
+		if err := sb.applyValidatorContract(chain, header, state); err != nil {
+			return nil, err
+		}
+	}
 	// No block rewards in Istanbul, so the state remains as is and uncles are dropped
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	header.UncleHash = nilUncleHash
@@ -412,6 +514,12 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 // Seal generates a new block for the given input block with the local miner's
 // seal place on top.
 func (sb *backend) Seal(chain consensus.ChainReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	if c := sb.legacy(block.Number()); c != nil {
+		return c.Seal(chain, block, results, stop)
+	}
+	if sb.successor(block.Number()) != nil {
+		return errRaftBlock
+	}
 
 	// update the block header timestamp and signature and propose the block to core engine
 	header := block.Header()
@@ -495,19 +603,27 @@ func (sb *backend) updateBlock(parent *types.Header, block *types.Block) (*types
 
 // APIs returns the RPC APIs this consensus engine provides.
 func (sb *backend) APIs(chain consensus.ChainReader) []rpc.API {
-	return []rpc.API{{
+	// The signer statistics are served through the API, so only index them
+	// once the API is wired to the chain.
+	sb.startSignerIndexer(chain)
+
+	apis := []rpc.API{{
 		Namespace: "istanbul",
 		Version:   "1.0",
 		Service:   &API{chain: chain, istanbul: sb},
 		Public:    true,
 	}}
+	if sb.clique != nil {
+		apis = append(apis, sb.clique.APIs(chain)...)
+	}
+	return apis
 }
 
 // Start implements consensus.Istanbul.Start
 func (sb *backend) Start(chain consensus.ChainReader, currentBlock func() *types.Block, hasBadBlock func(hash common.Hash) bool) error {
 	sb.coreMu.Lock()
 	defer sb.coreMu.Unlock()
-	if sb.coreStarted {
+	if sb.coreStarted || sb.coreDeferred {
 		return istanbul.ErrStartedEngine
 	}
 
@@ -522,6 +638,17 @@ func (sb *backend) Start(chain consensus.ChainReader, currentBlock func() *types
 	sb.currentBlock = currentBlock
 	sb.hasBadBlock = hasBadBlock
 
+	// Clique seals the blocks up to the transition, the core is started by
+	// NewChainHead once the next block is an Istanbul one
+	next := new(big.Int).Add(currentBlock().Number(), common.Big1)
+	if sb.legacy(next) != nil {
+		sb.coreDeferred = true
+		return nil
+	}
+	// Raft mints the blocks after the transition, the core isn't needed anymore
+	if sb.successor(next) != nil {
+		return nil
+	}
 	if err := sb.core.Start(); err != nil {
 		return err
 	}
@@ -534,6 +661,10 @@ func (sb *backend) Start(chain consensus.ChainReader, currentBlock func() *types
 func (sb *backend) Stop() error {
 	sb.coreMu.Lock()
 	defer sb.coreMu.Unlock()
+	if sb.coreDeferred {
+		sb.coreDeferred = false
+		return nil
+	}
 	if !sb.coreStarted {
 		return istanbul.ErrStoppedEngine
 	}
@@ -546,6 +677,14 @@ func (sb *backend) Stop() error {
 
 // snapshot retrieves the authorization snapshot at a given point in time.
 func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
+	// There are no Istanbul validators before the transition
+	if sb.legacy(new(big.Int).SetUint64(number+1)) != nil {
+		return nil, errLegacyBlock
+	}
+	// Nor after the transition to Raft
+	if sb.successor(new(big.Int).SetUint64(number+1)) != nil {
+		return nil, errRaftBlock
+	}
 	// Search for a snapshot in memory or on disk for checkpoints
 	var (
 		headers []*types.Header
@@ -561,10 +700,29 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 		if number%checkpointInterval == 0 {
 			if s, err := loadSnapshot(sb.config.Epoch, sb.db, hash); err == nil {
 				log.Trace("Loaded voting snapshot form disk", "number", number, "hash", hash)
+				s.updatePolicy(new(big.Int).SetUint64(number+1), hash, sb.config)
+				if s.BLSKeys == nil {
+					s.BLSKeys = sb.genesisBLSKeys()
+				}
 				snap = s
 				break
 			}
 		}
+		// If we're at the last Clique block, seed the validators with its signers
+		if sb.clique != nil && number+1 == sb.config.TransitionBlock.Uint64() {
+			signers, err := sb.clique.Signers(chain, number, hash, parents)
+			if err != nil {
+				return nil, err
+			}
+			snap = newSnapshot(sb.config.Epoch, number, hash, validator.NewSet(signers, sb.config.ProposerPolicy))
+			snap.BLSKeys = sb.genesisBLSKeys()
+			snap.updatePolicy(new(big.Int).SetUint64(number+1), hash, sb.config)
+			if err := snap.store(sb.db); err != nil {
+				return nil, err
+			}
+			log.Info("Seeded Istanbul validators from Clique signers", "number", number, "hash", hash, "validators", len(signers))
+			break
+		}
 		// If we're at block zero, make a snapshot
 		if number == 0 {
 			genesis := chain.GetHeaderByNumber(0)
@@ -576,6 +734,8 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 				return nil, err
 			}
 			snap = newSnapshot(sb.config.Epoch, 0, genesis.Hash(), validator.NewSet(istanbulExtra.Validators, sb.config.ProposerPolicy))
+			snap.BLSKeys = sb.genesisBLSKeys()
+			snap.updatePolicy(big.NewInt(1), genesis.Hash(), sb.config)
 			if err := snap.store(sb.db); err != nil {
 				return nil, err
 			}
@@ -605,7 +765,7 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	for i := 0; i < len(headers)/2; i++ {
 		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
 	}
-	snap, err := snap.apply(headers)
+	snap, err := snap.apply(headers, sb.config)
 	if err != nil {
 		return nil, err
 	}
@@ -621,6 +781,15 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	return snap, err
 }
 
+// genesisBLSKeys returns a copy of the BLS keys registered in the genesis.
+func (sb *backend) genesisBLSKeys() map[common.Address]*bls.PublicKey {
+	keys := make(map[common.Address]*bls.PublicKey, len(sb.blsKeys))
+	for addr, key := range sb.blsKeys {
+		keys[addr] = key
+	}
+	return keys
+}
+
 // FIXME: Need to update this for Istanbul
 // sigHash returns the hash which is used as input for the Istanbul
 // signing. It is the hash of the entire header apart from the 65 byte signature
@@ -640,6 +809,12 @@ func sigHash(header *types.Header) (hash common.Hash) {
 
 // SealHash returns the hash of a block prior to it being sealed.
 func (sb *backend) SealHash(header *types.Header) common.Hash {
+	if c := sb.legacy(header.Number); c != nil {
+		return c.SealHash(header)
+	}
+	if r := sb.successor(header.Number); r != nil {
+		return r.SealHash(header)
+	}
 	return sigHash(header)
 }
 
@@ -721,7 +896,30 @@ func writeCommittedSeals(h *types.Header, committedSeals [][]byte) error {
 			return errInvalidCommittedSeals
 		}
 	}
+	return setCommittedSeals(h, committedSeals)
+}
+
+// writeBLSRegistrations writes the extra-data field of a block header with the
+// given BLS key registrations.
+func writeBLSRegistrations(h *types.Header, registrations [][]byte) error {
+	istanbulExtra, err := types.ExtractIstanbulExtra(h)
+	if err != nil {
+		return err
+	}
+
+	istanbulExtra.BLSRegistrations = registrations
+	payload, err := rlp.EncodeToBytes(&istanbulExtra)
+	if err != nil {
+		return err
+	}
+
+	h.Extra = append(h.Extra[:types.IstanbulExtraVanity], payload...)
+	return nil
+}
 
+// setCommittedSeals replaces the committed seals in the extra-data field of a
+// block header.
+func setCommittedSeals(h *types.Header, committedSeals [][]byte) error {
 	istanbulExtra, err := types.ExtractIstanbulExtra(h)
 	if err != nil {
 		return err
@@ -0,0 +1,129 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	dbKeyEvidence = "istanbul-evidence"
+	maxEvidence   = 1024 // Maximum number of evidence kept in the database, the oldest are dropped first
+)
+
+// Evidence is the proof of a validator equivocating, as served by the API.
+type Evidence struct {
+	Validator common.Address  `json:"validator"`
+	Sequence  uint64          `json:"sequence"`
+	Round     uint64          `json:"round"`
+	Type      string          `json:"type"`
+	Digests   []common.Hash   `json:"digests"`
+	Messages  []hexutil.Bytes `json:"messages"`
+}
+
+// ReportEquivocation implements istanbul.Backend.ReportEquivocation
+func (sb *backend) ReportEquivocation(evidence *istanbul.Evidence) {
+	known, err := sb.storeEvidence(evidence)
+	if err != nil {
+		sb.logger.Error("Failed to store equivocation evidence", "validator", evidence.Validator, "err", err)
+	}
+	if known {
+		return
+	}
+	// Subscribers may take a while to act on the evidence, which must not hold
+	// up the consensus goroutine reporting it.
+	go sb.evidenceFeed.Send(istanbul.EquivocationEvent{Evidence: evidence})
+}
+
+// SubscribeEquivocationEvent implements istanbul.EquivocationSubscriber.SubscribeEquivocationEvent
+func (sb *backend) SubscribeEquivocationEvent(ch chan<- istanbul.EquivocationEvent) event.Subscription {
+	return sb.evidenceFeed.Subscribe(ch)
+}
+
+// loadEvidence retrieves the stored evidence, oldest first.
+func (sb *backend) loadEvidence() ([]*istanbul.Evidence, error) {
+	blob, err := sb.db.Get([]byte(dbKeyEvidence))
+	if err != nil {
+		// Nothing stored yet
+		return nil, nil
+	}
+	var evidence []*istanbul.Evidence
+	if err := rlp.DecodeBytes(blob, &evidence); err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// storeEvidence appends the given evidence to the stored ones, unless the
+// validator was already caught for the same kind of message in the same view,
+// in which case it reports the evidence as known.
+func (sb *backend) storeEvidence(evidence *istanbul.Evidence) (bool, error) {
+	sb.evidenceMu.Lock()
+	defer sb.evidenceMu.Unlock()
+
+	stored, err := sb.loadEvidence()
+	if err != nil {
+		return false, err
+	}
+	for _, known := range stored {
+		if known.Validator == evidence.Validator && known.Type == evidence.Type && known.View.Cmp(evidence.View) == 0 {
+			return true, nil
+		}
+	}
+	stored = append(stored, evidence)
+	if len(stored) > maxEvidence {
+		stored = stored[len(stored)-maxEvidence:]
+	}
+	blob, err := rlp.EncodeToBytes(stored)
+	if err != nil {
+		return false, err
+	}
+	return false, sb.db.Put([]byte(dbKeyEvidence), blob)
+}
+
+// evidence returns the stored evidence, of the given validator only if not nil.
+func (sb *backend) evidence(validator *common.Address) ([]*Evidence, error) {
+	sb.evidenceMu.Lock()
+	stored, err := sb.loadEvidence()
+	sb.evidenceMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Evidence, 0, len(stored))
+	for _, e := range stored {
+		if validator != nil && e.Validator != *validator {
+			continue
+		}
+		messages := make([]hexutil.Bytes, len(e.Messages))
+		for i, msg := range e.Messages {
+			messages[i] = msg
+		}
+		result = append(result, &Evidence{
+			Validator: e.Validator,
+			Sequence:  e.View.Sequence.Uint64(),
+			Round:     e.View.Round.Uint64(),
+			Type:      e.Type,
+			Digests:   e.Digests,
+			Messages:  messages,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestEvidence(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	config := *istanbul.DefaultConfig
+	b := New(&config, key, ethdb.NewMemDatabase()).(*backend)
+
+	events := make(chan istanbul.EquivocationEvent, 4)
+	sub := b.SubscribeEquivocationEvent(events)
+	defer sub.Unsubscribe()
+
+	newEvidence := func(validator common.Address, round int64) *istanbul.Evidence {
+		return &istanbul.Evidence{
+			Validator: validator,
+			View:      &istanbul.View{Round: big.NewInt(round), Sequence: big.NewInt(10)},
+			Type:      "PREPARE",
+			Digests:   []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")},
+			Messages:  [][]byte{{0x01}, {0x02}},
+		}
+	}
+	var (
+		first  = common.HexToAddress("0x01")
+		second = common.HexToAddress("0x02")
+	)
+	b.ReportEquivocation(newEvidence(first, 0))
+	b.ReportEquivocation(newEvidence(first, 0))
+	b.ReportEquivocation(newEvidence(first, 1))
+	b.ReportEquivocation(newEvidence(second, 0))
+
+	// Events are only sent for new evidence
+	for i := 0; i < 3; i++ {
+		select {
+		case <-events:
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not sent", i)
+		}
+	}
+	select {
+	case evt := <-events:
+		t.Errorf("unexpected event for known evidence: %+v", evt.Evidence)
+	case <-time.After(100 * time.Millisecond):
+	}
+	// The same equivocation is only stored once
+	all, err := b.evidence(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("evidence count mismatch: have %d, want 3", len(all))
+	}
+	if all[1].Validator != first || all[1].Round != 1 || all[1].Sequence != 10 || len(all[1].Messages) != 2 {
+		t.Errorf("evidence mismatch: have %+v", all[1])
+	}
+	filtered, err := b.evidence(&second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 || filtered[0].Validator != second {
+		t.Errorf("filtered evidence mismatch: have %+v", filtered)
+	}
+}
@@ -34,8 +34,12 @@ import (
 )
 
 const (
-	istanbulMsg = 0x11
-	NewBlockMsg = 0x07
+	istanbulMsg         = 0x11
+	istanbulAnnounceMsg = 0x12 // Signed enode of a validator, from istanbul/65
+	NewBlockMsg         = 0x07
+
+	istanbul64 = 64
+	istanbul65 = 65 // Adds validator announcements and relaying
 )
 
 var (
@@ -47,8 +51,8 @@ var (
 func (sb *backend) Protocol() consensus.Protocol {
 	return consensus.Protocol{
 		Name:     "istanbul",
-		Versions: []uint{64},
-		Lengths:  []uint64{18},
+		Versions: []uint{istanbul65, istanbul64},
+		Lengths:  []uint64{19, 18},
 	}
 }
 
@@ -67,8 +71,10 @@ func (sb *backend) HandleMsg(addr common.Address, msg p2p.Msg) (bool, error) {
 	defer sb.coreMu.Unlock()
 
 	if msg.Code == istanbulMsg {
-		if !sb.coreStarted {
-			return true, istanbul.ErrStoppedEngine
+		// Validators reach the others through any peer when lacking direct
+		// links, drop their messages unless relaying
+		if !sb.coreStarted && !sb.config.Relay {
+			return true, nil
 		}
 
 		data, hash, err := sb.decode(msg)
@@ -93,12 +99,24 @@ func (sb *backend) HandleMsg(addr common.Address, msg p2p.Msg) (bool, error) {
 		}
 		sb.knownMessages.Add(hash, true)
 
+		// Relaying nodes forward the messages between validators
+		if !sb.coreStarted {
+			sb.relayMessage(addr, data)
+			return true, nil
+		}
 		go sb.istanbulEventMux.Post(istanbul.MessageEvent{
 			Payload: data,
 		})
 
 		return true, nil
 	}
+	if msg.Code == istanbulAnnounceMsg {
+		var a announcement
+		if err := msg.Decode(&a); err != nil {
+			return true, errDecodeFailed
+		}
+		return true, sb.handleAnnouncement(addr, &a)
+	}
 	if msg.Code == NewBlockMsg && sb.core.IsProposer() { // eth.NewBlockMsg: import cycle
 		// this case is to safeguard the race of similar block which gets propagated from other node while this node is proposing
 		// as p2p.Msg can only be decoded once (get EOF for any subsequence read), we need to make sure the payload is restored after we decode it
@@ -134,8 +152,26 @@ func (sb *backend) SetBroadcaster(broadcaster consensus.Broadcaster) {
 }
 
 func (sb *backend) NewChainHead() error {
-	sb.coreMu.RLock()
-	defer sb.coreMu.RUnlock()
+	sb.coreMu.Lock()
+	defer sb.coreMu.Unlock()
+	if sb.coreDeferred && sb.legacy(new(big.Int).Add(sb.currentBlock().Number(), common.Big1)) == nil {
+		// The next block is the first Istanbul one, take over from Clique
+		if err := sb.core.Start(); err != nil {
+			return err
+		}
+		sb.coreDeferred = false
+		sb.coreStarted = true
+		return nil
+	}
+	if sb.coreStarted && sb.successor(new(big.Int).Add(sb.currentBlock().Number(), common.Big1)) != nil {
+		// The next block is the first Raft one, hand over to the Raft service
+		if err := sb.core.Stop(); err != nil {
+			return err
+		}
+		sb.coreStarted = false
+		sb.logger.Info("Istanbul handed over to Raft", "number", sb.currentBlock().Number())
+		return nil
+	}
 	if !sb.coreStarted {
 		return istanbul.ErrStoppedEngine
 	}
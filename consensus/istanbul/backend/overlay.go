@@ -0,0 +1,305 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	announceInterval = 5 * time.Minute  // Time between two announcements of the enode of a validator
+	connectInterval  = 30 * time.Second // Time between two updates of the connections to the validators
+)
+
+var (
+	// errInvalidAnnouncement is returned if an announcement isn't signed by the
+	// validator it announces, or announces an enode of another node.
+	errInvalidAnnouncement = errors.New("invalid validator announcement")
+	// errStartedOverlay is returned if the overlay is started twice.
+	errStartedOverlay = errors.New("overlay already started")
+)
+
+// announcement is the enode record of a validator, signed by the validator so
+// that it can be relayed by any node.
+type announcement struct {
+	Address   common.Address
+	Enode     string
+	Sequence  uint64 // Creation time of the announcement, newer ones replace older ones
+	Signature []byte
+}
+
+// payloadNoSig returns the signed part of the announcement.
+func (a *announcement) payloadNoSig() ([]byte, error) {
+	return rlp.EncodeToBytes([]interface{}{a.Address, a.Enode, a.Sequence})
+}
+
+// node checks the signature of the announcement, and returns the node it
+// announces.
+func (a *announcement) node() (*enode.Node, error) {
+	payload, err := a.payloadNoSig()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := istanbul.GetSignatureAddress(payload, a.Signature)
+	if err != nil || signer != a.Address {
+		return nil, errInvalidAnnouncement
+	}
+	node, err := enode.ParseV4(a.Enode)
+	if err != nil {
+		return nil, errInvalidAnnouncement
+	}
+	// Validators sign with their node key, which the enode must match
+	if crypto.PubkeyToAddress(*node.Pubkey()) != a.Address {
+		return nil, errInvalidAnnouncement
+	}
+	return node, nil
+}
+
+// StartOverlay implements consensus.Overlay.StartOverlay
+func (sb *backend) StartOverlay(chain consensus.ChainReader, connector consensus.PeerConnector) error {
+	sb.overlayMu.Lock()
+	defer sb.overlayMu.Unlock()
+	if sb.overlayQuit != nil {
+		return errStartedOverlay
+	}
+	sb.overlayChain = chain
+	sb.connector = connector
+	sb.overlayQuit = make(chan struct{})
+
+	go sb.overlayLoop(sb.overlayQuit)
+	return nil
+}
+
+// StopOverlay implements consensus.Overlay.StopOverlay
+func (sb *backend) StopOverlay() error {
+	sb.overlayMu.Lock()
+	defer sb.overlayMu.Unlock()
+	if sb.overlayQuit == nil {
+		return nil
+	}
+	close(sb.overlayQuit)
+	sb.overlayQuit = nil
+	return nil
+}
+
+// overlayLoop periodically announces the local enode while validating, and
+// connects to the other validators of the current validator set.
+func (sb *backend) overlayLoop(quit chan struct{}) {
+	announce := time.NewTicker(announceInterval)
+	defer announce.Stop()
+	connect := time.NewTicker(connectInterval)
+	defer connect.Stop()
+
+	sb.announce()
+	sb.connectValidators()
+	for {
+		select {
+		case <-announce.C:
+			sb.announce()
+		case <-connect.C:
+			sb.connectValidators()
+		case <-quit:
+			return
+		}
+	}
+}
+
+// currentValidators returns the validator set following the current head.
+func (sb *backend) currentValidators() istanbul.ValidatorSet {
+	sb.overlayMu.RLock()
+	chain := sb.overlayChain
+	sb.overlayMu.RUnlock()
+	if chain == nil {
+		return nil
+	}
+	header := chain.CurrentHeader()
+	snap, err := sb.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		sb.logger.Warn("Failed to retrieve the current validators", "err", err)
+		return nil
+	}
+	return snap.ValSet
+}
+
+// announce signs and broadcasts the enode of the local node, if it's a
+// validator.
+func (sb *backend) announce() {
+	valSet := sb.currentValidators()
+	if valSet == nil {
+		return
+	}
+	if _, val := valSet.GetByAddress(sb.address); val == nil {
+		return
+	}
+	a := &announcement{
+		Address:  sb.address,
+		Enode:    sb.connector.Self().String(),
+		Sequence: uint64(now().Unix()),
+	}
+	payload, err := a.payloadNoSig()
+	if err != nil {
+		return
+	}
+	if a.Signature, err = sb.Sign(payload); err != nil {
+		sb.logger.Error("Failed to sign the validator announcement", "err", err)
+		return
+	}
+	sb.storeAnnouncement(a)
+	sb.relay(common.Address{}, istanbulAnnounceMsg, a)
+}
+
+// storeAnnouncement keeps the given announcement if it's newer than the known
+// one of the validator, and reports whether it did.
+func (sb *backend) storeAnnouncement(a *announcement) bool {
+	sb.announceMu.Lock()
+	defer sb.announceMu.Unlock()
+
+	if known, ok := sb.announcements[a.Address]; ok && known.Sequence >= a.Sequence {
+		return false
+	}
+	sb.announcements[a.Address] = a
+	return true
+}
+
+// handleAnnouncement checks and stores an announcement received from a peer,
+// and relays it further if it's new.
+func (sb *backend) handleAnnouncement(addr common.Address, a *announcement) error {
+	if _, err := a.node(); err != nil {
+		return err
+	}
+	// Announcements of nodes which aren't validators are dropped, they may
+	// have been removed from the validator set in the meantime.
+	valSet := sb.currentValidators()
+	if valSet == nil {
+		return nil
+	}
+	if _, val := valSet.GetByAddress(a.Address); val == nil {
+		return nil
+	}
+	if sb.storeAnnouncement(a) {
+		sb.relay(addr, istanbulAnnounceMsg, a)
+	}
+	return nil
+}
+
+// connectValidators keeps direct connections to every announced validator of
+// the current validator set, and drops the connections to former validators.
+func (sb *backend) connectValidators() {
+	valSet := sb.currentValidators()
+	if valSet == nil {
+		return
+	}
+	validating := false
+	if _, val := valSet.GetByAddress(sb.address); val != nil {
+		validating = true
+	}
+
+	sb.announceMu.Lock()
+	defer sb.announceMu.Unlock()
+	for addr, node := range sb.validatorPeers {
+		if _, val := valSet.GetByAddress(addr); val == nil || !validating {
+			sb.logger.Debug("Disconnecting from former validator", "validator", addr)
+			sb.connector.RemovePeer(node)
+			delete(sb.validatorPeers, addr)
+		}
+	}
+	if !validating {
+		return
+	}
+	for _, val := range valSet.List() {
+		a, ok := sb.announcements[val.Address()]
+		if !ok || val.Address() == sb.address {
+			continue
+		}
+		node, err := a.node()
+		if err != nil {
+			continue
+		}
+		if known, ok := sb.validatorPeers[val.Address()]; ok {
+			if known.String() == node.String() {
+				continue
+			}
+			sb.connector.RemovePeer(known)
+		}
+		sb.logger.Debug("Connecting to validator", "validator", val.Address(), "enode", a.Enode)
+		sb.connector.AddPeer(node)
+		sb.validatorPeers[val.Address()] = node
+	}
+}
+
+// relayMessage forwards a consensus message signed by a validator to the peers
+// which haven't seen it yet, on behalf of validators lacking a direct link.
+func (sb *backend) relayMessage(addr common.Address, payload []byte) {
+	sender, err := istanbulCore.MessageSender(payload)
+	if err != nil {
+		sb.logger.Debug("Dropping invalid consensus message", "peer", addr, "err", err)
+		return
+	}
+	valSet := sb.currentValidators()
+	if valSet == nil {
+		return
+	}
+	if _, val := valSet.GetByAddress(sender); val == nil {
+		sb.logger.Debug("Dropping consensus message from non-validator", "peer", addr, "sender", sender)
+		return
+	}
+	sb.relay(addr, istanbulMsg, payload)
+}
+
+// relay sends a message to every peer speaking the overlay protocol, except
+// the one it's received from and the ones which have already seen it.
+func (sb *backend) relay(from common.Address, code uint64, data interface{}) {
+	if sb.broadcaster == nil {
+		return
+	}
+	hash := istanbul.RLPHash(data)
+	for addr, p := range sb.broadcaster.Peers() {
+		if addr == from || p.Version() < istanbul65 {
+			continue
+		}
+		sb.sendOnce(addr, p, code, hash, data)
+	}
+}
+
+// sendOnce sends a message to a peer, unless the peer is known to have it.
+func (sb *backend) sendOnce(addr common.Address, p consensus.Peer, code uint64, hash common.Hash, data interface{}) {
+	ms, ok := sb.recentMessages.Get(addr)
+	var m *lru.ARCCache
+	if ok {
+		m, _ = ms.(*lru.ARCCache)
+		if _, k := m.Get(hash); k {
+			// This peer had this event, skip it
+			return
+		}
+	} else {
+		m, _ = lru.NewARC(inmemoryMessages)
+	}
+	m.Add(hash, true)
+	sb.recentMessages.Add(addr, m)
+
+	go p.Send(code, data)
+}
@@ -0,0 +1,225 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+type testConnector struct {
+	self    *enode.Node
+	peers   map[enode.ID]bool
+	removed map[enode.ID]bool
+}
+
+func (c *testConnector) Self() *enode.Node        { return c.self }
+func (c *testConnector) AddPeer(node *enode.Node) { c.peers[node.ID()] = true }
+func (c *testConnector) RemovePeer(node *enode.Node) {
+	delete(c.peers, node.ID())
+	c.removed[node.ID()] = true
+}
+
+type testPeer struct {
+	version int
+	mu      sync.Mutex
+	sent    []uint64
+}
+
+func (p *testPeer) Send(msgcode uint64, data interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, msgcode)
+	return nil
+}
+
+func (p *testPeer) Version() int { return p.version }
+
+func (p *testPeer) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sent)
+}
+
+type testBroadcaster struct {
+	peers map[common.Address]consensus.Peer
+}
+
+func (b *testBroadcaster) Enqueue(id string, block *types.Block) {}
+
+func (b *testBroadcaster) FindPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	m := make(map[common.Address]consensus.Peer)
+	for addr, p := range b.peers {
+		if targets[addr] {
+			m[addr] = p
+		}
+	}
+	return m
+}
+
+func (b *testBroadcaster) Peers() map[common.Address]consensus.Peer {
+	return b.peers
+}
+
+func newTestNode(key *ecdsa.PrivateKey) *enode.Node {
+	return enode.NewV4(&key.PublicKey, net.ParseIP("127.0.0.1"), 30303, 30303, 0)
+}
+
+func signAnnouncement(key *ecdsa.PrivateKey, node *enode.Node, sequence uint64) *announcement {
+	a := &announcement{
+		Address:  crypto.PubkeyToAddress(key.PublicKey),
+		Enode:    node.String(),
+		Sequence: sequence,
+	}
+	payload, _ := a.payloadNoSig()
+	a.Signature, _ = crypto.Sign(crypto.Keccak256(payload), key)
+	return a
+}
+
+// newOverlayBackend returns the backend of the first of n validators, with its
+// overlay set up but not running, and the keys of the validators.
+func newOverlayBackend(n int) (*backend, *testConnector, []*ecdsa.PrivateKey) {
+	genesis, keys := getGenesisAndKeys(n)
+	memDB := ethdb.NewMemDatabase()
+	config := *istanbul.DefaultConfig
+	b, _ := New(&config, keys[0], memDB).(*backend)
+	genesis.MustCommit(memDB)
+	chain, err := core.NewBlockChain(memDB, nil, genesis.Config, b, vm.Config{}, nil)
+	if err != nil {
+		panic(err)
+	}
+	connector := &testConnector{
+		self:    newTestNode(keys[0]),
+		peers:   make(map[enode.ID]bool),
+		removed: make(map[enode.ID]bool),
+	}
+	b.overlayChain = chain
+	b.connector = connector
+	return b, connector, keys
+}
+
+func TestAnnouncement(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	a := signAnnouncement(key, newTestNode(key), 1)
+	if node, err := a.node(); err != nil || node.ID() != newTestNode(key).ID() {
+		t.Errorf("announced node mismatch: have %v, %v", node, err)
+	}
+	// Announcing the enode of another node
+	if _, err := signAnnouncement(key, newTestNode(other), 1).node(); err != errInvalidAnnouncement {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidAnnouncement)
+	}
+	// Tampering with the announcement
+	a.Sequence++
+	if _, err := a.node(); err != errInvalidAnnouncement {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidAnnouncement)
+	}
+}
+
+func TestConnectValidators(t *testing.T) {
+	b, connector, keys := newOverlayBackend(2)
+	validator := newTestNode(keys[1])
+
+	outsider, _ := crypto.GenerateKey()
+	if err := b.handleAnnouncement(common.Address{}, signAnnouncement(outsider, newTestNode(outsider), 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.handleAnnouncement(common.Address{}, signAnnouncement(keys[1], validator, 2)); err != nil {
+		t.Fatal(err)
+	}
+	forged := signAnnouncement(keys[1], validator, 3)
+	forged.Enode = newTestNode(outsider).String()
+	if err := b.handleAnnouncement(common.Address{}, forged); err != errInvalidAnnouncement {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidAnnouncement)
+	}
+	if len(b.announcements) != 1 || b.announcements[crypto.PubkeyToAddress(keys[1].PublicKey)].Sequence != 2 {
+		t.Fatalf("announcements mismatch: have %v", b.announcements)
+	}
+	// Older announcements don't replace newer ones
+	if b.storeAnnouncement(signAnnouncement(keys[1], validator, 1)) {
+		t.Errorf("older announcement stored")
+	}
+
+	b.connectValidators()
+	if len(connector.peers) != 1 || !connector.peers[validator.ID()] {
+		t.Errorf("connected peers mismatch: have %v, want %v", connector.peers, validator.ID())
+	}
+
+	// Leaving the validator set drops the connections
+	b.address = crypto.PubkeyToAddress(outsider.PublicKey)
+	b.connectValidators()
+	if len(connector.peers) != 0 || !connector.removed[validator.ID()] {
+		t.Errorf("connected peers mismatch: have %v, want none", connector.peers)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	b, _, keys := newOverlayBackend(2)
+	var (
+		validator = crypto.PubkeyToAddress(keys[1].PublicKey)
+		relay     = common.HexToAddress("0x01")
+		legacy    = common.HexToAddress("0x02")
+		peers     = map[common.Address]consensus.Peer{
+			relay:  &testPeer{version: istanbul65},
+			legacy: &testPeer{version: istanbul64},
+		}
+	)
+	b.SetBroadcaster(&testBroadcaster{peers: peers})
+
+	// Without a direct link, messages go through the relaying peers
+	b.Gossip(currentValidators(t, b), []byte("message"))
+	time.Sleep(10 * time.Millisecond)
+	if have := peers[relay].(*testPeer).count(); have != 1 {
+		t.Errorf("relay messages mismatch: have %d, want 1", have)
+	}
+	if have := peers[legacy].(*testPeer).count(); have != 0 {
+		t.Errorf("legacy peer messages mismatch: have %d, want 0", have)
+	}
+
+	// Once linked, messages are sent to the validator only
+	peers[validator] = &testPeer{version: istanbul65}
+	b.Gossip(currentValidators(t, b), []byte("another message"))
+	time.Sleep(10 * time.Millisecond)
+	if have := peers[validator].(*testPeer).count(); have != 1 {
+		t.Errorf("validator messages mismatch: have %d, want 1", have)
+	}
+	if have := peers[relay].(*testPeer).count(); have != 1 {
+		t.Errorf("relay messages mismatch: have %d, want 1", have)
+	}
+}
+
+func currentValidators(t *testing.T, b *backend) istanbul.ValidatorSet {
+	valSet := b.currentValidators()
+	if valSet == nil {
+		t.Fatal("no validator set")
+	}
+	return valSet
+}
@@ -0,0 +1,371 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// signerStatsSectionSize is the number of blocks the signer statistics are
+	// indexed by. It also bounds how far the signer metrics lag behind the head.
+	signerStatsSectionSize = 64
+
+	// signerStatsThrottling is the time to wait between processing two sections.
+	signerStatsThrottling = 100 * time.Millisecond
+)
+
+var (
+	// signerStatsPrefix is the database prefix of the signer statistics index.
+	signerStatsPrefix = []byte("istanbul-signers-")
+	// signerStatsSectionPrefix prefixes the signer statistics of a section
+	// within the index, followed by the section number and head hash.
+	signerStatsSectionPrefix = []byte("s")
+)
+
+// SignerStats is the activity of the validators over a range of blocks, as
+// recorded by the proposer and committed seals of the blocks.
+type SignerStats struct {
+	FromBlock uint64            `json:"fromBlock"`
+	ToBlock   uint64            `json:"toBlock"`
+	Signers   []*SignerActivity `json:"signers"`
+}
+
+// SignerActivity is the activity of a validator over a range of blocks.
+type SignerActivity struct {
+	Address        common.Address `json:"address"`
+	Proposed       uint64         `json:"proposed"`       // Number of blocks proposed
+	Sealed         uint64         `json:"sealed"`         // Number of committed seals included in blocks
+	Missed         uint64         `json:"missed"`         // Number of blocks without a committed seal while a validator
+	LongestAbsence uint64         `json:"longestAbsence"` // Longest run of consecutive missed blocks
+}
+
+// signerRecord is the activity of a validator over a range of blocks, along
+// with the runs of missed blocks at the edges of the range needed to merge it
+// with the adjacent ones.
+type signerRecord struct {
+	Address  common.Address
+	Proposed uint64
+	Sealed   uint64
+	Missed   uint64
+	Leading  uint64 // Consecutive blocks missed from the start of the range
+	Trailing uint64 // Consecutive blocks missed up to the end of the range
+	Longest  uint64 // Longest run of consecutive blocks missed
+}
+
+// signerActivity accumulates the activity of the validators over a range of
+// consecutive blocks.
+type signerActivity struct {
+	blocks  uint64
+	signers map[common.Address]*signerRecord
+}
+
+func newSignerActivity() *signerActivity {
+	return &signerActivity{signers: make(map[common.Address]*signerRecord)}
+}
+
+func (a *signerActivity) record(addr common.Address) *signerRecord {
+	rec := a.signers[addr]
+	if rec == nil {
+		rec = &signerRecord{Address: addr}
+		a.signers[addr] = rec
+	}
+	return rec
+}
+
+// add appends a block to the range. A block in which a signer isn't a validator
+// interrupts its run of missed blocks.
+func (a *signerActivity) add(author common.Address, validators, committers []common.Address) {
+	sealed := make(map[common.Address]bool)
+	for _, addr := range committers {
+		sealed[addr] = true
+	}
+	active := make(map[common.Address]bool)
+	for _, addr := range validators {
+		active[addr] = true
+
+		rec := a.record(addr)
+		if sealed[addr] {
+			rec.Sealed++
+			rec.Trailing = 0
+			continue
+		}
+		rec.Missed++
+		if rec.Leading == a.blocks {
+			rec.Leading++
+		}
+		rec.Trailing++
+		if rec.Trailing > rec.Longest {
+			rec.Longest = rec.Trailing
+		}
+	}
+	for addr, rec := range a.signers {
+		if !active[addr] {
+			rec.Trailing = 0
+		}
+	}
+	a.record(author).Proposed++
+	a.blocks++
+}
+
+// merge appends the range of blocks of b, which must follow the ones of a.
+func (a *signerActivity) merge(b *signerActivity) {
+	for addr, rb := range b.signers {
+		ra := a.record(addr)
+
+		leading := ra.Leading
+		if ra.Leading == a.blocks {
+			leading += rb.Leading
+		}
+		trailing := rb.Trailing
+		if rb.Trailing == b.blocks {
+			trailing += ra.Trailing
+		}
+		longest := ra.Longest
+		if rb.Longest > longest {
+			longest = rb.Longest
+		}
+		if ra.Trailing+rb.Leading > longest {
+			longest = ra.Trailing + rb.Leading
+		}
+		ra.Proposed += rb.Proposed
+		ra.Sealed += rb.Sealed
+		ra.Missed += rb.Missed
+		ra.Leading, ra.Trailing, ra.Longest = leading, trailing, longest
+	}
+	for addr, ra := range a.signers {
+		if _, ok := b.signers[addr]; !ok && b.blocks > 0 {
+			ra.Trailing = 0
+		}
+	}
+	a.blocks += b.blocks
+}
+
+// stats returns the activity of the validators, sorted by address.
+func (a *signerActivity) stats(from, to uint64) *SignerStats {
+	stats := &SignerStats{FromBlock: from, ToBlock: to, Signers: []*SignerActivity{}}
+	for _, rec := range a.signers {
+		stats.Signers = append(stats.Signers, &SignerActivity{
+			Address:        rec.Address,
+			Proposed:       rec.Proposed,
+			Sealed:         rec.Sealed,
+			Missed:         rec.Missed,
+			LongestAbsence: rec.Longest,
+		})
+	}
+	sort.Slice(stats.Signers, func(i, j int) bool {
+		return bytes.Compare(stats.Signers[i].Address[:], stats.Signers[j].Address[:]) < 0
+	})
+	return stats
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (a *signerActivity) EncodeRLP(w io.Writer) error {
+	records := make([]*signerRecord, 0, len(a.signers))
+	for _, rec := range a.signers {
+		records = append(records, rec)
+	}
+	return rlp.Encode(w, []interface{}{a.blocks, records})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (a *signerActivity) DecodeRLP(s *rlp.Stream) error {
+	var enc struct {
+		Blocks  uint64
+		Records []*signerRecord
+	}
+	if err := s.Decode(&enc); err != nil {
+		return err
+	}
+	a.blocks, a.signers = enc.Blocks, make(map[common.Address]*signerRecord)
+	for _, rec := range enc.Records {
+		a.signers[rec.Address] = rec
+	}
+	return nil
+}
+
+// blockActivity returns the proposer, the validators and the signers of the
+// committed seals of the given block.
+func (sb *backend) blockActivity(chain consensus.ChainReader, header *types.Header) (common.Address, []common.Address, []common.Address, error) {
+	author, err := sb.Author(header)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	committers, err := sb.committers(chain, header)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return author, snap.validators(), committers, nil
+}
+
+// signerStats returns the activity of the validators between the given blocks,
+// both included, using the indexed sections where available.
+func (sb *backend) signerStats(chain consensus.ChainReader, from, to uint64) (*SignerStats, error) {
+	if from == 0 {
+		from = 1 // the genesis block has no proposer nor seals
+	}
+	activity := newSignerActivity()
+	for number := from; number <= to; {
+		if section := number / signerStatsSectionSize; number%signerStatsSectionSize == 0 && number+signerStatsSectionSize-1 <= to {
+			if indexed := sb.readSignerSection(section); indexed != nil {
+				activity.merge(indexed)
+				number += signerStatsSectionSize
+				continue
+			}
+		}
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		author, validators, committers, err := sb.blockActivity(chain, header)
+		if err != nil {
+			return nil, err
+		}
+		activity.add(author, validators, committers)
+		number++
+	}
+	return activity.stats(from, to), nil
+}
+
+// startSignerIndexer starts indexing the signer statistics of the given chain
+// in the background, if it can be followed.
+func (sb *backend) startSignerIndexer(chain consensus.ChainReader) {
+	sb.signerIndexerOnce.Do(func() {
+		indexerChain, ok := chain.(core.ChainIndexerChain)
+		if !ok {
+			return
+		}
+		backend := &signerIndexer{
+			backend: sb,
+			chain:   chain,
+			db:      ethdb.NewTable(sb.db, string(signerStatsPrefix)),
+			absence: make(map[common.Address]uint64),
+		}
+		sb.signerIndexer = core.NewChainIndexer(sb.db, backend.db, backend, signerStatsSectionSize, 0, signerStatsThrottling, "istanbul-signers")
+		sb.signerIndexer.Start(indexerChain)
+	})
+}
+
+// readSignerSection returns the indexed signer statistics of the given section,
+// or nil if the section isn't indexed yet.
+func (sb *backend) readSignerSection(section uint64) *signerActivity {
+	if sb.signerIndexer == nil {
+		return nil
+	}
+	if sections, _, _ := sb.signerIndexer.Sections(); section >= sections {
+		return nil
+	}
+	head := rawdb.ReadCanonicalHash(sb.db, (section+1)*signerStatsSectionSize-1)
+	blob, err := ethdb.NewTable(sb.db, string(signerStatsPrefix)).Get(signerSectionKey(section, head))
+	if err != nil {
+		return nil
+	}
+	activity := newSignerActivity()
+	if err := rlp.DecodeBytes(blob, activity); err != nil {
+		sb.logger.Error("Invalid signer statistics", "section", section, "err", err)
+		return nil
+	}
+	return activity
+}
+
+// signerSectionKey = signerStatsSectionPrefix + section (uint64 big endian) + head hash
+func signerSectionKey(section uint64, head common.Hash) []byte {
+	key := append(append([]byte{}, signerStatsSectionPrefix...), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(signerStatsSectionPrefix):], section)
+	return append(key, head.Bytes()...)
+}
+
+// signerIndexer implements core.ChainIndexerBackend, indexing the activity of
+// the validators and reporting it through metrics as blocks are processed.
+type signerIndexer struct {
+	backend  *backend
+	chain    consensus.ChainReader
+	db       ethdb.Database
+	section  uint64
+	head     common.Hash
+	activity *signerActivity
+	absence  map[common.Address]uint64 // Consecutive blocks missed by each validator
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new section.
+func (i *signerIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	i.section, i.head, i.activity = section, common.Hash{}, newSignerActivity()
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding a block to the section.
+func (i *signerIndexer) Process(ctx context.Context, header *types.Header) error {
+	i.head = header.Hash()
+	if header.Number.Sign() == 0 {
+		return nil
+	}
+	author, validators, committers, err := i.backend.blockActivity(i.chain, header)
+	if err != nil {
+		return err
+	}
+	i.activity.add(author, validators, committers)
+
+	sealed := make(map[common.Address]bool)
+	for _, addr := range committers {
+		sealed[addr] = true
+	}
+	metrics.GetOrRegisterCounter(signerMetric(author, "proposed"), nil).Inc(1)
+	for _, addr := range validators {
+		if sealed[addr] {
+			metrics.GetOrRegisterCounter(signerMetric(addr, "sealed"), nil).Inc(1)
+			i.absence[addr] = 0
+		} else {
+			metrics.GetOrRegisterCounter(signerMetric(addr, "missed"), nil).Inc(1)
+			i.absence[addr]++
+		}
+		metrics.GetOrRegisterGauge(signerMetric(addr, "absence"), nil).Update(int64(i.absence[addr]))
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing the section out into the
+// database.
+func (i *signerIndexer) Commit() error {
+	blob, err := rlp.EncodeToBytes(i.activity)
+	if err != nil {
+		return err
+	}
+	return i.db.Put(signerSectionKey(i.section, i.head), blob)
+}
+
+func signerMetric(addr common.Address, name string) string {
+	return fmt.Sprintf("consensus/istanbul/signers/%x/%s", addr, name)
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestSignerActivity(t *testing.T) {
+	var (
+		a = common.HexToAddress("0x0a")
+		b = common.HexToAddress("0x0b")
+		c = common.HexToAddress("0x0c")
+	)
+	// Blocks proposed by a, with c offline for a while and only a validator
+	// from the third block on.
+	type block struct {
+		validators []common.Address
+		committers []common.Address
+	}
+	blocks := []block{
+		{[]common.Address{a, b}, []common.Address{a}},
+		{[]common.Address{a, b}, []common.Address{a}},
+		{[]common.Address{a, b, c}, []common.Address{a, b}},
+		{[]common.Address{a, b, c}, []common.Address{a, b}},
+		{[]common.Address{a, b, c}, []common.Address{a, b}},
+		{[]common.Address{a, b, c}, []common.Address{a, b, c}},
+		{[]common.Address{a, b, c}, []common.Address{a, b}},
+		{[]common.Address{a, b, c}, []common.Address{a, b}},
+	}
+	whole := newSignerActivity()
+	for _, block := range blocks {
+		whole.add(a, block.validators, block.committers)
+	}
+	want := []*SignerActivity{
+		{Address: a, Proposed: 8, Sealed: 8},
+		{Address: b, Sealed: 6, Missed: 2, LongestAbsence: 2},
+		{Address: c, Sealed: 1, Missed: 5, LongestAbsence: 3},
+	}
+	if have := whole.stats(1, 8).Signers; !reflect.DeepEqual(have, want) {
+		t.Fatalf("stats mismatch: have %v, want %v", have, want)
+	}
+
+	// Merging the activity of any split of the range gives the same statistics.
+	for split := 0; split <= len(blocks); split++ {
+		first, second := newSignerActivity(), newSignerActivity()
+		for _, block := range blocks[:split] {
+			first.add(a, block.validators, block.committers)
+		}
+		for _, block := range blocks[split:] {
+			second.add(a, block.validators, block.committers)
+		}
+		// Round trip the second half through the index encoding.
+		blob, err := rlp.EncodeToBytes(second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := newSignerActivity()
+		if err := rlp.DecodeBytes(blob, decoded); err != nil {
+			t.Fatal(err)
+		}
+		first.merge(decoded)
+		if have := first.stats(1, 8).Signers; !reflect.DeepEqual(have, want) {
+			t.Errorf("split %d: stats mismatch: have %v, want %v", split, have, want)
+		}
+	}
+}
+
+func TestSignerStats(t *testing.T) {
+	chain, engine := newBlockChain(1)
+	defer engine.Stop()
+
+	parent := chain.Genesis()
+	for i := 0; i < 2; i++ {
+		block := makeBlock(chain, engine, parent)
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to import block %d: %v", i+1, err)
+		}
+		parent = block
+	}
+	stats, err := engine.signerStats(chain, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SignerStats{
+		FromBlock: 1,
+		ToBlock:   2,
+		Signers:   []*SignerActivity{{Address: engine.Address(), Proposed: 2, Sealed: 2}},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats mismatch: have %+v, want %+v", stats, want)
+	}
+	if _, err := engine.signerStats(chain, 1, 3); err != errUnknownBlock {
+		t.Errorf("error mismatch: have %v, want %v", err, errUnknownBlock)
+	}
+}
@@ -19,11 +19,14 @@ package backend
 import (
 	"bytes"
 	"encoding/json"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/consensus/istanbul/validator"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls"
 	"github.com/ethereum/go-ethereum/ethdb"
 )
 
@@ -56,6 +59,11 @@ type Snapshot struct {
 	Votes  []*Vote                  // List of votes cast in chronological order
 	Tally  map[common.Address]Tally // Current vote tally to avoid recalculating
 	ValSet istanbul.ValidatorSet    // Set of authorized validators at this moment
+
+	Proposer common.Address            // Proposer of the block where the snapshot was created
+	Missed   map[common.Address]uint64 // Last block each validator missed its turn to propose in
+
+	BLSKeys map[common.Address]*bls.PublicKey // BLS keys registered in the genesis or by the blocks so far
 }
 
 // newSnapshot create a new snapshot with the specified startup parameters. This
@@ -68,6 +76,9 @@ func newSnapshot(epoch uint64, number uint64, hash common.Hash, valSet istanbul.
 		Hash:   hash,
 		ValSet: valSet,
 		Tally:  make(map[common.Address]Tally),
+		Missed: make(map[common.Address]uint64),
+
+		BLSKeys: make(map[common.Address]*bls.PublicKey),
 	}
 	return snap
 }
@@ -105,11 +116,22 @@ func (s *Snapshot) copy() *Snapshot {
 		ValSet: s.ValSet.Copy(),
 		Votes:  make([]*Vote, len(s.Votes)),
 		Tally:  make(map[common.Address]Tally),
+
+		Proposer: s.Proposer,
+		Missed:   make(map[common.Address]uint64),
+
+		BLSKeys: make(map[common.Address]*bls.PublicKey, len(s.BLSKeys)),
 	}
 
 	for address, tally := range s.Tally {
 		cpy.Tally[address] = tally
 	}
+	for address, number := range s.Missed {
+		cpy.Missed[address] = number
+	}
+	for address, key := range s.BLSKeys {
+		cpy.BLSKeys[address] = key
+	}
 	copy(cpy.Votes, s.Votes)
 
 	return cpy
@@ -159,8 +181,9 @@ func (s *Snapshot) uncast(address common.Address, authorize bool) bool {
 }
 
 // apply creates a new authorization snapshot by applying the given headers to
-// the original one.
-func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
+// the original one. Once the validator contract manages the validator set, the
+// headers announce the validators for the following block instead of votes.
+func (s *Snapshot) apply(headers []*types.Header, config *istanbul.Config) (*Snapshot, error) {
 	// Allow passing in no headers for cleaner code
 	if len(headers) == 0 {
 		return s, nil
@@ -178,6 +201,8 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	snap := s.copy()
 
 	for _, header := range headers {
+		snap.updatePolicy(header.Number, header.ParentHash, config)
+
 		// Remove any votes on checkpoint blocks
 		number := header.Number.Uint64()
 		if number%s.Epoch == 0 {
@@ -192,6 +217,20 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		if _, v := snap.ValSet.GetByAddress(validator); v == nil {
 			return nil, errUnauthorized
 		}
+		if snap.ValSet.Policy() == istanbul.LivenessAware {
+			snap.trackMissed(number, validator)
+		}
+		snap.Proposer = validator
+
+		if err := snap.registerBLSKeys(header); err != nil {
+			return nil, err
+		}
+		if config.IsValidatorContract(header.Number) {
+			if err := snap.announce(header, config); err != nil {
+				return nil, err
+			}
+			continue
+		}
 
 		// Header authorized, discard any previous votes from the validator
 		for i, vote := range snap.Votes {
@@ -222,8 +261,10 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				Authorize: authorize,
 			})
 		}
-		// If the vote passed, update the list of validators
-		if tally := snap.Tally[header.Coinbase]; tally.Votes > snap.ValSet.Size()/2 {
+		// If the vote passed, update the list of validators. From the BLS seal
+		// fork on, validators without a registered BLS key aren't admitted.
+		if tally := snap.Tally[header.Coinbase]; tally.Votes > snap.ValSet.Size()/2 &&
+			(!tally.Authorize || !config.IsBLSSeal(header.Number) || snap.BLSKeys[header.Coinbase] != nil) {
 			if tally.Authorize {
 				snap.ValSet.AddValidator(header.Coinbase)
 			} else {
@@ -254,10 +295,89 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	}
 	snap.Number += uint64(len(headers))
 	snap.Hash = headers[len(headers)-1].Hash()
+	snap.updatePolicy(new(big.Int).SetUint64(snap.Number+1), snap.Hash, config)
 
 	return snap, nil
 }
 
+// registerBLSKeys records the BLS keys registered by the header.
+func (s *Snapshot) registerBLSKeys(header *types.Header) error {
+	istanbulExtra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	for _, registration := range istanbulExtra.BLSRegistrations {
+		addr, key, err := recoverBLSRegistration(registration)
+		if err != nil {
+			return err
+		}
+		s.BLSKeys[addr] = key
+	}
+	return nil
+}
+
+// announce replaces the validator set with the one announced in the header by
+// the validator contract, dropping any pending votes.
+func (s *Snapshot) announce(header *types.Header, config *istanbul.Config) error {
+	istanbulExtra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	if len(istanbulExtra.Validators) == 0 {
+		return errEmptyValidatorSet
+	}
+	s.ValSet = validator.NewSet(istanbulExtra.Validators, s.ValSet.Policy())
+	s.ValSet.SetWeights(config.ValidatorWeights)
+	s.Votes = nil
+	s.Tally = make(map[common.Address]Tally)
+	return nil
+}
+
+// updatePolicy prepares the validator set for selecting the proposers of the
+// given block, the child of parent: it switches to the proposer policy configured
+// for the block, and marks the validators which missed their turn to propose
+// within the liveness window as inactive.
+func (s *Snapshot) updatePolicy(number *big.Int, parent common.Hash, config *istanbul.Config) {
+	if policy := config.ProposerPolicyAt(number); policy != s.ValSet.Policy() {
+		s.ValSet = validator.NewSet(s.validators(), policy)
+	}
+	s.ValSet.SetWeights(config.ValidatorWeights)
+	s.ValSet.SetSeed(parent)
+
+	var inactive []common.Address
+	for address, missed := range s.Missed {
+		if missed+config.LivenessWindow < number.Uint64() {
+			delete(s.Missed, address)
+		} else {
+			inactive = append(inactive, address)
+		}
+	}
+	s.ValSet.SetInactive(inactive)
+}
+
+// trackMissed records the validators which missed their turn to propose the
+// given block, by replaying the rounds until the one of its actual proposer.
+func (s *Snapshot) trackMissed(number uint64, proposer common.Address) {
+	selector, ok := validator.ProposerSelector(s.ValSet.Policy())
+	if !ok {
+		return
+	}
+	var missed []common.Address
+	for round := uint64(0); round < uint64(s.ValSet.Size()); round++ {
+		expected := selector(s.ValSet, s.Proposer, round)
+		if expected == nil {
+			return
+		}
+		if expected.Address() == proposer {
+			for _, address := range missed {
+				s.Missed[address] = number
+			}
+			return
+		}
+		missed = append(missed, expected.Address())
+	}
+}
+
 // validators retrieves the list of authorized validators in ascending order.
 func (s *Snapshot) validators() []common.Address {
 	validators := make([]common.Address, 0, s.ValSet.Size())
@@ -284,9 +404,23 @@ type snapshotJSON struct {
 	// for validator set
 	Validators []common.Address        `json:"validators"`
 	Policy     istanbul.ProposerPolicy `json:"policy"`
+
+	// for proposer policies
+	Proposer common.Address            `json:"proposer"`
+	Missed   map[common.Address]uint64 `json:"missed,omitempty"`
+
+	// for BLS committed seals
+	BLSKeys map[common.Address]hexutil.Bytes `json:"blsKeys,omitempty"`
 }
 
 func (s *Snapshot) toJSONStruct() *snapshotJSON {
+	var blsKeys map[common.Address]hexutil.Bytes
+	if len(s.BLSKeys) > 0 {
+		blsKeys = make(map[common.Address]hexutil.Bytes, len(s.BLSKeys))
+		for address, key := range s.BLSKeys {
+			blsKeys[address] = key.Marshal()
+		}
+	}
 	return &snapshotJSON{
 		Epoch:      s.Epoch,
 		Number:     s.Number,
@@ -295,6 +429,9 @@ func (s *Snapshot) toJSONStruct() *snapshotJSON {
 		Tally:      s.Tally,
 		Validators: s.validators(),
 		Policy:     s.ValSet.Policy(),
+		Proposer:   s.Proposer,
+		Missed:     s.Missed,
+		BLSKeys:    blsKeys,
 	}
 }
 
@@ -311,6 +448,23 @@ func (s *Snapshot) UnmarshalJSON(b []byte) error {
 	s.Votes = j.Votes
 	s.Tally = j.Tally
 	s.ValSet = validator.NewSet(j.Validators, j.Policy)
+	s.Proposer = j.Proposer
+	s.Missed = j.Missed
+	if s.Missed == nil {
+		s.Missed = make(map[common.Address]uint64)
+	}
+	// Snapshots stored before BLS keys were tracked leave them nil, for the
+	// engine to fill in with the ones of the genesis
+	if j.BLSKeys != nil {
+		s.BLSKeys = make(map[common.Address]*bls.PublicKey, len(j.BLSKeys))
+		for address, blob := range j.BLSKeys {
+			key, err := bls.UnmarshalPublicKey(blob)
+			if err != nil {
+				return err
+			}
+			s.BLSKeys[address] = key
+		}
+	}
 	return nil
 }
 
@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls"
 	"github.com/ethereum/go-ethereum/ethdb"
 )
 
@@ -426,6 +427,9 @@ func TestSaveAndLoad(t *testing.T) {
 			common.StringToAddress("1234567894"),
 			common.StringToAddress("1234567895"),
 		}, istanbul.RoundRobin),
+		BLSKeys: map[common.Address]*bls.PublicKey{
+			common.StringToAddress("1234567894"): bls.DeriveKey([]byte{1}).PublicKey(),
+		},
 	}
 	db := ethdb.NewMemDatabase()
 	err := snap.store(db)
@@ -452,4 +456,40 @@ func TestSaveAndLoad(t *testing.T) {
 	if !reflect.DeepEqual(snap.ValSet, snap.ValSet) {
 		t.Errorf("validator set mismatch: have %v, want %v", snap1.ValSet, snap.ValSet)
 	}
+	key := snap1.BLSKeys[common.StringToAddress("1234567894")]
+	if key == nil || !bytes.Equal(key.Marshal(), snap.BLSKeys[common.StringToAddress("1234567894")].Marshal()) {
+		t.Errorf("BLS keys mismatch: have %v, want %v", snap1.BLSKeys, snap.BLSKeys)
+	}
+}
+
+func TestLivenessTracking(t *testing.T) {
+	var addrs []common.Address
+	for i := 1; i <= 4; i++ {
+		addrs = append(addrs, common.BytesToAddress([]byte{byte(i)}))
+	}
+	config := &istanbul.Config{ProposerPolicy: istanbul.LivenessAware, LivenessWindow: 10}
+	snap := newSnapshot(0, 0, common.Hash{}, validator.NewSet(addrs, istanbul.LivenessAware))
+	snap.Proposer = addrs[0]
+
+	// Block 1 should have been proposed by the second validator, but the third
+	// one proposed it after a round change.
+	snap.trackMissed(1, addrs[2])
+	snap.Proposer = addrs[2]
+	if have := snap.Missed[addrs[1]]; have != 1 {
+		t.Fatalf("missed block mismatch: have %d, want 1", have)
+	}
+	snap.updatePolicy(big.NewInt(2), common.Hash{}, config)
+	if !snap.ValSet.IsInactive(addrs[1]) {
+		t.Errorf("validator which missed its turn not inactive")
+	}
+	snap.ValSet.CalcProposer(addrs[0], 0)
+	if have := snap.ValSet.GetProposer().Address(); have != addrs[2] {
+		t.Errorf("proposer mismatch: have %x, want %x", have, addrs[2])
+	}
+
+	// After the liveness window, the validator gets its turn again.
+	snap.updatePolicy(big.NewInt(12), common.Hash{}, config)
+	if snap.ValSet.IsInactive(addrs[1]) || len(snap.Missed) != 0 {
+		t.Errorf("validator still inactive after the liveness window")
+	}
 }
@@ -0,0 +1,62 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// NewTransition creates an Istanbul engine for a chain started with Clique,
+// which keeps sealing and verifying the blocks before config.TransitionBlock.
+// The Istanbul validators are seeded with the Clique signers of the block
+// preceding the transition.
+func NewTransition(config *istanbul.Config, privateKey *ecdsa.PrivateKey, db ethdb.Database, legacy *clique.Clique) consensus.Istanbul {
+	sb := New(config, privateKey, db).(*backend)
+	sb.clique = legacy
+
+	// Clique signs with the node key too, as Istanbul does
+	legacy.Authorize(sb.address, func(account accounts.Account, hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, privateKey)
+	})
+	return sb
+}
+
+// legacy returns the Clique engine if the given block precedes the transition
+// to Istanbul, nil otherwise.
+func (sb *backend) legacy(number *big.Int) *clique.Clique {
+	if sb.clique == nil || sb.config.IsTransitioned(number) {
+		return nil
+	}
+	return sb.clique
+}
+
+// successor returns the engine processing the Raft blocks if the given block
+// follows the transition to Raft, nil otherwise.
+func (sb *backend) successor(number *big.Int) consensus.Engine {
+	if sb.raft == nil || !sb.config.IsRaft(number) {
+		return nil
+	}
+	return sb.raft
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestTransition(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	chainConfig := *params.TestChainConfig
+	chainConfig.Ethash = nil
+	chainConfig.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	chainConfig.Istanbul = &params.IstanbulConfig{TransitionBlock: big.NewInt(3)}
+
+	genesis := &core.Genesis{
+		Config:     &chainConfig,
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+		ExtraData:  make([]byte, 32+common.AddressLength+65),
+	}
+	copy(genesis.ExtraData[32:], signer[:])
+
+	db := ethdb.NewMemDatabase()
+	genesis.MustCommit(db)
+
+	config := *istanbul.DefaultConfig
+	config.TransitionBlock = chainConfig.Istanbul.TransitionBlock
+	b := NewTransition(&config, key, db, clique.New(chainConfig.Clique, db)).(*backend)
+
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, b, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	// The core waits for the transition before starting
+	if err := b.Start(chain, chain.CurrentBlock, chain.HasBadBlock); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+	if b.coreStarted || !b.coreDeferred {
+		t.Fatalf("core started before the transition")
+	}
+
+	// Clique seals and verifies the blocks before the transition
+	for i := 0; i < 2; i++ {
+		parent := chain.CurrentBlock()
+		header := makeHeader(parent, &config)
+		if err := b.Prepare(chain, header); err != nil {
+			t.Fatal(err)
+		}
+		state, _, _ := chain.StateAt(parent.Root())
+		block, err := b.Finalize(chain, header, state, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results := make(chan *types.Block, 1)
+		if err := b.Seal(chain, block, results, nil); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case block = <-results:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d not sealed by clique", i+1)
+		}
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", i+1, err)
+		}
+		if author, err := b.Author(block.Header()); err != nil || author != signer {
+			t.Errorf("block %d author mismatch: have %x (%v), want %x", i+1, author, err, signer)
+		}
+		b.NewChainHead()
+	}
+	if !b.coreStarted || b.coreDeferred {
+		t.Errorf("core not started at the transition")
+	}
+
+	// Istanbul takes over with the Clique signers as validators
+	if _, err := b.snapshot(chain, 1, chain.GetHeaderByNumber(1).Hash(), nil); err != errLegacyBlock {
+		t.Errorf("snapshot before the transition: have %v, want %v", err, errLegacyBlock)
+	}
+	snap, err := b.snapshot(chain, 2, chain.CurrentBlock().Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validators := snap.validators(); len(validators) != 1 || validators[0] != signer {
+		t.Errorf("validators mismatch: have %x, want [%x]", validators, signer)
+	}
+	header := makeHeader(chain.CurrentBlock(), &config)
+	if err := b.Prepare(chain, header); err != nil {
+		t.Fatal(err)
+	}
+	if header.MixDigest != types.IstanbulDigest || header.Difficulty.Cmp(defaultDifficulty) != 0 {
+		t.Errorf("transition block not prepared by istanbul: %v", header)
+	}
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extra.Validators) != 1 || extra.Validators[0] != signer {
+		t.Errorf("extra validators mismatch: have %x, want [%x]", extra.Validators, signer)
+	}
+}
+
+func TestRaftTransition(t *testing.T) {
+	genesis, keys := getGenesisAndKeys(1)
+	chainConfig := *genesis.Config
+	chainConfig.Istanbul = &params.IstanbulConfig{RaftBlock: big.NewInt(2)}
+	genesis.Config = &chainConfig
+
+	db := ethdb.NewMemDatabase()
+	genesis.MustCommit(db)
+
+	config := *istanbul.DefaultConfig
+	config.RaftBlock = genesis.Config.Istanbul.RaftBlock
+	b := New(&config, keys[0], db).(*backend)
+
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, b, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	if err := b.Start(chain, chain.CurrentBlock, chain.HasBadBlock); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+
+	// Istanbul seals the blocks before the transition, then stops
+	block := makeBlock(chain, b, chain.Genesis())
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to insert istanbul block: %v", err)
+	}
+	b.NewChainHead()
+	if b.coreStarted {
+		t.Errorf("core still started after the transition")
+	}
+	if _, err := b.snapshot(chain, 1, block.Hash(), nil); err != errRaftBlock {
+		t.Errorf("snapshot after the transition: have %v, want %v", err, errRaftBlock)
+	}
+
+	// Raft blocks aren't sealed by Istanbul, but are processed like Raft does
+	header := makeHeader(block, &config)
+	if err := b.Prepare(chain, header); err != errRaftBlock {
+		t.Errorf("prepare error mismatch: have %v, want %v", err, errRaftBlock)
+	}
+	minter := common.HexToAddress("0x01")
+	header.Coinbase = minter
+	header.Time = big.NewInt(time.Now().UnixNano())
+	header.Difficulty = b.CalcDifficulty(chain, header.Time.Uint64(), block.Header())
+	header.Extra = make([]byte, 32)
+
+	state, _, _ := chain.StateAt(block.Root())
+	raftBlock, err := b.Finalize(chain, header, state, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Seal(chain, raftBlock, make(chan *types.Block, 1), nil); err != errRaftBlock {
+		t.Errorf("seal error mismatch: have %v, want %v", err, errRaftBlock)
+	}
+	if _, err := chain.InsertChain(types.Blocks{raftBlock}); err != nil {
+		t.Fatalf("failed to insert raft block: %v", err)
+	}
+	if author, err := b.Author(raftBlock.Header()); err != nil || author != minter {
+		t.Errorf("raft block author mismatch: have %x (%v), want %x", author, err, minter)
+	}
+}
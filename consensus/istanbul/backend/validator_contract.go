@@ -0,0 +1,170 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// validatorContractABI is the part of the validator contract's ABI read by the
+// engine. See permission/contract/ValidatorManager.sol for an implementation
+// managed through the permissions org model.
+const validatorContractABI = `[{"constant":true,"inputs":[],"name":"getValidators","outputs":[{"name":"","type":"address[]"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// validatorContractGas is the gas available to the validator contract to return
+// the validator set.
+const validatorContractGas = 50000000
+
+var (
+	// errEmptyValidatorSet is returned if the validator contract returns no
+	// validators, in which case the previous validator set is kept.
+	errEmptyValidatorSet = errors.New("validator contract returned an empty validator set")
+	// errInvalidValidatorSet is returned if the validators in a header's extra
+	// data differ from the ones returned by the validator contract.
+	errInvalidValidatorSet = errors.New("validator set does not match the validator contract")
+
+	parsedValidatorContractABI, _ = abi.JSON(strings.NewReader(validatorContractABI))
+)
+
+// chainContext adapts a consensus.ChainReader to the core.ChainContext needed to
+// run the EVM.
+type chainContext struct {
+	consensus.ChainReader
+	engine consensus.Engine
+}
+
+func (c chainContext) Engine() consensus.Engine { return c.engine }
+
+// validatorsFromContract calls the validator contract on top of the given state,
+// and returns the validator set it reports in ascending order.
+func (sb *backend) validatorsFromContract(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB) ([]common.Address, error) {
+	input, err := parsedValidatorContractABI.Pack("getValidators")
+	if err != nil {
+		return nil, err
+	}
+	// The call must not leave any trace in the state the block is built upon.
+	statedb = statedb.Copy()
+
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     core.GetHashFn(header, chainContext{chain, sb}),
+		BlockNumber: new(big.Int).Set(header.Number),
+		Time:        new(big.Int).Set(header.Time),
+		Difficulty:  new(big.Int).Set(header.Difficulty),
+		GasLimit:    header.GasLimit,
+		GasPrice:    new(big.Int),
+	}
+	evm := vm.NewEVM(context, statedb, statedb, chain.Config(), vm.Config{})
+
+	output, _, err := evm.StaticCall(vm.AccountRef(common.Address{}), sb.config.ValidatorContract, input, validatorContractGas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call validator contract %x: %v", sb.config.ValidatorContract, err)
+	}
+	var validators []common.Address
+	if err := parsedValidatorContractABI.Unpack(&validators, "getValidators", output); err != nil {
+		return nil, fmt.Errorf("failed to read validators from contract %x: %v", sb.config.ValidatorContract, err)
+	}
+	if len(validators) == 0 {
+		return nil, errEmptyValidatorSet
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i][:], validators[j][:]) < 0
+	})
+	return validators, nil
+}
+
+// applyValidatorContract makes the header's extra data carry the validator set
+// for the next block, as read from the validator contract on top of the block's
+// final state. Unsealed headers, as assembled by the proposer, are updated.
+// Sealed headers are checked instead, so that a block announcing a validator
+// set different from the contract's is rejected.
+//
+// From the BLS seal fork on, validators without a registered BLS key are left
+// out, unless the proposer knows their registration, which it adds to the block.
+// An empty validator set, e.g. after suspending the org of the last validators,
+// would halt the chain, so the validators of the parent block are announced
+// again instead.
+func (sb *backend) applyValidatorContract(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB) error {
+	validators, err := sb.validatorsFromContract(chain, header, statedb)
+	if err != nil && err != errEmptyValidatorSet {
+		return err
+	}
+	istanbulExtra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return err
+	}
+	snap, err := sb.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	if sb.config.IsBLSSeal(header.Number) {
+		if len(istanbulExtra.Seal) == 0 {
+			registered := blsRegistrationsOf(istanbulExtra)
+			sb.candidatesLock.RLock()
+			for _, validator := range validators {
+				if registration, _ := sb.pendingBLSRegistration(snap, validator); registration != nil && !registered[validator] {
+					istanbulExtra.BLSRegistrations = append(istanbulExtra.BLSRegistrations, registration)
+				}
+			}
+			sb.candidatesLock.RUnlock()
+		}
+		registered := blsRegistrationsOf(istanbulExtra)
+		keyed := validators[:0]
+		for _, validator := range validators {
+			if snap.BLSKeys[validator] != nil || registered[validator] {
+				keyed = append(keyed, validator)
+			}
+		}
+		validators = keyed
+	}
+	if len(validators) == 0 {
+		validators = snap.validators()
+	}
+	if len(istanbulExtra.Seal) > 0 {
+		if len(istanbulExtra.Validators) != len(validators) {
+			return errInvalidValidatorSet
+		}
+		for i, validator := range validators {
+			if istanbulExtra.Validators[i] != validator {
+				return errInvalidValidatorSet
+			}
+		}
+		return nil
+	}
+	istanbulExtra.Validators = validators
+	payload, err := rlp.EncodeToBytes(&istanbulExtra)
+	if err != nil {
+		return err
+	}
+	header.Extra = append(header.Extra[:types.IstanbulExtraVanity], payload...)
+	return nil
+}
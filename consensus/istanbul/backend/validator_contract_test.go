@@ -0,0 +1,144 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backend
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// validatorsCode returns the code of a contract which answers any call with the
+// ABI encoding of the given validators.
+func validatorsCode(validators ...common.Address) []byte {
+	words := []common.Hash{common.BigToHash(big.NewInt(32)), common.BigToHash(big.NewInt(int64(len(validators))))}
+	for _, validator := range validators {
+		words = append(words, validator.Hash())
+	}
+	var code []byte
+	for i, word := range words {
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, word[:]...)
+		code = append(code, byte(vm.PUSH1), byte(i*32), byte(vm.MSTORE))
+	}
+	return append(code, byte(vm.PUSH1), byte(len(words)*32), byte(vm.PUSH1), 0, byte(vm.RETURN))
+}
+
+func TestValidatorContract(t *testing.T) {
+	genesis, nodeKeys := getGenesisAndKeys(1)
+	self := crypto.PubkeyToAddress(nodeKeys[0].PublicKey)
+	newcomer := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+	contract := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	genesis.Alloc = core.GenesisAlloc{contract: {Code: validatorsCode(self, newcomer), Balance: big.NewInt(0)}}
+
+	config := *istanbul.DefaultConfig
+	config.ValidatorContractBlock = big.NewInt(1)
+	config.ValidatorContract = contract
+
+	memDB := ethdb.NewMemDatabase()
+	engine := New(&config, nodeKeys[0], memDB).(*backend)
+	genesis.MustCommit(memDB)
+	chain, err := core.NewBlockChain(memDB, nil, genesis.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Start(chain, chain.CurrentBlock, chain.HasBadBlock)
+	defer engine.Stop()
+
+	want := []common.Address{newcomer, self}
+	if bytes.Compare(self[:], newcomer[:]) < 0 {
+		want = []common.Address{self, newcomer}
+	}
+
+	// The proposer announces the contract's validator set in the block.
+	block := makeBlock(chain, engine, chain.Genesis())
+	istanbulExtra, err := types.ExtractIstanbulExtra(block.Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(istanbulExtra.Validators, want) {
+		t.Errorf("announced validators mismatch: have %x, want %x", istanbulExtra.Validators, want)
+	}
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to import block: %v", err)
+	}
+	snap, err := engine.snapshot(chain, 1, block.Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := snap.validators(); !reflect.DeepEqual(have, want) {
+		t.Errorf("snapshot validators mismatch: have %x, want %x", have, want)
+	}
+
+	// A sealed block announcing a different validator set is rejected.
+	header := block.Header()
+	istanbulExtra.Validators = []common.Address{self}
+	payload, _ := rlp.EncodeToBytes(&istanbulExtra)
+	header.Extra = append(header.Extra[:types.IstanbulExtraVanity], payload...)
+
+	state, _, err := chain.StateAt(chain.Genesis().Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.Finalize(chain, header, state, nil, nil, nil); err != errInvalidValidatorSet {
+		t.Errorf("error mismatch: have %v, want %v", err, errInvalidValidatorSet)
+	}
+}
+
+func TestEmptyValidatorContract(t *testing.T) {
+	genesis, nodeKeys := getGenesisAndKeys(1)
+	self := crypto.PubkeyToAddress(nodeKeys[0].PublicKey)
+	contract := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	genesis.Alloc = core.GenesisAlloc{contract: {Code: validatorsCode(), Balance: big.NewInt(0)}}
+
+	config := *istanbul.DefaultConfig
+	config.ValidatorContractBlock = big.NewInt(1)
+	config.ValidatorContract = contract
+
+	memDB := ethdb.NewMemDatabase()
+	engine := New(&config, nodeKeys[0], memDB).(*backend)
+	genesis.MustCommit(memDB)
+	chain, err := core.NewBlockChain(memDB, nil, genesis.Config, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Start(chain, chain.CurrentBlock, chain.HasBadBlock)
+	defer engine.Stop()
+
+	// An empty validator set from the contract keeps the previous validators.
+	block := makeBlock(chain, engine, chain.Genesis())
+	istanbulExtra, err := types.ExtractIstanbulExtra(block.Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []common.Address{self}; !reflect.DeepEqual(istanbulExtra.Validators, want) {
+		t.Errorf("announced validators mismatch: have %x, want %x", istanbulExtra.Validators, want)
+	}
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to import block: %v", err)
+	}
+}
@@ -16,21 +16,95 @@
 
 package istanbul
 
-import "math/big"
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type ProposerPolicy uint64
 
 const (
 	RoundRobin ProposerPolicy = iota
 	Sticky
+	WeightedRandom // Proposers are picked at random, proportionally to their weight
+	LivenessAware  // Round robin, skipping validators which recently missed their turn to propose
+)
+
+// TimeoutGrowth is how the round change timeout grows with the round number.
+type TimeoutGrowth string
+
+const (
+	ExponentialGrowth TimeoutGrowth = "exponential" // RequestTimeout + 2^round * RoundChangeTimeoutIncrement from round 1
+	LinearGrowth      TimeoutGrowth = "linear"      // RequestTimeout + round * RoundChangeTimeoutIncrement
+	CappedGrowth      TimeoutGrowth = "capped"      // Exponential, never above MaxRoundChangeTimeout or DefaultMaxRoundChangeTimeout
+)
+
+// DefaultMaxRoundChangeTimeout is the maximum round change timeout in
+// milliseconds of the capped growth if MaxRoundChangeTimeout isn't set.
+const DefaultMaxRoundChangeTimeout = 60000
+
+// DefaultRoundChangeTimeoutIncrement is the increment of the round change
+// timeout in milliseconds if RoundChangeTimeoutIncrement isn't set.
+const DefaultRoundChangeTimeoutIncrement = 1000
+
+// ParseTimeoutGrowth returns the round change timeout growth of the given name.
+func ParseTimeoutGrowth(name string) (TimeoutGrowth, error) {
+	switch growth := TimeoutGrowth(name); growth {
+	case ExponentialGrowth, LinearGrowth, CappedGrowth:
+		return growth, nil
+	}
+	return "", fmt.Errorf("unknown round change timeout growth %q", name)
+}
+
+// TimeoutReset is when the round change timeout goes back to RequestTimeout.
+type TimeoutReset string
+
+const (
+	SequenceReset TimeoutReset = "sequence" // Every sequence starts from RequestTimeout
+	GradualReset  TimeoutReset = "gradual"  // Every sequence starts one round below the one the previous sequence ended on
 )
 
+// ParseTimeoutReset returns the round change timeout reset of the given name.
+func ParseTimeoutReset(name string) (TimeoutReset, error) {
+	switch reset := TimeoutReset(name); reset {
+	case SequenceReset, GradualReset:
+		return reset, nil
+	}
+	return "", fmt.Errorf("unknown round change timeout reset %q", name)
+}
+
 type Config struct {
 	RequestTimeout uint64         `toml:",omitempty"` // The timeout for each Istanbul round in milliseconds.
 	BlockPeriod    uint64         `toml:",omitempty"` // Default minimum difference between two consecutive block's timestamps in second
 	ProposerPolicy ProposerPolicy `toml:",omitempty"` // The policy for proposer selection
 	Epoch          uint64         `toml:",omitempty"` // The number of blocks after which to checkpoint and reset the pending votes
 	Ceil2Nby3Block *big.Int       `toml:",omitempty"` // Number of confirmations required to move from one state to next [2F + 1 to Ceil(2N/3)]
+
+	ValidatorContractBlock *big.Int       `toml:",omitempty"` // Block from which the validator set is read from ValidatorContract
+	ValidatorContract      common.Address `toml:",omitempty"` // Address of the contract managing the validator set
+
+	ProposerPolicyBlock *big.Int                  `toml:",omitempty"` // Block from which NewProposerPolicy replaces ProposerPolicy
+	NewProposerPolicy   ProposerPolicy            `toml:",omitempty"` // The policy for proposer selection from ProposerPolicyBlock
+	ValidatorWeights    map[common.Address]uint64 `toml:"-"`          // Weights of validators for the WeightedRandom policy, 1 if absent
+	LivenessWindow      uint64                    `toml:",omitempty"` // Number of blocks the LivenessAware policy skips a validator for after a missed turn
+
+	RoundChangeTimeoutBlock     *big.Int      `toml:",omitempty"` // Block from which the round change timeout settings below apply
+	RoundChangeTimeoutGrowth    TimeoutGrowth `toml:",omitempty"` // How the round change timeout grows with the round number, exponential if empty
+	RoundChangeTimeoutIncrement uint64        `toml:",omitempty"` // The increment of the round change timeout in milliseconds, DefaultRoundChangeTimeoutIncrement if 0
+	MaxRoundChangeTimeout       uint64        `toml:",omitempty"` // The maximum round change timeout in milliseconds, 0 for none
+	RoundChangeTimeoutReset     TimeoutReset  `toml:",omitempty"` // When the round change timeout goes back to RequestTimeout, every sequence if empty
+
+	BLSSealBlock  *big.Int                  `toml:",omitempty"` // Block from which committed seals are aggregated BLS signatures
+	BLSPublicKeys map[common.Address][]byte `toml:"-"`          // BLS public keys of validators followed by their proof of possession
+
+	Relay bool `toml:",omitempty"` // Forward consensus messages between validators when not validating
+
+	TransitionBlock *big.Int `toml:",omitempty"` // Block from which Istanbul takes over from Clique, nil if the chain started with Istanbul
+	RaftBlock       *big.Int `toml:",omitempty"` // Block from which Raft takes over from Istanbul, nil if there is no such transition
 }
 
 var DefaultConfig = &Config{
@@ -39,4 +113,94 @@ var DefaultConfig = &Config{
 	ProposerPolicy: RoundRobin,
 	Epoch:          30000,
 	Ceil2Nby3Block: big.NewInt(0),
+	LivenessWindow: 100,
+}
+
+// IsValidatorContract returns whether the validator set following the given
+// block is read from the validator contract.
+func (c *Config) IsValidatorContract(number *big.Int) bool {
+	return c.ValidatorContractBlock != nil && c.ValidatorContractBlock.Cmp(number) <= 0
+}
+
+// ProposerPolicyAt returns the policy for proposer selection at the given block.
+func (c *Config) ProposerPolicyAt(number *big.Int) ProposerPolicy {
+	if c.ProposerPolicyBlock != nil && c.ProposerPolicyBlock.Cmp(number) <= 0 {
+		return c.NewProposerPolicy
+	}
+	return c.ProposerPolicy
+}
+
+// IsBLSSeal returns whether the committed seals of the given block are an
+// aggregated BLS signature.
+func (c *Config) IsBLSSeal(number *big.Int) bool {
+	return c.BLSSealBlock != nil && c.BLSSealBlock.Cmp(number) <= 0
+}
+
+// IsTransitioned returns whether the given block is sealed by Istanbul rather
+// than by the Clique engine the chain started with.
+func (c *Config) IsTransitioned(number *big.Int) bool {
+	return c.TransitionBlock == nil || number == nil || c.TransitionBlock.Cmp(number) <= 0
+}
+
+// IsRaft returns whether the given block is minted by Raft after the transition
+// from Istanbul.
+func (c *Config) IsRaft(number *big.Int) bool {
+	return c.RaftBlock != nil && number != nil && c.RaftBlock.Cmp(number) <= 0
+}
+
+// roundChangeTimeoutConfig returns the round change timeout settings in effect at
+// the given block: the configured ones from RoundChangeTimeoutBlock, or the
+// defaults before. Settings left unset take their default value.
+func (c *Config) roundChangeTimeoutConfig(number *big.Int) (TimeoutGrowth, uint64, uint64, TimeoutReset) {
+	growth, increment, max, reset := ExponentialGrowth, uint64(DefaultRoundChangeTimeoutIncrement), uint64(0), SequenceReset
+	if c.RoundChangeTimeoutBlock == nil || c.RoundChangeTimeoutBlock.Cmp(number) <= 0 {
+		if c.RoundChangeTimeoutGrowth != "" {
+			growth = c.RoundChangeTimeoutGrowth
+		}
+		if c.RoundChangeTimeoutIncrement != 0 {
+			increment = c.RoundChangeTimeoutIncrement
+		}
+		max = c.MaxRoundChangeTimeout
+		if c.RoundChangeTimeoutReset != "" {
+			reset = c.RoundChangeTimeoutReset
+		}
+	}
+	return growth, increment, max, reset
+}
+
+// RoundChangeTimeout returns how long to wait for the given round of the given
+// block to complete before moving to the next round.
+func (c *Config) RoundChangeTimeout(number *big.Int, round uint64) time.Duration {
+	growth, increment, max, _ := c.roundChangeTimeoutConfig(number)
+
+	timeout := float64(c.RequestTimeout)
+	switch growth {
+	case LinearGrowth:
+		timeout += float64(round) * float64(increment)
+	case CappedGrowth:
+		if max == 0 {
+			max = DefaultMaxRoundChangeTimeout
+		}
+		fallthrough
+	default:
+		if round > 0 {
+			timeout += math.Pow(2, float64(round)) * float64(increment)
+		}
+	}
+	if max != 0 && timeout > float64(max) {
+		timeout = float64(max)
+	}
+	// Keep clear of overflowing time.Duration on very high rounds
+	if limit := float64(math.MaxInt64 / int64(time.Millisecond)); timeout > limit {
+		timeout = limit
+	}
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// GradualTimeoutReset returns whether the round change timeout of the given
+// block starts from where the previous block's one ended, rather than from
+// RequestTimeout.
+func (c *Config) GradualTimeoutReset(number *big.Int) bool {
+	_, _, _, reset := c.roundChangeTimeoutConfig(number)
+	return reset == GradualReset
 }
@@ -0,0 +1,77 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestRoundChangeTimeout(t *testing.T) {
+	config := *DefaultConfig
+	config.RoundChangeTimeoutBlock = big.NewInt(10)
+	config.RoundChangeTimeoutIncrement = 500
+
+	tests := []struct {
+		growth TimeoutGrowth
+		max    uint64
+		number int64
+		round  uint64
+		want   time.Duration
+	}{
+		// Before the fork block, the default settings apply
+		{LinearGrowth, 0, 9, 0, 10 * time.Second},
+		{LinearGrowth, 0, 9, 3, 18 * time.Second},
+
+		{ExponentialGrowth, 0, 10, 0, 10 * time.Second},
+		{ExponentialGrowth, 0, 10, 3, 14 * time.Second},
+		{ExponentialGrowth, 12000, 10, 3, 12 * time.Second},
+		{LinearGrowth, 0, 10, 3, 11500 * time.Millisecond},
+		{LinearGrowth, 11000, 10, 3, 11 * time.Second},
+		{CappedGrowth, 0, 10, 3, 14 * time.Second},
+		{CappedGrowth, 0, 10, 20, DefaultMaxRoundChangeTimeout * time.Millisecond},
+		{CappedGrowth, 20000, 10, 20, 20 * time.Second},
+		{ExponentialGrowth, 0, 10, 1000, time.Duration(1<<63 - 1).Truncate(time.Millisecond)},
+	}
+	for i, tt := range tests {
+		config.RoundChangeTimeoutGrowth = tt.growth
+		config.MaxRoundChangeTimeout = tt.max
+		if have := config.RoundChangeTimeout(big.NewInt(tt.number), tt.round); have != tt.want {
+			t.Errorf("test %d: timeout mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+func TestParseTimeoutSettings(t *testing.T) {
+	for _, name := range []string{"exponential", "linear", "capped"} {
+		if growth, err := ParseTimeoutGrowth(name); err != nil || string(growth) != name {
+			t.Errorf("growth %q: have %q, %v", name, growth, err)
+		}
+	}
+	if _, err := ParseTimeoutGrowth("quadratic"); err == nil {
+		t.Errorf("unknown growth accepted")
+	}
+	for _, name := range []string{"sequence", "gradual"} {
+		if reset, err := ParseTimeoutReset(name); err != nil || string(reset) != name {
+			t.Errorf("reset %q: have %q, %v", name, reset, err)
+		}
+	}
+	if _, err := ParseTimeoutReset("never"); err == nil {
+		t.Errorf("unknown reset accepted")
+	}
+}
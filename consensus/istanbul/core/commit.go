@@ -61,6 +61,7 @@ func (c *core) handleCommit(msg *message, src istanbul.Validator) error {
 	if err := c.checkMessage(msgCommit, commit.View); err != nil {
 		return err
 	}
+	c.checkEquivocation(msg, commit)
 
 	if err := c.verifyCommit(commit, src); err != nil {
 		return err
@@ -102,6 +103,7 @@ func (c *core) acceptCommit(msg *message, src istanbul.Validator) error {
 		logger.Error("Failed to record commit message", "msg", msg, "err", err)
 		return err
 	}
+	c.tracer.message(msgCommit, src.Address(), c.current.Round().Uint64())
 
 	return nil
 }
@@ -25,7 +25,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	metrics "github.com/ethereum/go-ethereum/metrics"
@@ -83,9 +82,14 @@ type core struct {
 
 	current   *roundState
 	handlerWg *sync.WaitGroup
+	tracer    roundTracer
 
 	roundChangeSet   *roundChangeSet
 	roundChangeTimer *time.Timer
+	// the rounds carried over from the previous sequences by the round change timeout
+	timeoutCarry uint64
+	// the first PREPARE and COMMIT messages of each validator in the current view
+	signedSubjects map[signedKey]*signedSubject
 
 	pendingRequests   *prque.Prque
 	pendingRequestsMu *sync.Mutex
@@ -108,8 +112,7 @@ func (c *core) finalizeMessage(msg *message) ([]byte, error) {
 	msg.CommittedSeal = []byte{}
 	// Assign the CommittedSeal if it's a COMMIT message and proposal is not nil
 	if msg.Code == msgCommit && c.current.Proposal() != nil {
-		seal := PrepareCommittedSeal(c.current.Proposal().Hash())
-		msg.CommittedSeal, err = c.backend.Sign(seal)
+		msg.CommittedSeal, err = c.backend.SignCommittedSeal(c.current.Proposal())
 		if err != nil {
 			return nil, err
 		}
@@ -176,12 +179,13 @@ func (c *core) commit() {
 	if proposal != nil {
 		committedSeals := make([][]byte, c.current.Commits.Size())
 		for i, v := range c.current.Commits.Values() {
-			committedSeals[i] = make([]byte, types.IstanbulExtraSeal)
+			committedSeals[i] = make([]byte, len(v.CommittedSeal))
 			copy(committedSeals[i][:], v.CommittedSeal[:])
 		}
 
 		if err := c.backend.Commit(proposal, committedSeals); err != nil {
 			c.current.UnlockHash() //Unlock block when insertion fails
+			c.tracer.setRoundChangeReason(reasonCommitFailed)
 			c.sendNextRoundChange()
 			return
 		}
@@ -198,6 +202,7 @@ func (c *core) startNewRound(round *big.Int) {
 	}
 
 	roundChange := false
+	reason := ""
 	// Try to get last proposal
 	lastProposal, lastProposer := c.backend.LastProposal()
 	if c.current == nil {
@@ -210,6 +215,11 @@ func (c *core) startNewRound(round *big.Int) {
 			c.consensusTimer.UpdateSince(c.consensusTimestamp)
 			c.consensusTimestamp = time.Time{}
 		}
+		reason = reasonCommitted
+		if diff.Sign() > 0 {
+			reason = reasonSynced
+		}
+		c.updateTimeoutCarry(lastProposal.Number())
 		logger.Trace("Catch up latest proposal", "number", lastProposal.Number().Uint64(), "hash", lastProposal.Hash())
 	} else if lastProposal.Number().Cmp(big.NewInt(c.current.Sequence().Int64()-1)) == 0 {
 		if round.Cmp(common.Big0) == 0 {
@@ -248,6 +258,7 @@ func (c *core) startNewRound(round *big.Int) {
 	// Calculate new proposer
 	c.valSet.CalcProposer(lastProposer, newView.Round.Uint64())
 	c.waitingForRoundChange = false
+	c.tracer.startRound(newView, c.valSet.GetProposer().Address(), c.current.GetLockedHash(), false, reason)
 	c.setState(StateAcceptRequest)
 	if roundChange && c.IsProposer() && c.current != nil {
 		// If it is locked, propose the old proposal
@@ -276,6 +287,7 @@ func (c *core) catchUpRound(view *istanbul.View) {
 
 	// Need to keep block locked for round catching up
 	c.updateRoundState(view, c.valSet, true)
+	c.tracer.startRound(view, c.valSet.GetProposer().Address(), c.current.GetLockedHash(), true, "")
 	c.roundChangeSet.Clear(view.Round)
 	c.newRoundChangeTimer()
 
@@ -284,6 +296,8 @@ func (c *core) catchUpRound(view *istanbul.View) {
 
 // updateRoundState updates round state by checking if locking block is necessary
 func (c *core) updateRoundState(view *istanbul.View, validatorSet istanbul.ValidatorSet, roundChange bool) {
+	c.signedSubjects = nil
+
 	// Lock only if both roundChange is true and it is locked
 	if roundChange && c.current != nil {
 		if c.current.IsHashLocked() {
@@ -299,6 +313,7 @@ func (c *core) updateRoundState(view *istanbul.View, validatorSet istanbul.Valid
 func (c *core) setState(state State) {
 	if c.state != state {
 		c.state = state
+		c.tracer.setState(state, c.current.GetLockedHash())
 	}
 	if state == StateAcceptRequest {
 		c.processPendingRequests()
@@ -327,17 +342,27 @@ func (c *core) newRoundChangeTimer() {
 	c.stopTimer()
 
 	// set timeout based on the round number
-	timeout := time.Duration(c.config.RequestTimeout) * time.Millisecond
-	round := c.current.Round().Uint64()
-	if round > 0 {
-		timeout += time.Duration(math.Pow(2, float64(round))) * time.Second
-	}
+	timeout := c.config.RoundChangeTimeout(c.current.Sequence(), c.current.Round().Uint64()+c.timeoutCarry)
 
 	c.roundChangeTimer = time.AfterFunc(timeout, func() {
 		c.sendEvent(timeoutEvent{})
 	})
 }
 
+// updateTimeoutCarry updates the rounds the round change timeout of the sequence
+// following the given block starts from. With gradual reset, a sequence starts
+// one round below the one the previous sequence ended on, so that the timeout
+// steps down after a period of round changes instead of going back to
+// RequestTimeout at once.
+func (c *core) updateTimeoutCarry(number *big.Int) {
+	level := c.timeoutCarry + c.current.Round().Uint64()
+	if !c.config.GradualTimeoutReset(new(big.Int).Add(number, common.Big1)) || level == 0 {
+		c.timeoutCarry = 0
+		return
+	}
+	c.timeoutCarry = level - 1
+}
+
 func (c *core) checkValidatorSignature(data []byte, sig []byte) (common.Address, error) {
 	return istanbul.CheckValidatorSignature(c.valSet, data, sig)
 }
@@ -358,3 +383,13 @@ func PrepareCommittedSeal(hash common.Hash) []byte {
 	buf.Write([]byte{byte(msgCommit)})
 	return buf.Bytes()
 }
+
+// MessageSender returns the address which signed the given consensus message,
+// without checking whether it belongs to a validator.
+func MessageSender(payload []byte) (common.Address, error) {
+	msg := new(message)
+	if err := msg.FromPayload(payload, istanbul.GetSignatureAddress); err != nil {
+		return common.Address{}, err
+	}
+	return msg.Address, nil
+}
@@ -98,3 +98,33 @@ func TestQuorumSize(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeoutCarry(t *testing.T) {
+	config := *istanbul.DefaultConfig
+	config.RoundChangeTimeoutReset = istanbul.GradualReset
+	config.RoundChangeTimeoutBlock = big.NewInt(3)
+
+	c := &core{config: &config}
+	view := func(seq, round int64) *istanbul.View {
+		return &istanbul.View{Sequence: big.NewInt(seq), Round: big.NewInt(round)}
+	}
+	tests := []struct {
+		view *istanbul.View
+		want uint64
+	}{
+		{view(1, 3), 0}, // the sequence reset applies before the fork block
+		{view(2, 3), 2},
+		{view(3, 0), 1},
+		{view(4, 2), 2},
+		{view(5, 0), 1},
+		{view(6, 0), 0},
+		{view(7, 0), 0},
+	}
+	for i, tt := range tests {
+		c.current = newRoundState(tt.view, newTestValidatorSet(4), common.Hash{}, nil, nil, nil)
+		c.updateTimeoutCarry(tt.view.Sequence)
+		if c.timeoutCarry != tt.want {
+			t.Errorf("test %d: carry mismatch: have %d, want %d", i, c.timeoutCarry, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+// signedKey identifies the messages a validator signs at most once per view.
+type signedKey struct {
+	code    uint64
+	address common.Address
+}
+
+// signedSubject is the first message of a kind received from a validator in
+// the current view.
+type signedSubject struct {
+	msg      *message
+	digest   common.Hash
+	reported bool // Whether the validator was caught equivocating in the view
+}
+
+// checkEquivocation records the subject of a PREPARE or COMMIT message of the
+// current view, and reports the sender to the backend if it signed a message
+// of the same kind for another proposal in the view.
+func (c *core) checkEquivocation(msg *message, subject *istanbul.Subject) {
+	if c.signedSubjects == nil {
+		c.signedSubjects = make(map[signedKey]*signedSubject)
+	}
+	key := signedKey{code: msg.Code, address: msg.Address}
+	first, ok := c.signedSubjects[key]
+	if !ok {
+		c.signedSubjects[key] = &signedSubject{msg: msg, digest: subject.Digest}
+		return
+	}
+	// Only the first conflict is reported, the next ones prove nothing more
+	if first.digest == subject.Digest || first.reported {
+		return
+	}
+	first.reported = true
+
+	logger := c.logger.New("from", msg.Address, "state", c.state)
+	firstPayload, err := first.msg.Payload()
+	if err != nil {
+		logger.Error("Failed to encode equivocating message", "err", err)
+		return
+	}
+	payload, err := msg.Payload()
+	if err != nil {
+		logger.Error("Failed to encode equivocating message", "err", err)
+		return
+	}
+	evidence := &istanbul.Evidence{
+		Validator: msg.Address,
+		View:      subject.View,
+		Type:      messageType(msg.Code),
+		Digests:   []common.Hash{first.digest, subject.Digest},
+		Messages:  [][]byte{firstPayload, payload},
+	}
+	logger.Warn("Validator equivocated", "type", evidence.Type, "view", subject.View, "digests", evidence.Digests)
+	c.backend.ReportEquivocation(evidence)
+}
+
+// messageType returns the name of the given message code.
+func messageType(code uint64) string {
+	switch code {
+	case msgPreprepare:
+		return "PREPREPARE"
+	case msgPrepare:
+		return "PREPARE"
+	case msgCommit:
+		return "COMMIT"
+	case msgRoundChange:
+		return "ROUND CHANGE"
+	}
+	return "UNKNOWN"
+}
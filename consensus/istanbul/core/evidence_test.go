@@ -0,0 +1,83 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+func TestEquivocation(t *testing.T) {
+	view := &istanbul.View{Round: big.NewInt(0), Sequence: big.NewInt(1)}
+
+	for _, code := range []uint64{msgPrepare, msgCommit} {
+		sys := NewTestSystemWithBackend(4, 1)
+		backend := sys.backends[0]
+		c := backend.engine.(*core)
+		c.valSet = backend.peers
+		c.current = newTestRoundState(view, c.valSet)
+		c.state = StatePreprepared
+
+		src := c.valSet.GetByIndex(1)
+		send := func(digest common.Hash) {
+			subject, _ := Encode(&istanbul.Subject{View: view, Digest: digest})
+			msg := &message{Code: code, Msg: subject, Address: src.Address()}
+			if code == msgPrepare {
+				c.handlePrepare(msg, src)
+			} else {
+				c.handleCommit(msg, src)
+			}
+		}
+		digest := c.current.Subject().Digest
+		send(digest)
+		send(digest)
+		if len(backend.evidence) != 0 {
+			t.Fatalf("%s: evidence reported for repeated message", messageType(code))
+		}
+		send(common.HexToHash("0x01"))
+		send(common.HexToHash("0x02"))
+		if len(backend.evidence) != 1 {
+			t.Fatalf("%s: evidence count mismatch: have %d, want 1", messageType(code), len(backend.evidence))
+		}
+		evidence := backend.evidence[0]
+		if evidence.Validator != src.Address() || evidence.Type != messageType(code) || evidence.View.Cmp(view) != 0 {
+			t.Errorf("%s: evidence mismatch: have %v", messageType(code), evidence)
+		}
+		if len(evidence.Digests) != 2 || evidence.Digests[0] != digest || evidence.Digests[1] != common.HexToHash("0x01") {
+			t.Errorf("%s: evidence digests mismatch: have %v", messageType(code), evidence.Digests)
+		}
+		for i, payload := range evidence.Messages {
+			msg := new(message)
+			if err := msg.FromPayload(payload, nil); err != nil {
+				t.Fatalf("%s: failed to decode evidence message: %v", messageType(code), err)
+			}
+			var subject *istanbul.Subject
+			if err := msg.Decode(&subject); err != nil || subject.Digest != evidence.Digests[i] {
+				t.Errorf("%s: evidence message %d mismatch: have %v", messageType(code), i, subject)
+			}
+		}
+
+		// Messages are only compared within a view
+		c.updateRoundState(&istanbul.View{Round: big.NewInt(1), Sequence: big.NewInt(1)}, c.valSet, true)
+		if c.signedSubjects != nil {
+			t.Errorf("%s: signed subjects not reset on view change", messageType(code))
+		}
+	}
+}
@@ -48,6 +48,7 @@ func (c *core) handlePrepare(msg *message, src istanbul.Validator) error {
 	if err := c.checkMessage(msgPrepare, prepare.View); err != nil {
 		return err
 	}
+	c.checkEquivocation(msg, prepare)
 
 	// If it is locked, it can only process on the locked block.
 	// Passing verifyPrepare and checkMessage implies it is processing on the locked block since it was verified in the Preprepared state.
@@ -90,6 +91,7 @@ func (c *core) acceptPrepare(msg *message, src istanbul.Validator) error {
 		logger.Error("Failed to add PREPARE message to round state", "msg", msg, "err", err)
 		return err
 	}
+	c.tracer.message(msgPrepare, src.Address(), c.current.Round().Uint64())
 
 	return nil
 }
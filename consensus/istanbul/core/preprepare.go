@@ -94,6 +94,7 @@ func (c *core) handlePreprepare(msg *message, src istanbul.Validator) error {
 			})
 		} else {
 			logger.Warn("Failed to verify proposal", "err", err, "duration", duration)
+			c.tracer.setRoundChangeReason(reasonInvalidProposal)
 			c.sendNextRoundChange()
 		}
 		return err
@@ -110,6 +111,7 @@ func (c *core) handlePreprepare(msg *message, src istanbul.Validator) error {
 				c.sendCommit()
 			} else {
 				// Send round change
+				c.tracer.setRoundChangeReason(reasonLockedProposal)
 				c.sendNextRoundChange()
 			}
 		} else {
@@ -128,4 +130,5 @@ func (c *core) handlePreprepare(msg *message, src istanbul.Validator) error {
 func (c *core) acceptPreprepare(preprepare *istanbul.Preprepare) {
 	c.consensusTimestamp = time.Now()
 	c.current.SetPreprepare(preprepare)
+	c.tracer.preprepare(preprepare.Proposal.Hash())
 }
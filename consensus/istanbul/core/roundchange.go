@@ -89,17 +89,20 @@ func (c *core) handleRoundChange(msg *message, src istanbul.Validator) error {
 		logger.Warn("Failed to add round change message", "from", src, "msg", msg, "err", err)
 		return err
 	}
+	c.tracer.message(msgRoundChange, src.Address(), roundView.Round.Uint64())
 
 	// Once we received f+1 ROUND CHANGE messages, those messages form a weak certificate.
 	// If our round number is smaller than the certificate's round number, we would
 	// try to catch up the round number.
 	if c.waitingForRoundChange && num == int(c.valSet.F()+1) {
 		if cv.Round.Cmp(roundView.Round) < 0 {
+			c.tracer.setRoundChangeReason(reasonRoundChangeCert)
 			c.sendRoundChange(roundView.Round)
 		}
 		return nil
 	} else if num == c.QuorumSize() && (c.waitingForRoundChange || cv.Round.Cmp(roundView.Round) < 0) {
 		// We've received 2f+1/Ceil(2N/3) ROUND CHANGE messages, start a new round immediately.
+		c.tracer.setRoundChangeReason(reasonRoundChangeQuorum)
 		c.startNewRound(roundView.Round)
 		return nil
 	} else if cv.Round.Cmp(roundView.Round) < 0 {
@@ -42,6 +42,7 @@ type testSystemBackend struct {
 
 	committedMsgs []testCommittedMsgs
 	sentMsgs      [][]byte // store the message when Send is called by core
+	evidence      []*istanbul.Evidence
 
 	address common.Address
 	db      ethdb.Database
@@ -113,6 +114,10 @@ func (self *testSystemBackend) Sign(data []byte) ([]byte, error) {
 	return self.address.Bytes(), nil
 }
 
+func (self *testSystemBackend) SignCommittedSeal(proposal istanbul.Proposal) ([]byte, error) {
+	return self.Sign(PrepareCommittedSeal(proposal.Hash()))
+}
+
 func (self *testSystemBackend) CheckSignature([]byte, common.Address, []byte) error {
 	return nil
 }
@@ -135,6 +140,10 @@ func (self *testSystemBackend) HasBadProposal(hash common.Hash) bool {
 	return false
 }
 
+func (self *testSystemBackend) ReportEquivocation(evidence *istanbul.Evidence) {
+	self.evidence = append(self.evidence, evidence)
+}
+
 func (self *testSystemBackend) LastProposal() (istanbul.Proposal, common.Address) {
 	l := len(self.committedMsgs)
 	if l > 0 {
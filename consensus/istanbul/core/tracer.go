@@ -0,0 +1,260 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+// roundHistoryLimit is the number of finished rounds kept by the round tracer.
+const roundHistoryLimit = 128
+
+// Reasons for a round to end, as reported in RoundInfo.EndReason.
+const (
+	reasonCommitted          = "committed"
+	reasonSynced             = "synced to a newer block"
+	reasonTimeout            = "round change timer expired"
+	reasonInvalidProposal    = "invalid proposal"
+	reasonLockedProposal     = "proposal differs from locked proposal"
+	reasonCommitFailed       = "failed to commit proposal"
+	reasonRoundChangeCert    = "received F+1 round change messages"
+	reasonRoundChangeQuorum  = "received a quorum of round change messages"
+	reasonRoundChangeUnknown = "round change"
+)
+
+// RoundInfo describes a consensus round, as seen by the local node.
+type RoundInfo struct {
+	Sequence     uint64                    `json:"sequence"`
+	Round        uint64                    `json:"round"`
+	Proposer     common.Address            `json:"proposer"`
+	State        string                    `json:"state"`
+	Proposal     common.Hash               `json:"proposal"`
+	LockedHash   common.Hash               `json:"lockedHash"`
+	Prepares     []common.Address          `json:"prepares"`
+	Commits      []common.Address          `json:"commits"`
+	RoundChanges map[common.Address]uint64 `json:"roundChanges"`
+
+	StartedAt    time.Time  `json:"startedAt"`
+	PreprepareAt *time.Time `json:"preprepareAt,omitempty"`
+	PreparedAt   *time.Time `json:"preparedAt,omitempty"`
+	CommittedAt  *time.Time `json:"committedAt,omitempty"`
+	EndedAt      *time.Time `json:"endedAt,omitempty"`
+	EndReason    string     `json:"endReason,omitempty"`
+}
+
+// copy returns a deep copy of the round info.
+func (r *RoundInfo) copy() *RoundInfo {
+	cpy := *r
+	cpy.Prepares = append([]common.Address{}, r.Prepares...)
+	cpy.Commits = append([]common.Address{}, r.Commits...)
+	cpy.RoundChanges = make(map[common.Address]uint64, len(r.RoundChanges))
+	for addr, round := range r.RoundChanges {
+		cpy.RoundChanges[addr] = round
+	}
+	return &cpy
+}
+
+// Status describes the current consensus round and the messages queued for
+// future rounds.
+type Status struct {
+	RoundInfo
+	WaitingForRoundChange bool                   `json:"waitingForRoundChange"`
+	IsProposer            bool                   `json:"isProposer"`
+	Backlogs              map[common.Address]int `json:"backlogs"`
+}
+
+// roundTracer records the progress of the consensus rounds, to be queried
+// concurrently with the core's event loop. Its zero value is ready to use.
+type roundTracer struct {
+	mu      sync.RWMutex
+	current *RoundInfo
+	waiting bool
+	reason  string
+	history []*RoundInfo
+}
+
+// setRoundChangeReason records why the current round is being left. Only the
+// first reason given during a round is kept.
+func (t *roundTracer) setRoundChangeReason(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.reason == "" {
+		t.reason = reason
+	}
+}
+
+// startRound moves the tracer to the given round, ending the current one with
+// the given reason, or with the reason recorded by setRoundChangeReason if
+// empty. A round entered while waiting for round changes and then started for
+// good is kept as a single round.
+func (t *roundTracer) startRound(view *istanbul.View, proposer common.Address, lockedHash common.Hash, waiting bool, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.current != nil {
+		if t.current.Sequence == view.Sequence.Uint64() && t.current.Round == view.Round.Uint64() {
+			t.current.Proposer = proposer
+			t.current.LockedHash = lockedHash
+			t.waiting = waiting
+			t.reason = ""
+			return
+		}
+		if reason == "" {
+			reason = t.reason
+		}
+		if reason == "" {
+			reason = reasonRoundChangeUnknown
+		}
+		t.current.EndedAt = &now
+		t.current.EndReason = reason
+
+		t.history = append(t.history, t.current)
+		if len(t.history) > roundHistoryLimit {
+			t.history = t.history[len(t.history)-roundHistoryLimit:]
+		}
+	}
+	t.current = &RoundInfo{
+		Sequence:     view.Sequence.Uint64(),
+		Round:        view.Round.Uint64(),
+		Proposer:     proposer,
+		State:        StateAcceptRequest.String(),
+		LockedHash:   lockedHash,
+		RoundChanges: make(map[common.Address]uint64),
+		StartedAt:    now,
+	}
+	t.waiting = waiting
+	t.reason = ""
+}
+
+// setState records a state transition of the current round.
+func (t *roundTracer) setState(state State, lockedHash common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current == nil || t.current.State == state.String() {
+		return
+	}
+	now := time.Now()
+	switch state {
+	case StatePreprepared:
+		t.current.PreprepareAt = &now
+	case StatePrepared:
+		t.current.PreparedAt = &now
+	case StateCommitted:
+		t.current.CommittedAt = &now
+	}
+	t.current.State = state.String()
+	t.current.LockedHash = lockedHash
+}
+
+// preprepare records the proposal accepted in the current round.
+func (t *roundTracer) preprepare(proposal common.Hash) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current != nil {
+		t.current.Proposal = proposal
+	}
+}
+
+// message records a PREPARE, COMMIT or ROUND CHANGE message accepted from the
+// given validator.
+func (t *roundTracer) message(code uint64, src common.Address, round uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.current == nil {
+		return
+	}
+	switch code {
+	case msgPrepare:
+		t.current.Prepares = appendAddress(t.current.Prepares, src)
+	case msgCommit:
+		t.current.Commits = appendAddress(t.current.Commits, src)
+	case msgRoundChange:
+		if r, ok := t.current.RoundChanges[src]; !ok || r < round {
+			t.current.RoundChanges[src] = round
+		}
+	}
+}
+
+// status returns a copy of the current round, or nil if consensus hasn't
+// started yet.
+func (t *roundTracer) status() (*RoundInfo, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.current == nil {
+		return nil, false
+	}
+	return t.current.copy(), t.waiting
+}
+
+// rounds returns a copy of the finished rounds, oldest first.
+func (t *roundTracer) rounds() []*RoundInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	history := make([]*RoundInfo, len(t.history))
+	for i, round := range t.history {
+		history[i] = round.copy()
+	}
+	return history
+}
+
+func appendAddress(addrs []common.Address, addr common.Address) []common.Address {
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
+
+// Status implements core.Engine.Status
+func (c *core) Status() *Status {
+	current, waiting := c.tracer.status()
+	if current == nil {
+		return nil
+	}
+	status := &Status{
+		RoundInfo:             *current,
+		WaitingForRoundChange: waiting,
+		IsProposer:            current.Proposer == c.address,
+		Backlogs:              make(map[common.Address]int),
+	}
+	c.backlogsMu.Lock()
+	defer c.backlogsMu.Unlock()
+
+	for addr, backlog := range c.backlogs {
+		if backlog != nil && backlog.Size() > 0 {
+			status.Backlogs[addr] = backlog.Size()
+		}
+	}
+	return status
+}
+
+// RoundHistory implements core.Engine.RoundHistory
+func (c *core) RoundHistory() []*RoundInfo {
+	return c.tracer.rounds()
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+func TestRoundTracer(t *testing.T) {
+	var (
+		tracer roundTracer
+		addr   = common.HexToAddress("0x01")
+		view   = func(seq, round int64) *istanbul.View {
+			return &istanbul.View{Sequence: big.NewInt(seq), Round: big.NewInt(round)}
+		}
+	)
+	if status, _ := tracer.status(); status != nil {
+		t.Fatalf("status before the first round: have %v, want nil", status)
+	}
+	tracer.startRound(view(1, 0), addr, common.Hash{}, false, "")
+	tracer.message(msgPrepare, addr, 0)
+	tracer.message(msgPrepare, addr, 0)
+	tracer.message(msgRoundChange, addr, 2)
+	tracer.message(msgRoundChange, addr, 1)
+
+	// Leaving a round for a round change keeps the first reason given, and
+	// entering the same round again doesn't start a new one.
+	tracer.setRoundChangeReason(reasonTimeout)
+	tracer.setRoundChangeReason(reasonRoundChangeCert)
+	tracer.startRound(view(1, 2), addr, common.Hash{}, true, "")
+	tracer.setRoundChangeReason(reasonRoundChangeQuorum)
+	tracer.startRound(view(1, 2), addr, common.Hash{}, false, "")
+	tracer.setState(StateCommitted, common.Hash{})
+	tracer.startRound(view(2, 0), addr, common.Hash{}, false, reasonCommitted)
+
+	history := tracer.rounds()
+	if len(history) != 2 {
+		t.Fatalf("history length mismatch: have %d, want 2", len(history))
+	}
+	if len(history[0].Prepares) != 1 || history[0].RoundChanges[addr] != 2 {
+		t.Errorf("messages mismatch: have prepares %v, round changes %v", history[0].Prepares, history[0].RoundChanges)
+	}
+	if history[0].EndReason != reasonTimeout {
+		t.Errorf("end reason mismatch: have %q, want %q", history[0].EndReason, reasonTimeout)
+	}
+	if history[1].Round != 2 || history[1].EndReason != reasonCommitted || history[1].CommittedAt == nil {
+		t.Errorf("round mismatch: have round %d, reason %q", history[1].Round, history[1].EndReason)
+	}
+
+	// The history is bounded.
+	for i := int64(3); i < roundHistoryLimit+10; i++ {
+		tracer.startRound(view(i, 0), addr, common.Hash{}, false, reasonCommitted)
+	}
+	history = tracer.rounds()
+	if len(history) != roundHistoryLimit {
+		t.Fatalf("history length mismatch: have %d, want %d", len(history), roundHistoryLimit)
+	}
+	if status, _ := tracer.status(); history[len(history)-1].Sequence != status.Sequence-1 {
+		t.Errorf("last round mismatch: have %d, want %d", history[len(history)-1].Sequence, status.Sequence-1)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	N := uint64(4)
+	F := uint64(1)
+
+	sys := NewTestSystemWithBackend(N, F)
+
+	close := sys.Run(true)
+	defer close()
+
+	sys.backends[0].NewRequest(makeBlock(1))
+	<-time.After(1 * time.Second)
+	sys.backends[0].NewRequest(makeBlock(2))
+	<-time.After(1 * time.Second)
+
+	for i, backend := range sys.backends {
+		c := backend.engine.(*core)
+
+		status := c.Status()
+		if status == nil || status.Sequence != 3 || status.Round != 0 {
+			t.Fatalf("backend %d: status mismatch: have %+v", i, status)
+		}
+		history := c.RoundHistory()
+		if len(history) == 0 {
+			t.Fatalf("backend %d: empty round history", i)
+		}
+		last := history[len(history)-1]
+		if last.Sequence != 2 || last.EndReason != reasonCommitted {
+			t.Errorf("backend %d: round mismatch: have sequence %d, reason %q", i, last.Sequence, last.EndReason)
+		}
+		if len(last.Commits) < c.QuorumSize() {
+			t.Errorf("backend %d: commits mismatch: have %d, want at least %d", i, len(last.Commits), c.QuorumSize())
+		}
+	}
+}
@@ -38,6 +38,13 @@ type Engine interface {
 	// pending request is populated right at the preprepare stage so this would give us the earliest verification
 	// to avoid any race condition of coming propagated blocks
 	IsCurrentProposal(blockHash common.Hash) bool
+
+	// Status returns the state of the current consensus round, or nil if the
+	// engine hasn't started a round yet.
+	Status() *Status
+
+	// RoundHistory returns the most recent finished rounds, oldest first.
+	RoundHistory() []*RoundInfo
 }
 
 type State uint64
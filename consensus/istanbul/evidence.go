@@ -0,0 +1,44 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package istanbul
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Evidence proves that a validator equivocated, by signing two messages of the
+// same kind for different proposals in the same view.
+type Evidence struct {
+	Validator common.Address
+	View      *View
+	Type      string        // Kind of the conflicting messages, PREPARE or COMMIT
+	Digests   []common.Hash // Proposals of the conflicting messages
+	Messages  [][]byte      // Conflicting messages, with the signatures of the validator
+}
+
+// EquivocationEvent is posted when a validator is caught equivocating.
+type EquivocationEvent struct {
+	Evidence *Evidence
+}
+
+// EquivocationSubscriber is implemented by the engines reporting equivocating
+// validators, for other services to act on them.
+type EquivocationSubscriber interface {
+	// SubscribeEquivocationEvent registers a subscription of EquivocationEvent.
+	SubscribeEquivocationEvent(ch chan<- EquivocationEvent) event.Subscription
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// connected reports whether messages sent by a node reach another. The caller
+// must hold the simulation lock.
+func (s *Simulation) connected(from, to *node) bool {
+	if from == to {
+		return true
+	}
+	if from.fault == Silent {
+		return false
+	}
+	if s.partition != nil && s.partition[from.index] != s.partition[to.index] {
+		return false
+	}
+	// Each twin of an equivocating validator only talks to half of the others
+	switch {
+	case from.twin >= 0 && to.twin >= 0:
+		return false
+	case from.twin >= 0:
+		return to.index%2 == from.twin
+	case to.twin >= 0:
+		return from.index%2 == to.twin
+	}
+	return true
+}
+
+// send delivers a message to the nodes reached by the sender, after a random
+// latency, unless the message is lost.
+func (s *Simulation) send(from *node, payload []byte) {
+	hash := istanbul.RLPHash(payload)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.running {
+		return
+	}
+	for _, to := range s.nodes {
+		if to == from || !s.connected(from, to) {
+			continue
+		}
+		// Draw from the source for every message, delivered or not, so that
+		// the draws only depend on the sequence of messages
+		drop := s.rand.Float64() < s.dropRate
+		latency := s.config.MinLatency
+		if spread := s.config.MaxLatency - s.config.MinLatency; spread > 0 {
+			latency += time.Duration(s.rand.Int63n(int64(spread)))
+		}
+		if drop {
+			log.Trace("Dropping simulated message", "from", from.id, "to", to.id)
+			continue
+		}
+		to := to
+		time.AfterFunc(latency, func() {
+			if to.markKnown(hash) {
+				to.mux.Post(istanbul.MessageEvent{Payload: payload})
+			}
+		})
+	}
+}
+
+// syncLoop periodically has the nodes behind others catch up on the blocks
+// they missed, as the block synchronisation of a real network would.
+func (s *Simulation) syncLoop(quit chan struct{}) {
+	defer s.syncDoneWg.Done()
+
+	ticker := time.NewTicker(s.config.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sync()
+		case <-quit:
+			return
+		}
+	}
+}
+
+// sync imports into every node the next block of a node ahead of it, if that
+// node reaches it.
+func (s *Simulation) sync() {
+	for _, to := range s.nodes {
+		height := to.height()
+		for _, from := range s.nodes {
+			s.lock.Lock()
+			connected := s.connected(from, to)
+			s.lock.Unlock()
+			if !connected || from.height() <= height {
+				continue
+			}
+			block, seals := from.block(height + 1)
+			if err := to.importBlock(block, seals); err != nil {
+				log.Debug("Failed to import simulated block", "from", from.id, "to", to.id, "number", block.NumberU64(), "err", err)
+				continue
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	// errUnknownParent is returned when verifying a proposal which doesn't
+	// extend the node's chain.
+	errUnknownParent = errors.New("unknown parent")
+	// errInsufficientSeals is returned when importing a block without enough
+	// committed seals from distinct validators.
+	errInsufficientSeals = errors.New("insufficient committed seals")
+	// errInvalidSignature is returned when a signature isn't from the expected
+	// signer.
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// node is a validator of the simulation, running an Istanbul core on top of
+// an in-memory chain. It implements istanbul.Backend.
+type node struct {
+	id      int // Index of the node in the network, twins have their own
+	index   int // Index of the validator the node runs
+	twin    int // Which of the twins of an equivocating validator the node is, -1 otherwise
+	key     *ecdsa.PrivateKey
+	address common.Address
+	fault   Fault
+	sim     *Simulation
+
+	valSet istanbul.ValidatorSet
+	mux    *event.TypeMux
+	engine istanbulCore.Engine
+
+	lock  sync.RWMutex
+	chain []*types.Block       // Committed blocks, from the genesis
+	seals [][][]byte           // Committed seals of the committed blocks
+	known map[common.Hash]bool // Messages seen by the node
+}
+
+func newNode(sim *Simulation, id, index, twin int, key *ecdsa.PrivateKey, fault Fault, valSet istanbul.ValidatorSet, genesis *types.Block) *node {
+	n := &node{
+		id:      id,
+		index:   index,
+		twin:    twin,
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+		fault:   fault,
+		sim:     sim,
+		valSet:  valSet,
+		mux:     new(event.TypeMux),
+		chain:   []*types.Block{genesis},
+		seals:   [][][]byte{nil},
+		known:   make(map[common.Hash]bool),
+	}
+	n.engine = istanbulCore.New(n, sim.config.Istanbul)
+	return n
+}
+
+// height returns the number of the last committed block.
+func (n *node) height() uint64 {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return uint64(len(n.chain) - 1)
+}
+
+// block returns the committed block of the given number and its seals.
+func (n *node) block(number uint64) (*types.Block, [][]byte) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if number >= uint64(len(n.chain)) {
+		return nil, nil
+	}
+	return n.chain[number], n.seals[number]
+}
+
+// markKnown records that the node has seen the given message, and reports
+// whether it hadn't before.
+func (n *node) markKnown(hash common.Hash) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.known[hash] {
+		return false
+	}
+	n.known[hash] = true
+	return true
+}
+
+// request hands the core the block the node would propose on top of its chain.
+func (n *node) request() {
+	n.lock.RLock()
+	parent := n.chain[len(n.chain)-1]
+	n.lock.RUnlock()
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		Coinbase:   n.address,
+		Difficulty: common.Big1,
+		Time:       big.NewInt(time.Now().UnixNano()),
+		Extra:      []byte{byte(n.id)}, // Tells apart the proposals of twins
+	}
+	go n.mux.Post(istanbul.RequestEvent{Proposal: types.NewBlock(header, nil, nil, nil)})
+}
+
+// importBlock appends a block committed by another node, checking that its
+// seals are signed by more than F validators.
+func (n *node) importBlock(block *types.Block, seals [][]byte) error {
+	signers := make(map[common.Address]bool)
+	for _, seal := range seals {
+		addr, err := istanbul.GetSignatureAddress(istanbulCore.PrepareCommittedSeal(block.Hash()), seal)
+		if err != nil {
+			continue
+		}
+		if _, val := n.valSet.GetByAddress(addr); val != nil {
+			signers[addr] = true
+		}
+	}
+	if len(signers) <= n.valSet.F() {
+		return errInsufficientSeals
+	}
+	if err := n.append(block, seals); err != nil {
+		return err
+	}
+	go n.mux.Post(istanbul.FinalCommittedEvent{})
+	n.request()
+	return nil
+}
+
+// append extends the chain with the given block, or checks it against the
+// block committed at the same height.
+func (n *node) append(block *types.Block, seals [][]byte) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	number := block.NumberU64()
+	if number < uint64(len(n.chain)) {
+		if hash := n.chain[number].Hash(); hash != block.Hash() {
+			n.sim.reportConflict(n, number, hash, block.Hash())
+		}
+		return nil
+	}
+	if number != uint64(len(n.chain)) || block.ParentHash() != n.chain[number-1].Hash() {
+		return errUnknownParent
+	}
+	n.chain = append(n.chain, block)
+	n.seals = append(n.seals, seals)
+	return nil
+}
+
+// Address implements istanbul.Backend.Address
+func (n *node) Address() common.Address {
+	return n.address
+}
+
+// Validators implements istanbul.Backend.Validators
+func (n *node) Validators(proposal istanbul.Proposal) istanbul.ValidatorSet {
+	return n.valSet.Copy()
+}
+
+// EventMux implements istanbul.Backend.EventMux
+func (n *node) EventMux() *event.TypeMux {
+	return n.mux
+}
+
+// Broadcast implements istanbul.Backend.Broadcast
+func (n *node) Broadcast(valSet istanbul.ValidatorSet, payload []byte) error {
+	n.Gossip(valSet, payload)
+	if n.markKnown(istanbul.RLPHash(payload)) {
+		go n.mux.Post(istanbul.MessageEvent{Payload: payload})
+	}
+	return nil
+}
+
+// Gossip implements istanbul.Backend.Gossip
+func (n *node) Gossip(valSet istanbul.ValidatorSet, payload []byte) error {
+	n.sim.send(n, payload)
+	return nil
+}
+
+// Commit implements istanbul.Backend.Commit
+func (n *node) Commit(proposal istanbul.Proposal, seals [][]byte) error {
+	block, ok := proposal.(*types.Block)
+	if !ok {
+		return errUnknownParent
+	}
+	if err := n.append(block, seals); err != nil {
+		return err
+	}
+
+	go n.mux.Post(istanbul.FinalCommittedEvent{})
+	n.request()
+	return nil
+}
+
+// Verify implements istanbul.Backend.Verify
+func (n *node) Verify(proposal istanbul.Proposal) (time.Duration, error) {
+	block, ok := proposal.(*types.Block)
+	if !ok {
+		return 0, errUnknownParent
+	}
+	parent, _ := n.block(block.NumberU64() - 1)
+	if parent == nil || parent.Hash() != block.ParentHash() {
+		return 0, errUnknownParent
+	}
+	return 0, nil
+}
+
+// Sign implements istanbul.Backend.Sign
+func (n *node) Sign(data []byte) ([]byte, error) {
+	return crypto.Sign(crypto.Keccak256(data), n.key)
+}
+
+// SignCommittedSeal implements istanbul.Backend.SignCommittedSeal
+func (n *node) SignCommittedSeal(proposal istanbul.Proposal) ([]byte, error) {
+	return n.Sign(istanbulCore.PrepareCommittedSeal(proposal.Hash()))
+}
+
+// CheckSignature implements istanbul.Backend.CheckSignature
+func (n *node) CheckSignature(data []byte, addr common.Address, sig []byte) error {
+	signer, err := istanbul.GetSignatureAddress(data, sig)
+	if err != nil {
+		return err
+	}
+	if signer != addr {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// LastProposal implements istanbul.Backend.LastProposal
+func (n *node) LastProposal() (istanbul.Proposal, common.Address) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	last := n.chain[len(n.chain)-1]
+	return last, last.Coinbase()
+}
+
+// HasPropsal implements istanbul.Backend.HasPropsal
+func (n *node) HasPropsal(hash common.Hash, number *big.Int) bool {
+	block, _ := n.block(number.Uint64())
+	return block != nil && block.Hash() == hash
+}
+
+// GetProposer implements istanbul.Backend.GetProposer
+func (n *node) GetProposer(number uint64) common.Address {
+	if block, _ := n.block(number); block != nil {
+		return block.Coinbase()
+	}
+	return common.Address{}
+}
+
+// ParentValidators implements istanbul.Backend.ParentValidators
+func (n *node) ParentValidators(proposal istanbul.Proposal) istanbul.ValidatorSet {
+	return n.valSet.Copy()
+}
+
+// HasBadProposal implements istanbul.Backend.HasBadProposal
+func (n *node) HasBadProposal(hash common.Hash) bool {
+	return false
+}
+
+// ReportEquivocation implements istanbul.Backend.ReportEquivocation
+func (n *node) ReportEquivocation(evidence *istanbul.Evidence) {
+	n.sim.reportEquivocation(evidence)
+}
+
+// Close implements istanbul.Backend.Close
+func (n *node) Close() error {
+	return nil
+}
@@ -0,0 +1,337 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package simulation runs networks of Istanbul validators in memory, over a
+// virtual network with configurable latency, message loss, partitions and
+// Byzantine validators, and checks the safety and liveness of the consensus.
+//
+// Every validator runs an unmodified Istanbul core with its own key. The faults
+// of the network are drawn from a seeded source, so that a failing scenario can
+// be replayed with the same seed, although the scheduling of the cores and
+// their timers is left to the Go runtime.
+package simulation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	istanbulCore "github.com/ethereum/go-ethereum/consensus/istanbul/core"
+	"github.com/ethereum/go-ethereum/consensus/istanbul/validator"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Fault is the behaviour of a validator of the simulation.
+type Fault int
+
+const (
+	Honest       Fault = iota // Follows the protocol
+	Silent                    // Receives messages but never sends any, as a crashed or isolated validator
+	Equivocating              // Runs as two twins sharing the validator's key, each talking to half of the other validators
+)
+
+// Config is the configuration of a simulation.
+type Config struct {
+	Validators int           // Number of validators
+	Faults     map[int]Fault // Behaviour of the validators by index, honest if absent
+	Seed       int64         // Seed of the source of network faults
+
+	MinLatency   time.Duration // Minimum delay of message delivery
+	MaxLatency   time.Duration // Maximum delay of message delivery
+	DropRate     float64       // Probability for a message to be lost
+	SyncInterval time.Duration // Time between two catch ups of lagging validators with their peers
+
+	Istanbul *istanbul.Config // Configuration of the validators' cores
+}
+
+// DefaultConfig is a network of four honest validators, with short timeouts to
+// keep simulations fast.
+var DefaultConfig = Config{
+	Validators:   4,
+	MinLatency:   time.Millisecond,
+	MaxLatency:   10 * time.Millisecond,
+	SyncInterval: 100 * time.Millisecond,
+	Istanbul: &istanbul.Config{
+		RequestTimeout:              500,
+		ProposerPolicy:              istanbul.RoundRobin,
+		Epoch:                       30000,
+		Ceil2Nby3Block:              big.NewInt(0),
+		RoundChangeTimeoutGrowth:    istanbul.ExponentialGrowth,
+		RoundChangeTimeoutIncrement: 100,
+		RoundChangeTimeoutReset:     istanbul.SequenceReset,
+	},
+}
+
+var (
+	// errNotStarted is returned when waiting on a simulation which isn't running.
+	errNotStarted = errors.New("simulation not started")
+)
+
+// Simulation is a network of Istanbul validators.
+type Simulation struct {
+	config     Config
+	validators []common.Address
+	nodes      []*node
+
+	lock       sync.Mutex
+	rand       *rand.Rand
+	partition  map[int]int // Group of each validator index, nil if the network isn't partitioned
+	dropRate   float64
+	conflicts  []string
+	evidence   []*istanbul.Evidence
+	running    bool
+	quit       chan struct{}
+	syncDoneWg sync.WaitGroup
+}
+
+// New creates a simulation of the given configuration. Missing values are
+// taken from DefaultConfig.
+func New(config Config) *Simulation {
+	if config.Validators == 0 {
+		config.Validators = DefaultConfig.Validators
+	}
+	if config.MaxLatency < config.MinLatency {
+		config.MaxLatency = config.MinLatency
+	}
+	if config.SyncInterval == 0 {
+		config.SyncInterval = DefaultConfig.SyncInterval
+	}
+	if config.Istanbul == nil {
+		config.Istanbul = DefaultConfig.Istanbul
+	}
+	s := &Simulation{
+		config:   config,
+		rand:     rand.New(rand.NewSource(config.Seed)),
+		dropRate: config.DropRate,
+	}
+
+	// Keys are derived from the seed as well, for the proposers to be the same
+	// from one run to the other
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	addrs := make([]common.Address, 0, config.Validators)
+	for i := 0; i < config.Validators; i++ {
+		key, _ := crypto.ToECDSA(crypto.Keccak256(big.NewInt(config.Seed).Bytes(), big.NewInt(int64(i)).Bytes()))
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		keys[addr] = key
+		addrs = append(addrs, addr)
+	}
+	valSet := validator.NewSet(addrs, config.Istanbul.ProposerPolicy)
+	genesis := types.NewBlock(&types.Header{Number: new(big.Int), Difficulty: common.Big1}, nil, nil, nil)
+
+	// Validators are indexed in the order of the validator set, so that the
+	// indexes given in the configuration match proposer turns
+	for i, val := range valSet.List() {
+		s.validators = append(s.validators, val.Address())
+
+		fault, key := config.Faults[i], keys[val.Address()]
+		if fault == Equivocating {
+			for twin := 0; twin < 2; twin++ {
+				s.nodes = append(s.nodes, newNode(s, len(s.nodes), i, twin, key, fault, valSet.Copy(), genesis))
+			}
+			continue
+		}
+		s.nodes = append(s.nodes, newNode(s, len(s.nodes), i, -1, key, fault, valSet.Copy(), genesis))
+	}
+	return s
+}
+
+// Validators returns the addresses of the validators, by index.
+func (s *Simulation) Validators() []common.Address {
+	return s.validators
+}
+
+// Start starts the cores of all the validators.
+func (s *Simulation) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.running {
+		return istanbul.ErrStartedEngine
+	}
+	for _, n := range s.nodes {
+		if err := n.engine.Start(); err != nil {
+			return err
+		}
+	}
+	s.running = true
+	s.quit = make(chan struct{})
+	s.syncDoneWg.Add(1)
+	go s.syncLoop(s.quit)
+
+	for _, n := range s.nodes {
+		n.request()
+	}
+	return nil
+}
+
+// Stop stops the cores of all the validators.
+func (s *Simulation) Stop() {
+	s.lock.Lock()
+	if !s.running {
+		s.lock.Unlock()
+		return
+	}
+	s.running = false
+	close(s.quit)
+	s.lock.Unlock()
+
+	s.syncDoneWg.Wait()
+	for _, n := range s.nodes {
+		n.engine.Stop()
+	}
+}
+
+// Partition splits the network into the given groups of validator indexes.
+// Validators left out of every group are isolated.
+func (s *Simulation) Partition(groups ...[]int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.partition = make(map[int]int)
+	for i := range s.validators {
+		s.partition[i] = -1 - i
+	}
+	for group, indexes := range groups {
+		for _, index := range indexes {
+			s.partition[index] = group
+		}
+	}
+}
+
+// Heal reconnects all the validators after a partition.
+func (s *Simulation) Heal() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.partition = nil
+}
+
+// SetDropRate changes the probability for a message to be lost.
+func (s *Simulation) SetDropRate(rate float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dropRate = rate
+}
+
+// Heights returns the number of the last block committed by each validator,
+// the lowest of the twins for equivocating validators.
+func (s *Simulation) Heights() []uint64 {
+	heights := make([]uint64, len(s.validators))
+	seen := make(map[int]bool)
+	for _, n := range s.nodes {
+		if h := n.height(); !seen[n.index] || h < heights[n.index] {
+			heights[n.index] = h
+		}
+		seen[n.index] = true
+	}
+	return heights
+}
+
+// Status returns the status of the current consensus round of each node, twins
+// of equivocating validators included.
+func (s *Simulation) Status() []*istanbulCore.Status {
+	status := make([]*istanbulCore.Status, len(s.nodes))
+	for i, n := range s.nodes {
+		status[i] = n.engine.Status()
+	}
+	return status
+}
+
+// WaitForHeight waits until every honest validator has committed the block of
+// the given number, and fails if they don't within the timeout.
+func (s *Simulation) WaitForHeight(number uint64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		s.lock.Lock()
+		running := s.running
+		s.lock.Unlock()
+		if !running {
+			return errNotStarted
+		}
+		reached := true
+		for _, n := range s.nodes {
+			if n.fault == Honest && n.height() < number {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("liveness violation: block %d not committed within %v, heights %v", number, timeout, s.Heights())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// CheckSafety checks that no two validators committed different blocks at
+// the same height. The twins of equivocating validators are left out, as they
+// may commit a block the others haven't and fall behind.
+func (s *Simulation) CheckSafety() error {
+	s.lock.Lock()
+	conflicts := s.conflicts
+	s.lock.Unlock()
+	if len(conflicts) > 0 {
+		return fmt.Errorf("safety violation: %s", conflicts[0])
+	}
+	committed := make(map[uint64]*node)
+	for _, n := range s.nodes {
+		if n.fault == Equivocating {
+			continue
+		}
+		for number := uint64(1); number <= n.height(); number++ {
+			block, _ := n.block(number)
+			other, ok := committed[number]
+			if !ok {
+				committed[number] = n
+				continue
+			}
+			if otherBlock, _ := other.block(number); otherBlock.Hash() != block.Hash() {
+				return fmt.Errorf("safety violation: validators %d and %d committed %x and %x at block %d", other.index, n.index, otherBlock.Hash(), block.Hash(), number)
+			}
+		}
+	}
+	return nil
+}
+
+// reportConflict records a node committing a block other than the one it has
+// at the same height.
+func (s *Simulation) reportConflict(n *node, number uint64, have, want common.Hash) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.conflicts = append(s.conflicts, fmt.Sprintf("validator %d committed %x and %x at block %d", n.index, have, want, number))
+}
+
+// reportEquivocation records the evidence of an equivocating validator found by
+// a node.
+func (s *Simulation) reportEquivocation(evidence *istanbul.Evidence) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.evidence = append(s.evidence, evidence)
+}
+
+// Evidence returns the evidence of equivocating validators found by the nodes.
+func (s *Simulation) Evidence() []*istanbul.Evidence {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*istanbul.Evidence(nil), s.evidence...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+const testTimeout = 20 * time.Second
+
+func startSimulation(t *testing.T, config Config) *Simulation {
+	sim := New(config)
+	if err := sim.Start(); err != nil {
+		t.Fatalf("failed to start simulation: %v", err)
+	}
+	return sim
+}
+
+func checkSafety(t *testing.T, sim *Simulation) {
+	if err := sim.CheckSafety(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHonestValidators(t *testing.T) {
+	sim := startSimulation(t, Config{Seed: 1, MinLatency: time.Millisecond, MaxLatency: 10 * time.Millisecond})
+	defer sim.Stop()
+
+	if err := sim.WaitForHeight(5, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, sim)
+}
+
+func TestSilentValidator(t *testing.T) {
+	sim := startSimulation(t, Config{Seed: 2, Faults: map[int]Fault{0: Silent}})
+	defer sim.Stop()
+
+	// The silent validator is the first proposer, so progress needs round changes
+	if err := sim.WaitForHeight(3, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, sim)
+}
+
+func TestPartition(t *testing.T) {
+	sim := startSimulation(t, Config{Seed: 3})
+	defer sim.Stop()
+
+	if err := sim.WaitForHeight(1, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	// Neither half holds a quorum, no block can be committed
+	sim.Partition([]int{0, 1}, []int{2, 3})
+	time.Sleep(500 * time.Millisecond)
+	stalled := sim.Heights()
+	time.Sleep(time.Second)
+	for i, height := range sim.Heights() {
+		if height != stalled[i] {
+			t.Fatalf("validator %d progressed while partitioned: have %d, want %d", i, height, stalled[i])
+		}
+	}
+	checkSafety(t, sim)
+
+	sim.Heal()
+	var highest uint64
+	for _, height := range stalled {
+		if height > highest {
+			highest = height
+		}
+	}
+	if err := sim.WaitForHeight(highest+2, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, sim)
+}
+
+func TestEquivocatingValidator(t *testing.T) {
+	sim := startSimulation(t, Config{Seed: 4, Faults: map[int]Fault{1: Equivocating}})
+	defer sim.Stop()
+
+	if err := sim.WaitForHeight(4, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, sim)
+}
+
+func TestMessageLoss(t *testing.T) {
+	sim := startSimulation(t, Config{Seed: 5, DropRate: 0.1})
+	defer sim.Stop()
+
+	if err := sim.WaitForHeight(3, testTimeout); err != nil {
+		t.Fatal(err)
+	}
+	checkSafety(t, sim)
+}
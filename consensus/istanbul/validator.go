@@ -73,6 +73,18 @@ type ValidatorSet interface {
 	F() int
 	// Get proposer policy
 	Policy() ProposerPolicy
+	// Get the weight of a validator for weighted proposer selection
+	Weight(address common.Address) uint64
+	// Set the weights of validators for weighted proposer selection
+	SetWeights(weights map[common.Address]uint64)
+	// Check whether a validator recently missed its turn to propose
+	IsInactive(address common.Address) bool
+	// Set the validators which recently missed their turn to propose
+	SetInactive(addresses []common.Address)
+	// Get the seed for randomized proposer selection
+	Seed() common.Hash
+	// Set the seed for randomized proposer selection, the hash of the last block
+	SetSeed(seed common.Hash)
 }
 
 // ----------------------------------------------------------------------------
@@ -47,6 +47,10 @@ type defaultSet struct {
 	proposer    istanbul.Validator
 	validatorMu sync.RWMutex
 	selector    istanbul.ProposalSelector
+
+	weights  map[common.Address]uint64 // Weights for weighted proposer selection
+	inactive map[common.Address]bool   // Validators which recently missed their turn to propose
+	seed     common.Hash               // Seed for randomized proposer selection
 }
 
 func newDefaultSet(addrs []common.Address, policy istanbul.ProposerPolicy) *defaultSet {
@@ -65,8 +69,8 @@ func newDefaultSet(addrs []common.Address, policy istanbul.ProposerPolicy) *defa
 		valSet.proposer = valSet.GetByIndex(0)
 	}
 	valSet.selector = roundRobinProposer
-	if policy == istanbul.Sticky {
-		valSet.selector = stickyProposer
+	if selector, ok := ProposerSelector(policy); ok {
+		valSet.selector = selector
 	}
 
 	return valSet
@@ -193,9 +197,61 @@ func (valSet *defaultSet) Copy() istanbul.ValidatorSet {
 	for _, v := range valSet.validators {
 		addresses = append(addresses, v.Address())
 	}
-	return NewSet(addresses, valSet.policy)
+	cpy := newDefaultSet(addresses, valSet.policy)
+	cpy.weights = valSet.weights
+	cpy.inactive = valSet.inactive
+	cpy.seed = valSet.seed
+	return cpy
 }
 
 func (valSet *defaultSet) F() int { return int(math.Ceil(float64(valSet.Size())/3)) - 1 }
 
 func (valSet *defaultSet) Policy() istanbul.ProposerPolicy { return valSet.policy }
+
+func (valSet *defaultSet) Weight(address common.Address) uint64 {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+
+	if weight := valSet.weights[address]; weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+func (valSet *defaultSet) SetWeights(weights map[common.Address]uint64) {
+	cpy := make(map[common.Address]uint64, len(weights))
+	for address, weight := range weights {
+		cpy[address] = weight
+	}
+	valSet.validatorMu.Lock()
+	valSet.weights = cpy
+	valSet.validatorMu.Unlock()
+}
+
+func (valSet *defaultSet) Seed() common.Hash {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+	return valSet.seed
+}
+
+func (valSet *defaultSet) SetSeed(seed common.Hash) {
+	valSet.validatorMu.Lock()
+	valSet.seed = seed
+	valSet.validatorMu.Unlock()
+}
+
+func (valSet *defaultSet) IsInactive(address common.Address) bool {
+	valSet.validatorMu.RLock()
+	defer valSet.validatorMu.RUnlock()
+	return valSet.inactive[address]
+}
+
+func (valSet *defaultSet) SetInactive(addresses []common.Address) {
+	inactive := make(map[common.Address]bool, len(addresses))
+	for _, address := range addresses {
+		inactive[address] = true
+	}
+	valSet.validatorMu.Lock()
+	valSet.inactive = inactive
+	valSet.validatorMu.Unlock()
+}
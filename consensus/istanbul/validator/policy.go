@@ -0,0 +1,138 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	proposerPoliciesMu sync.RWMutex
+	proposerPolicies   = map[istanbul.ProposerPolicy]istanbul.ProposalSelector{
+		istanbul.RoundRobin:     roundRobinProposer,
+		istanbul.Sticky:         stickyProposer,
+		istanbul.WeightedRandom: weightedRandomProposer,
+		istanbul.LivenessAware:  livenessAwareProposer,
+	}
+)
+
+// RegisterProposerPolicy makes a proposer selector available under the given
+// policy. Proposer selection must be deterministic, as every validator has to
+// agree on the proposer of each round. It panics if the policy is already taken.
+func RegisterProposerPolicy(policy istanbul.ProposerPolicy, selector istanbul.ProposalSelector) {
+	proposerPoliciesMu.Lock()
+	defer proposerPoliciesMu.Unlock()
+
+	if _, ok := proposerPolicies[policy]; ok {
+		panic(fmt.Sprintf("istanbul: proposer policy %d registered twice", policy))
+	}
+	proposerPolicies[policy] = selector
+}
+
+// ProposerSelector returns the proposer selector registered under the given
+// policy.
+func ProposerSelector(policy istanbul.ProposerPolicy) (istanbul.ProposalSelector, bool) {
+	proposerPoliciesMu.RLock()
+	defer proposerPoliciesMu.RUnlock()
+
+	selector, ok := proposerPolicies[policy]
+	return selector, ok
+}
+
+// weightedRandomProposer picks proposers at random, proportionally to their
+// weight, seeded by the last block hash and proposer. Every round picks among
+// the validators not picked in the earlier rounds of the same sequence, so that
+// round changes move away from unresponsive proposers.
+func weightedRandomProposer(valSet istanbul.ValidatorSet, proposer common.Address, round uint64) istanbul.Validator {
+	size := valSet.Size()
+	if size == 0 {
+		return nil
+	}
+	var (
+		picked = make(map[common.Address]bool)
+		pick   istanbul.Validator
+		seed   = make([]byte, common.HashLength+common.AddressLength+8)
+		hash   = valSet.Seed()
+	)
+	copy(seed, hash[:])
+	copy(seed[common.HashLength:], proposer[:])
+
+	for r := uint64(0); r <= round; r++ {
+		if len(picked) == size {
+			picked = make(map[common.Address]bool)
+		}
+		binary.BigEndian.PutUint64(seed[common.HashLength+common.AddressLength:], r)
+		pick = weightedPick(valSet, crypto.Keccak256(seed), picked)
+		picked[pick.Address()] = true
+	}
+	return pick
+}
+
+// weightedPick picks a validator outside of excluded, proportionally to its
+// weight, using the given seed as source of randomness.
+func weightedPick(valSet istanbul.ValidatorSet, seed []byte, excluded map[common.Address]bool) istanbul.Validator {
+	var (
+		candidates []istanbul.Validator
+		total      = new(big.Int)
+	)
+	for _, val := range valSet.List() {
+		if !excluded[val.Address()] {
+			candidates = append(candidates, val)
+			total.Add(total, new(big.Int).SetUint64(valSet.Weight(val.Address())))
+		}
+	}
+	target := new(big.Int).Mod(new(big.Int).SetBytes(seed), total)
+	for _, val := range candidates {
+		target.Sub(target, new(big.Int).SetUint64(valSet.Weight(val.Address())))
+		if target.Sign() < 0 {
+			return val
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// livenessAwareProposer selects proposers round robin, like roundRobinProposer,
+// but skips validators which recently missed their turn to propose. If every
+// validator is inactive, none is skipped.
+func livenessAwareProposer(valSet istanbul.ValidatorSet, proposer common.Address, round uint64) istanbul.Validator {
+	size := valSet.Size()
+	if size == 0 {
+		return nil
+	}
+	start := uint64(0)
+	if !emptyAddress(proposer) {
+		start = calcSeed(valSet, proposer, 0) + 1
+	}
+	var candidates []istanbul.Validator
+	for i := uint64(0); i < uint64(size); i++ {
+		val := valSet.GetByIndex((start + i) % uint64(size))
+		if !valSet.IsInactive(val.Address()) {
+			candidates = append(candidates, val)
+		}
+	}
+	if len(candidates) == 0 {
+		return roundRobinProposer(valSet, proposer, round)
+	}
+	return candidates[round%uint64(len(candidates))]
+}
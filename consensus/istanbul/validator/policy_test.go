@@ -0,0 +1,121 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
+)
+
+func testAddresses(n int) []common.Address {
+	addrs := make([]common.Address, n)
+	for i := range addrs {
+		addrs[i] = common.BytesToAddress([]byte{byte(i + 1)})
+	}
+	return addrs
+}
+
+func TestWeightedRandomProposer(t *testing.T) {
+	addrs := testAddresses(4)
+	valSet := NewSet(addrs, istanbul.WeightedRandom)
+	valSet.SetWeights(map[common.Address]uint64{addrs[0]: 7})
+
+	// The heavy validator should propose roughly 70% of the blocks.
+	counts := make(map[common.Address]int)
+	lastProposer := common.Address{}
+	for i := 0; i < 1000; i++ {
+		valSet.SetSeed(common.BytesToHash([]byte{byte(i), byte(i >> 8)}))
+		valSet.CalcProposer(lastProposer, 0)
+		lastProposer = valSet.GetProposer().Address()
+		counts[lastProposer]++
+	}
+	if counts[addrs[0]] < 600 || counts[addrs[0]] > 800 {
+		t.Errorf("weighted validator proposed %d of 1000 blocks, want about 700", counts[addrs[0]])
+	}
+
+	// Consecutive rounds of a sequence never pick a validator twice before
+	// every validator had its turn.
+	picked := make(map[common.Address]bool)
+	for round := uint64(0); round < uint64(len(addrs)); round++ {
+		valSet.CalcProposer(addrs[1], round)
+		proposer := valSet.GetProposer().Address()
+		if picked[proposer] {
+			t.Errorf("round %d: validator %x picked twice", round, proposer)
+		}
+		picked[proposer] = true
+	}
+}
+
+func TestLivenessAwareProposer(t *testing.T) {
+	addrs := testAddresses(4)
+	valSet := NewSet(addrs, istanbul.LivenessAware)
+	valSet.SetInactive([]common.Address{addrs[1], addrs[2]})
+
+	// Without inactive validators, the policy is round robin. Otherwise the
+	// inactive ones are skipped.
+	tests := []struct {
+		lastProposer common.Address
+		round        uint64
+		want         common.Address
+	}{
+		{addrs[0], 0, addrs[3]},
+		{addrs[0], 1, addrs[0]},
+		{addrs[3], 0, addrs[0]},
+		{addrs[3], 1, addrs[3]},
+		{common.Address{}, 0, addrs[0]},
+	}
+	for i, tt := range tests {
+		valSet.CalcProposer(tt.lastProposer, tt.round)
+		if have := valSet.GetProposer().Address(); have != tt.want {
+			t.Errorf("test %d: proposer mismatch: have %x, want %x", i, have, tt.want)
+		}
+	}
+
+	// If every validator is inactive, none is skipped.
+	valSet.SetInactive(addrs)
+	valSet.CalcProposer(addrs[0], 0)
+	if have := valSet.GetProposer().Address(); have != addrs[1] {
+		t.Errorf("proposer mismatch with all validators inactive: have %x, want %x", have, addrs[1])
+	}
+
+	// Copies keep the liveness information.
+	if cpy := valSet.Copy(); !cpy.IsInactive(addrs[2]) {
+		t.Errorf("inactive validator lost in copy")
+	}
+}
+
+func TestRegisterProposerPolicy(t *testing.T) {
+	const custom = istanbul.ProposerPolicy(100)
+	RegisterProposerPolicy(custom, func(valSet istanbul.ValidatorSet, _ common.Address, _ uint64) istanbul.Validator {
+		return valSet.GetByIndex(uint64(valSet.Size() - 1))
+	})
+	addrs := testAddresses(3)
+	valSet := NewSet(addrs, custom)
+	valSet.CalcProposer(addrs[0], 0)
+	if have := valSet.GetProposer().Address(); have != addrs[2] {
+		t.Errorf("proposer mismatch: have %x, want %x", have, addrs[2])
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering a policy twice did not panic")
+		}
+	}()
+	RegisterProposerPolicy(istanbul.RoundRobin, roundRobinProposer)
+}
@@ -20,6 +20,7 @@ package consensus
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
 // Constants to match up protocol versions and messages
@@ -52,10 +53,24 @@ type Broadcaster interface {
 	Enqueue(id string, block *types.Block)
 	// FindPeers retrives peers by addresses
 	FindPeers(map[common.Address]bool) map[common.Address]Peer
+	// Peers retrieves all the connected peers by addresses
+	Peers() map[common.Address]Peer
 }
 
 // Peer defines the interface to communicate with peer
 type Peer interface {
 	// Send sends the message to this peer
 	Send(msgcode uint64, data interface{}) error
+	// Version returns the version of the protocol spoken with this peer
+	Version() int
+}
+
+// PeerConnector defines the interface to connect to and disconnect from nodes
+type PeerConnector interface {
+	// Self returns the local node
+	Self() *enode.Node
+	// AddPeer connects to the given node, and keeps reconnecting when disconnected
+	AddPeer(node *enode.Node)
+	// RemovePeer disconnects from the given node
+	RemovePeer(node *enode.Node)
 }
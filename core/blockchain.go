@@ -135,6 +135,8 @@ type BlockChain struct {
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
 
 	privateStateCache state.Database // Private state database to reuse between imports (contains state cache)
+	privateTriegc     *prque.Prque   // Priority queue mapping block numbers to private tries to gc
+	tenants           []string       // Enclave keys of the tenants with a private state of their own
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -171,6 +173,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		vmConfig:          vmConfig,
 		badBlocks:         badBlocks,
 		privateStateCache: state.NewDatabase(db),
+		privateTriegc:     prque.New(nil),
 	}
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
@@ -236,9 +239,11 @@ func (bc *BlockChain) loadLastState() error {
 	}
 
 	// Quorum
-	if _, err := state.New(GetPrivateStateRoot(bc.db, currentBlock.Root()), bc.privateStateCache); err != nil {
-		log.Warn("Head private state missing, resetting chain", "number", currentBlock.Number(), "hash", currentBlock.Hash())
-		return bc.Reset()
+	if !bc.hasPrivateStates(currentBlock.Root()) {
+		log.Warn("Head private state missing, repairing chain", "number", currentBlock.Number(), "hash", currentBlock.Hash())
+		if err := bc.repair(&currentBlock); err != nil {
+			return err
+		}
 	}
 	// /Quorum
 
@@ -293,6 +298,14 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
 
+	// Discard the frozen blocks past the new head, the freezer only holding the
+	// canonical chain
+	if ancients, ok := bc.db.(rawdb.AncientStore); ok && ancients.Ancients() > head+1 {
+		if err := ancients.TruncateAncients(head + 1); err != nil {
+			return err
+		}
+	}
+
 	// Clear out any stale content from the caches
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
@@ -455,6 +468,7 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *types.Block) error {
 // repair tries to repair the current blockchain by rolling back the current block
 // until one with associated state is found. This is needed to fix incomplete db
 // writes caused either by crashes/power outages, or simply non-committed tries.
+// Both the public and the private state of the block must be available.
 //
 // This method only rolls back the current block. The current header and current
 // fast block are left intact.
@@ -462,8 +476,10 @@ func (bc *BlockChain) repair(head **types.Block) error {
 	for {
 		// Abort if we've rewound to a head block that does have associated state
 		if _, err := state.New((*head).Root(), bc.stateCache); err == nil {
-			log.Info("Rewound blockchain to past state", "number", (*head).Number(), "hash", (*head).Hash())
-			return nil
+			if bc.hasPrivateStates((*head).Root()) {
+				log.Info("Rewound blockchain to past state", "number", (*head).Number(), "hash", (*head).Hash())
+				return nil
+			}
 		}
 		// Otherwise rewind one block and recheck state availability there
 		(*head) = bc.GetBlock((*head).ParentHash(), (*head).NumberU64()-1)
@@ -584,8 +600,11 @@ func (bc *BlockChain) HasBlock(hash common.Hash, number uint64) bool {
 
 // HasState checks if state trie is fully present in the database or not.
 func (bc *BlockChain) HasState(hash common.Hash) bool {
-	_, err := bc.stateCache.OpenTrie(hash)
-	return err == nil
+	if _, err := bc.stateCache.OpenTrie(hash); err != nil {
+		return false
+	}
+	// Quorum: the private states are garbage collected as well
+	return bc.hasPrivateStates(hash)
 }
 
 // HasBlockAndState checks if a block and associated state trie is fully present
@@ -650,6 +669,36 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetPublicReceiptsByHash retrieves the receipts of a block as the public state
+// records them, which is what the block header commits to. The receipts stored
+// for private transactions are replaced by their public counterpart, a success
+// without logs, so that fast syncing peers can verify them. Receipts before
+// Byzantium carry the intermediate public state root, which isn't stored for
+// private transactions, and are returned as stored.
+func (bc *BlockChain) GetPublicReceiptsByHash(hash common.Hash) types.Receipts {
+	receipts := bc.GetReceiptsByHash(hash)
+	if receipts == nil || !bc.chainConfig.IsQuorum {
+		return receipts
+	}
+	block := bc.GetBlockByHash(hash)
+	if block == nil || !bc.chainConfig.IsByzantium(block.Number()) || len(block.Transactions()) != len(receipts) {
+		return receipts
+	}
+	public := make(types.Receipts, len(receipts))
+	for i, tx := range block.Transactions() {
+		public[i] = receipts[i]
+		if tx.IsPrivate() {
+			receipt := *receipts[i]
+			receipt.PostState = nil
+			receipt.Status = types.ReceiptStatusSuccessful
+			receipt.Logs = []*types.Log{}
+			receipt.Bloom = types.Bloom{}
+			public[i] = &receipt
+		}
+	}
+	return public
+}
+
 // GetBlocksFromHash returns the block corresponding to hash and up to n-1 ancestors.
 // [deprecated by eth/62]
 func (bc *BlockChain) GetBlocksFromHash(hash common.Hash, n int) (blocks []*types.Block) {
@@ -686,6 +735,17 @@ func (bc *BlockChain) TrieNode(hash common.Hash) ([]byte, error) {
 	return bc.stateCache.TrieDB().Node(hash)
 }
 
+// PrivateTrieNode retrieves a blob of data associated with a trie node (or code
+// hash) of the public or private state, including the private state nodes not
+// yet flushed to disk. It must only serve the party peers syncing the private
+// state of a checkpoint.
+func (bc *BlockChain) PrivateTrieNode(hash common.Hash) ([]byte, error) {
+	if node, err := bc.stateCache.TrieDB().Node(hash); err == nil {
+		return node, nil
+	}
+	return bc.privateStateCache.TrieDB().Node(hash)
+}
+
 // Stop stops the blockchain service. If any imports are currently in progress
 // it will abort them using the procInterrupt.
 func (bc *BlockChain) Stop() {
@@ -706,6 +766,7 @@ func (bc *BlockChain) Stop() {
 	//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
 	if !bc.cacheConfig.Disabled {
 		triedb := bc.stateCache.TrieDB()
+		privateTriedb := bc.privateStateCache.TrieDB()
 
 		for _, offset := range []uint64{0, 1, triesInMemory - 1} {
 			if number := bc.CurrentBlock().NumberU64(); number > offset {
@@ -715,14 +776,25 @@ func (bc *BlockChain) Stop() {
 				if err := triedb.Commit(recent.Root(), true); err != nil {
 					log.Error("Failed to commit recent state trie", "err", err)
 				}
+				for _, privateRoot := range bc.privateStateRoots(recent.Root()) {
+					if err := privateTriedb.Commit(privateRoot, true); err != nil {
+						log.Error("Failed to commit recent private state trie", "err", err)
+					}
+				}
 			}
 		}
 		for !bc.triegc.Empty() {
 			triedb.Dereference(bc.triegc.PopItem().(common.Hash))
 		}
+		for !bc.privateTriegc.Empty() {
+			privateTriedb.Dereference(bc.privateTriegc.PopItem().(common.Hash))
+		}
 		if size, _ := triedb.Size(); size != 0 {
 			log.Error("Dangling trie nodes after full cleanup")
 		}
+		if size, _ := privateTriedb.Size(); size != 0 {
+			log.Error("Dangling private trie nodes after full cleanup")
+		}
 	}
 	log.Info("Blockchain manager stopped")
 }
@@ -953,28 +1025,35 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	}
 	triedb := bc.stateCache.TrieDB()
 
-	// Explicit commit for privateStateTriedb to handle Raft db issues
+	// Quorum: the private state roots, the default and the tenant ones, were
+	// already written by the caller, which may or may not hand over the default
+	// private state to commit
+	privateTriedb := bc.privateStateCache.TrieDB()
 	if privateState != nil {
-		privateRoot, err := privateState.Commit(bc.chainConfig.IsEIP158(block.Number()))
-		if err != nil {
-			return NonStatTy, err
-		}
-		privateTriedb := bc.privateStateCache.TrieDB()
-		if err := privateTriedb.Commit(privateRoot, false); err != nil {
+		if _, err := privateState.Commit(bc.chainConfig.IsEIP158(block.Number())); err != nil {
 			return NonStatTy, err
 		}
 	}
+	privateRoots := bc.privateStateRoots(root)
 
 	// If we're running an archive node, always flush
 	if bc.cacheConfig.Disabled {
 		if err := triedb.Commit(root, false); err != nil {
 			return NonStatTy, err
 		}
-
+		for _, privateRoot := range privateRoots {
+			if err := privateTriedb.Commit(privateRoot, false); err != nil {
+				return NonStatTy, err
+			}
+		}
 	} else {
 		// Full but not archive node, do proper garbage collection
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		bc.triegc.Push(root, -int64(block.NumberU64()))
+		for _, privateRoot := range privateRoots {
+			privateTriedb.Reference(privateRoot, common.Hash{})
+			bc.privateTriegc.Push(privateRoot, -int64(block.NumberU64()))
+		}
 
 		if current := block.NumberU64(); current > triesInMemory {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
@@ -985,6 +1064,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 			if nodes > limit || imgs > 4*1024*1024 {
 				triedb.Cap(limit - ethdb.IdealBatchSize)
 			}
+			if nodes, imgs := privateTriedb.Size(); nodes > limit || imgs > 4*1024*1024 {
+				privateTriedb.Cap(limit - ethdb.IdealBatchSize)
+			}
 			// Find the next state trie we need to commit
 			header := bc.GetHeaderByNumber(current - triesInMemory)
 			chosen := header.Number.Uint64()
@@ -998,6 +1080,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 				}
 				// Flush an entire trie and restart the counters
 				triedb.Commit(header.Root, true)
+				for _, privateRoot := range bc.privateStateRoots(header.Root) {
+					privateTriedb.Commit(privateRoot, true)
+				}
 				lastWrite = chosen
 				bc.gcproc = 0
 			}
@@ -1010,6 +1095,14 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 				}
 				triedb.Dereference(root.(common.Hash))
 			}
+			for !bc.privateTriegc.Empty() {
+				root, number := bc.privateTriegc.Pop()
+				if uint64(-number) > chosen {
+					bc.privateTriegc.Push(root, number)
+					break
+				}
+				privateTriedb.Dereference(root.(common.Hash))
+			}
 		}
 	}
 
@@ -1282,6 +1375,10 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 
 		proctime := time.Since(bstart)
 
+		// Quorum: the tenant private states are written along with the block
+		if err := bc.ProcessTenants(block); err != nil {
+			return i, events, coalescedLogs, err
+		}
 		// Write the block to the chain and get the status.
 		status, err := bc.WriteBlockWithState(block, allReceipts, state, privateState)
 		if err != nil {
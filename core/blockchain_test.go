@@ -17,9 +17,12 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"math/rand"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -34,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
 )
 
 // So we can deterministically seed different blockchains
@@ -688,6 +692,133 @@ func TestFastVsFullChains(t *testing.T) {
 	}
 }
 
+// Tests that the blocks moved to the freezer are still served by the chain
+// accessors, and that rewinding the chain discards the frozen blocks past the
+// new head.
+func TestFreezerBlocks(t *testing.T) {
+	var (
+		db      = ethdb.NewMemDatabase()
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, receipts := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 64, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), common.Address{0x00}, big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+	chain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to process block %d: %v", n, err)
+	}
+	chain.Stop()
+
+	privateReceipt := types.NewReceipt(nil, false, 0)
+	privateReceipt.Logs = []*types.Log{{Address: common.Address{0x01}}}
+	WritePrivateBlockBloom(db, 10, types.Receipts{privateReceipt})
+
+	// Freeze all but the 16 most recent blocks
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	frdb, err := rawdb.NewDatabaseWithFreezer(db, dir, 16)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	defer frdb.Close()
+
+	ancients := frdb.(rawdb.AncientStore)
+	for deadline := time.Now().Add(5 * time.Second); ancients.Ancients() < 48; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("blocks not frozen: have %d, want %d", ancients.Ancients(), 48)
+		}
+	}
+	if rawdb.HasHeader(db, blocks[9].Hash(), 10) {
+		t.Fatalf("frozen header left in the key-value store")
+	}
+	chain, err = NewBlockChain(frdb, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain over the freezer: %v", err)
+	}
+	defer chain.Stop()
+
+	for i, block := range blocks {
+		if have := chain.GetBlock(block.Hash(), block.NumberU64()); have == nil || have.Hash() != block.Hash() || len(have.Transactions()) != 1 {
+			t.Errorf("block #%d: block mismatch", block.NumberU64())
+		}
+		if have := chain.GetReceiptsByHash(block.Hash()); types.DeriveSha(have) != types.DeriveSha(receipts[i]) {
+			t.Errorf("block #%d: receipts mismatch", block.NumberU64())
+		}
+	}
+	if bloom := GetPrivateBlockBloom(frdb, 10); bloom != types.CreateBloom(types.Receipts{privateReceipt}) {
+		t.Errorf("frozen private bloom mismatch")
+	}
+	if err := chain.SetHead(20); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	if have := ancients.Ancients(); have != 21 {
+		t.Errorf("ancient count mismatch after rewind: have %d, want %d", have, 21)
+	}
+	if chain.GetBlockByNumber(30) != nil {
+		t.Errorf("rewound block still served")
+	}
+}
+
+// Tests that the receipts served to fast syncing peers are those the header
+// commits to, the private receipts stored by a party node being replaced.
+func TestPublicReceipts(t *testing.T) {
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	config := *params.QuorumTestChainConfig
+	config.ByzantiumBlock = big.NewInt(0)
+	var (
+		gspec   = &Genesis{Config: &config}
+		gendb   = ethdb.NewMemDatabase()
+		genesis = gspec.MustCommit(gendb)
+		key, _  = crypto.GenerateKey()
+	)
+	tx, _ := types.SignTx(types.NewContractCreation(0, new(big.Int), 1000000, new(big.Int), crypto.Keccak512([]byte("payload"))), types.HomesteadSigner{}, key)
+	tx.SetPrivate()
+
+	private.P = &StubPrivateTransactionManager{responses: map[string][]interface{}{"Receive": {nil, errors.New("not a recipient")}}}
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), gendb, 1, func(i int, b *BlockGen) {
+		b.AddTx(tx)
+	})
+	// Import it on a node party to a contract failing on creation
+	private.P = &StubPrivateTransactionManager{responses: map[string][]interface{}{"Receive": {common.Hex2Bytes("fe"), nil}}}
+
+	db := ethdb.NewMemDatabase()
+	gspec.MustCommit(db)
+	chain, _ := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	if receipts := chain.GetReceiptsByHash(blocks[0].Hash()); len(receipts) != 1 || receipts[0].Status != types.ReceiptStatusFailed {
+		t.Fatalf("private receipt not stored: %v", receipts)
+	}
+	receipts := chain.GetPublicReceiptsByHash(blocks[0].Hash())
+	if hash := types.DeriveSha(receipts); hash != blocks[0].ReceiptHash() {
+		t.Errorf("public receipts hash mismatch: have %x, want %x", hash, blocks[0].ReceiptHash())
+	}
+	if stored := chain.GetReceiptsByHash(blocks[0].Hash()); stored[0].Status != types.ReceiptStatusFailed {
+		t.Errorf("stored private receipt modified")
+	}
+}
+
 // Tests that various import methods move the chain head pointers to the correct
 // positions.
 func TestLightVsFastVsFullChainHeads(t *testing.T) {
@@ -1375,6 +1506,46 @@ func TestLargeReorgTrieGC(t *testing.T) {
 	}
 }
 
+// Tests that a chain whose head private state went missing rewinds to the last
+// block with both the public and the private state, instead of resetting.
+func TestPrivateStateRepair(t *testing.T) {
+	engine := ethash.NewFaker()
+
+	db := ethdb.NewMemDatabase()
+	genesis := new(Genesis).MustCommit(db)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 8, func(i int, b *BlockGen) { b.SetCoinbase(common.Address{1}) })
+
+	diskdb := ethdb.NewMemDatabase()
+	new(Genesis).MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, &CacheConfig{Disabled: true}, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert into chain: %v", err)
+	}
+	chain.Stop()
+
+	// Point the head block to a private state which was never written
+	head := blocks[len(blocks)-1]
+	if err := WritePrivateStateRoot(diskdb, head.Root(), common.HexToHash("0xdead")); err != nil {
+		t.Fatal(err)
+	}
+	chain, err = NewBlockChain(diskdb, &CacheConfig{Disabled: true}, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to recreate tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if have, want := chain.CurrentBlock().NumberU64(), head.NumberU64()-1; have != want {
+		t.Errorf("head block mismatch: have %d, want %d", have, want)
+	}
+	if chain.HasState(head.Root()) {
+		t.Errorf("state reported available without its private state")
+	}
+}
+
 // Benchmarks large blocks with value transfers to non-existing accounts
 func benchmarkLargeNumberOfValueToNonexisting(b *testing.B, numTxs, numBlocks int, recipientFn func(uint64) common.Address, dataFn func(uint64) []byte) {
 	var (
@@ -25,7 +25,9 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -73,12 +75,9 @@ var (
 	preimageCounter    = metrics.NewCounter()
 	preimageHitCounter = metrics.NewCounter()
 
-	privateRootPrefix          = []byte("P")
+	// Quorum: the private state root, bloom and tenant prefixes are defined in rawdb
 	privateblockReceiptsPrefix = []byte("Pr") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 	privateReceiptPrefix       = []byte("Prs")
-	privateBloomPrefix         = []byte("Pb")
-
-	quorumEIP155ActivatedPrefix = []byte("quorum155active")
 )
 
 // txLookupEntry is a positional metadata to help looking up the data content of
@@ -122,7 +121,7 @@ func GetBlockNumber(db DatabaseReader, hash common.Hash) uint64 {
 //returns whether we have a chain configuration that can't be updated
 //after the EIP155 HF has happened
 func GetIsQuorumEIP155Activated(db DatabaseReader) bool {
-	data, _ := db.Get(quorumEIP155ActivatedPrefix)
+	data, _ := db.Get(rawdb.QuorumEIP155ActivatedKey)
 	return len(data) == 1
 }
 
@@ -606,7 +605,7 @@ func WriteChainConfig(db ethdb.Putter, hash common.Hash, cfg *params.ChainConfig
 
 // WriteQuorumEIP155Activation writes a flag to the database saying EIP155 HF is enforced
 func WriteQuorumEIP155Activation(db ethdb.Putter) error {
-	return db.Put(quorumEIP155ActivatedPrefix, []byte{1})
+	return db.Put(rawdb.QuorumEIP155ActivatedKey, []byte{1})
 }
 
 // GetChainConfig will fetch the network settings based on the given hash.
@@ -652,26 +651,108 @@ func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 }
 
 func GetPrivateStateRoot(db ethdb.Database, blockRoot common.Hash) common.Hash {
-	root, _ := db.Get(append(privateRootPrefix, blockRoot[:]...))
+	root, _ := db.Get(append(rawdb.PrivateRootPrefix, blockRoot[:]...))
 	return common.BytesToHash(root)
 }
 
 func WritePrivateStateRoot(db ethdb.Database, blockRoot, root common.Hash) error {
-	return db.Put(append(privateRootPrefix, blockRoot[:]...), root[:])
+	return db.Put(append(rawdb.PrivateRootPrefix, blockRoot[:]...), root[:])
 }
 
 // WritePrivateBlockBloom creates a bloom filter for the given receipts and saves it to the database
 // with the number given as identifier (i.e. block number).
 func WritePrivateBlockBloom(db ethdb.Database, number uint64, receipts types.Receipts) error {
 	rbloom := types.CreateBloom(receipts)
-	return db.Put(append(privateBloomPrefix, encodeBlockNumber(number)...), rbloom[:])
+	return db.Put(append(rawdb.PrivateBloomPrefix, encodeBlockNumber(number)...), rbloom[:])
 }
 
 // GetPrivateBlockBloom retrieves the private bloom associated with the given number.
 func GetPrivateBlockBloom(db ethdb.Database, number uint64) (bloom types.Bloom) {
-	data, _ := db.Get(append(privateBloomPrefix, encodeBlockNumber(number)...))
+	data, _ := db.Get(append(rawdb.PrivateBloomPrefix, encodeBlockNumber(number)...))
 	if len(data) > 0 {
 		bloom = types.BytesToBloom(data)
 	}
 	return bloom
 }
+
+// tenantKey returns the database key of the given tenant data, the tenant
+// being identified by the hash of its enclave key.
+func tenantKey(prefix []byte, tenant string, suffix []byte) []byte {
+	key := append(append([]byte{}, prefix...), crypto.Keccak256([]byte(tenant))...)
+	return append(key, suffix...)
+}
+
+// GetTenantPrivateStateRoot retrieves the root of the private state of the
+// given tenant at the given block, the default private state if tenant is empty.
+func GetTenantPrivateStateRoot(db ethdb.Database, tenant string, blockRoot common.Hash) common.Hash {
+	if tenant == "" {
+		return GetPrivateStateRoot(db, blockRoot)
+	}
+	root, _ := db.Get(tenantKey(rawdb.TenantRootPrefix, tenant, blockRoot[:]))
+	return common.BytesToHash(root)
+}
+
+// WriteTenantPrivateStateRoot stores the root of the private state of the given
+// tenant at the given block.
+func WriteTenantPrivateStateRoot(db ethdb.Database, tenant string, blockRoot, root common.Hash) error {
+	if tenant == "" {
+		return WritePrivateStateRoot(db, blockRoot, root)
+	}
+	return db.Put(tenantKey(rawdb.TenantRootPrefix, tenant, blockRoot[:]), root[:])
+}
+
+// WriteTenantPrivateBlockBloom creates a bloom filter for the given private
+// receipts of a tenant and saves it to the database with the block number.
+func WriteTenantPrivateBlockBloom(db ethdb.Database, tenant string, number uint64, receipts types.Receipts) error {
+	if tenant == "" {
+		return WritePrivateBlockBloom(db, number, receipts)
+	}
+	rbloom := types.CreateBloom(receipts)
+	return db.Put(tenantKey(rawdb.TenantBloomPrefix, tenant, encodeBlockNumber(number)), rbloom[:])
+}
+
+// GetTenantPrivateBlockBloom retrieves the private bloom of the given tenant
+// associated with the given number.
+func GetTenantPrivateBlockBloom(db ethdb.Database, tenant string, number uint64) (bloom types.Bloom) {
+	if tenant == "" {
+		return GetPrivateBlockBloom(db, number)
+	}
+	data, _ := db.Get(tenantKey(rawdb.TenantBloomPrefix, tenant, encodeBlockNumber(number)))
+	if len(data) > 0 {
+		bloom = types.BytesToBloom(data)
+	}
+	return bloom
+}
+
+// WriteTenantPrivateReceipts stores the receipts of the private transactions of
+// a block as processed against the private state of the given tenant.
+func WriteTenantPrivateReceipts(db ethdb.Database, tenant string, hash common.Hash, receipts types.Receipts) error {
+	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+	for i, receipt := range receipts {
+		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	bytes, err := rlp.EncodeToBytes(storageReceipts)
+	if err != nil {
+		return err
+	}
+	return db.Put(tenantKey(rawdb.TenantReceiptsPrefix, tenant, hash[:]), bytes)
+}
+
+// GetTenantPrivateReceipts retrieves the receipts of the private transactions
+// of a block as processed against the private state of the given tenant.
+func GetTenantPrivateReceipts(db ethdb.Database, tenant string, hash common.Hash) types.Receipts {
+	data, _ := db.Get(tenantKey(rawdb.TenantReceiptsPrefix, tenant, hash[:]))
+	if len(data) == 0 {
+		return nil
+	}
+	storageReceipts := []*types.ReceiptForStorage{}
+	if err := rlp.DecodeBytes(data, &storageReceipts); err != nil {
+		log.Error("Invalid tenant receipt array RLP", "hash", hash, "err", err)
+		return nil
+	}
+	receipts := make(types.Receipts, len(storageReceipts))
+	for i, receipt := range storageReceipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	return receipts
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -404,3 +405,34 @@ func TestIsQuorumEIP155Active(t *testing.T) {
 		t.Fatal("Quorum EIP155 active read to be unset, but was set beforehand")
 	}
 }
+
+// Tests that the database inspection accounts for the Quorum private state.
+func TestInspectPrivateStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	receipts := types.Receipts{types.NewReceipt(nil, false, 0)}
+	WritePrivateStateRoot(db, common.HexToHash("0x01"), common.HexToHash("0x02"))
+	WritePrivateBlockBloom(db, 1, receipts)
+	WriteTenantPrivateStateRoot(db, "A", common.HexToHash("0x01"), common.HexToHash("0x03"))
+	WriteTenantPrivateBlockBloom(db, "A", 1, receipts)
+	WriteTenantPrivateReceipts(db, "A", common.HexToHash("0x04"), receipts)
+	WriteQuorumEIP155Activation(db)
+
+	stats, err := rawdb.InspectDatabase(db)
+	if err != nil {
+		t.Fatalf("failed to inspect database: %v", err)
+	}
+	want := map[string]int{
+		"Private state roots":        1,
+		"Private blooms":             1,
+		"Tenant private state roots": 1,
+		"Tenant private blooms":      1,
+		"Tenant private receipts":    1,
+		"Metadata":                   1,
+	}
+	for _, stat := range stats {
+		if stat.Count != want[stat.Category] {
+			t.Errorf("%s: entry count mismatch: have %d, want %d", stat.Category, stat.Count, want[stat.Category])
+		}
+	}
+}
@@ -0,0 +1,537 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ExtensionAddress is the address the private transactions of the contract
+// extension workflow are sent to. The workflow is recorded in the private state
+// of this address, on the nodes party to the extension.
+var ExtensionAddress = common.BytesToAddress(crypto.Keccak256([]byte("quorum-contract-extension")))
+
+// Topics of the logs recording the progress of contract extensions, the second
+// topic being the extension ID.
+var (
+	ExtensionProposedTopic = crypto.Keccak256Hash([]byte("ExtensionProposed(bytes32)"))
+	ExtensionAcceptedTopic = crypto.Keccak256Hash([]byte("ExtensionAccepted(bytes32)"))
+	ExtensionRejectedTopic = crypto.Keccak256Hash([]byte("ExtensionRejected(bytes32)"))
+	ExtensionSharedTopic   = crypto.Keccak256Hash([]byte("ExtensionShared(bytes32,bytes32)"))
+)
+
+var (
+	// ErrInvalidExtension is returned for a malformed contract extension message.
+	ErrInvalidExtension = errors.New("invalid contract extension message")
+	// ErrUnknownExtension is returned if a contract extension isn't known.
+	ErrUnknownExtension = errors.New("unknown contract extension")
+	// ErrExtensionStatus is returned if a contract extension isn't at the step of
+	// the workflow the message is for.
+	ErrExtensionStatus = errors.New("contract extension status mismatch")
+	// ErrExtensionUnauthorized is returned if the sender of a contract extension
+	// message isn't allowed to send it.
+	ErrExtensionUnauthorized = errors.New("unauthorized contract extension message")
+	// ErrExtensionStateHash is returned if the state of a contract extension
+	// doesn't match the hash committed to by its approvers.
+	ErrExtensionStateHash = errors.New("contract extension state hash mismatch")
+	// ErrExtensionTooLarge is returned if the shared state of a contract exceeds
+	// the size limits.
+	ErrExtensionTooLarge = errors.New("contract extension state too large")
+)
+
+// MaxExtensionSlots is the maximum number of storage slots of a shared contract.
+const MaxExtensionSlots = 1 << 14
+
+// Codes of the contract extension messages.
+const (
+	ExtensionProposeMsg = iota // An existing party proposes to share a contract with a new party
+	ExtensionVoteMsg           // An approver accepts or rejects the extension
+	ExtensionShareMsg          // An existing party shares the contract state once accepted
+)
+
+// ExtensionStatus is the step of the workflow a contract extension is at.
+type ExtensionStatus uint8
+
+const (
+	ExtensionUnknown ExtensionStatus = iota
+	ExtensionPending
+	ExtensionAccepted
+	ExtensionRejected
+	ExtensionShared
+)
+
+func (s ExtensionStatus) String() string {
+	switch s {
+	case ExtensionPending:
+		return "pending"
+	case ExtensionAccepted:
+		return "accepted"
+	case ExtensionRejected:
+		return "rejected"
+	case ExtensionShared:
+		return "shared"
+	default:
+		return "unknown"
+	}
+}
+
+// ExtensionMessage is the payload of a private transaction sent to ExtensionAddress.
+type ExtensionMessage struct {
+	Code    uint64
+	Payload []byte
+}
+
+// ExtensionProposal proposes to share Contract with the party of the Recipient
+// public key of the private transaction manager and of the RecipientAccount,
+// once all Approvers accepted. The approvers must be the existing participants
+// of the contract along with the recipient account, and CodeHash the hash of
+// the code of the contract.
+type ExtensionProposal struct {
+	Contract         common.Address
+	CodeHash         common.Hash
+	Recipient        []byte
+	RecipientAccount common.Address
+	Approvers        []common.Address
+}
+
+// ExtensionVote accepts or rejects the extension of the given ID. An existing
+// participant accepting the extension commits to the hash of the state of the
+// contract as of the block the extension was proposed at.
+type ExtensionVote struct {
+	ID        common.Hash
+	Accept    bool
+	StateHash common.Hash
+}
+
+// ExtensionShare carries the state of the contract of the extension of the
+// given ID, as of the block the extension was proposed at.
+type ExtensionShare struct {
+	ID    common.Hash
+	State *ExtensionState
+}
+
+// ExtensionState is the private state of a contract shared with a new party.
+type ExtensionState struct {
+	Code    []byte
+	Nonce   uint64
+	Storage []ExtensionSlot // Sorted by key
+}
+
+// ExtensionSlot is a storage slot of a shared contract.
+type ExtensionSlot struct {
+	Key   common.Hash
+	Value common.Hash
+}
+
+// Hash returns the hash the parties compare to verify the shared state.
+func (s *ExtensionState) Hash() common.Hash {
+	blob, _ := rlp.EncodeToBytes(s)
+	return crypto.Keccak256Hash(blob)
+}
+
+// ExtensionStateReader is the part of a state database the extension records
+// and the contract state to share are read from.
+type ExtensionStateReader interface {
+	GetState(addr common.Address, key common.Hash) common.Hash
+	GetCode(addr common.Address) []byte
+	GetNonce(addr common.Address) uint64
+	StorageTrie(addr common.Address) state.Trie
+}
+
+// NewExtensionState reads the state of the given contract to share.
+func NewExtensionState(statedb ExtensionStateReader, contract common.Address) (*ExtensionState, error) {
+	s := &ExtensionState{
+		Code:  statedb.GetCode(contract),
+		Nonce: statedb.GetNonce(contract),
+	}
+	if len(s.Code) == 0 {
+		return nil, errors.New("no contract code at the given address")
+	}
+	storage := statedb.StorageTrie(contract)
+	if storage == nil {
+		return s, nil
+	}
+	it := trie.NewIterator(storage.NodeIterator(nil))
+	for it.Next() {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		preimage := storage.GetKey(it.Key)
+		if preimage == nil {
+			return nil, errors.New("missing storage key preimage")
+		}
+		s.Storage = append(s.Storage, ExtensionSlot{
+			Key:   common.BytesToHash(preimage),
+			Value: common.BytesToHash(content),
+		})
+	}
+	sort.Slice(s.Storage, func(i, j int) bool {
+		return s.Storage[i].Key.Big().Cmp(s.Storage[j].Key.Big()) < 0
+	})
+	return s, it.Err
+}
+
+// ExtensionID returns the ID of the extension proposed by the transaction of
+// the given sender and nonce.
+func ExtensionID(proposer common.Address, nonce uint64) common.Hash {
+	blob, _ := rlp.EncodeToBytes([]interface{}{proposer, nonce})
+	return crypto.Keccak256Hash(blob)
+}
+
+// Extension is the record of a contract extension.
+type Extension struct {
+	ID               common.Hash
+	Contract         common.Address
+	CodeHash         common.Hash
+	Proposer         common.Address
+	Recipient        []byte
+	RecipientAccount common.Address
+	Approvers        []common.Address
+	Pending          uint64 // Number of approvers yet to vote
+	Status           ExtensionStatus
+	ProposedAt       uint64
+	AcceptedAt       uint64
+	StateHash        common.Hash // Committed to by the approvers
+}
+
+// Fields of an extension record, each stored in the slot hashed from the
+// extension ID, the field and possibly an index or an address.
+const (
+	extensionStatusField byte = iota
+	extensionContractField
+	extensionProposerField
+	extensionRecipientField
+	extensionApproverCountField
+	extensionApproverField
+	extensionVoteField
+	extensionPendingField
+	extensionAcceptedAtField
+	extensionStateHashField
+	extensionRecipientAccountField
+	extensionProposedAtField
+	extensionCodeHashField
+)
+
+// Fields of the record of the participants of a contract, each stored in the
+// slot hashed from the contract address, the field and possibly an index.
+const (
+	extensionParticipantCountField byte = 0x80 + iota
+	extensionParticipantField
+	extensionFrozenField // ID of the extension the contract is frozen for
+)
+
+// Votes of the approvers of an extension.
+const (
+	extensionVoteNone = iota
+	extensionVotePending
+	extensionVoteAccepted
+)
+
+// extensionSlot returns the storage slot of a field of the given extension.
+func extensionSlot(id common.Hash, field byte, extra ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(append([][]byte{id[:], {field}}, extra...)...)
+}
+
+// ReadExtension retrieves the record of the given extension from the private
+// state, nil if unknown.
+func ReadExtension(statedb ExtensionStateReader, id common.Hash) *Extension {
+	get := func(field byte, extra ...[]byte) common.Hash {
+		return statedb.GetState(ExtensionAddress, extensionSlot(id, field, extra...))
+	}
+	status := ExtensionStatus(get(extensionStatusField).Big().Uint64())
+	if status == ExtensionUnknown {
+		return nil
+	}
+	ext := &Extension{
+		ID:               id,
+		Contract:         common.BytesToAddress(get(extensionContractField).Bytes()),
+		CodeHash:         get(extensionCodeHashField),
+		Proposer:         common.BytesToAddress(get(extensionProposerField).Bytes()),
+		Recipient:        get(extensionRecipientField).Bytes(),
+		RecipientAccount: common.BytesToAddress(get(extensionRecipientAccountField).Bytes()),
+		Pending:          get(extensionPendingField).Big().Uint64(),
+		Status:           status,
+		ProposedAt:       get(extensionProposedAtField).Big().Uint64(),
+		AcceptedAt:       get(extensionAcceptedAtField).Big().Uint64(),
+		StateHash:        get(extensionStateHashField),
+	}
+	count := get(extensionApproverCountField).Big().Uint64()
+	for i := uint64(0); i < count; i++ {
+		ext.Approvers = append(ext.Approvers, common.BytesToAddress(get(extensionApproverField, new(big.Int).SetUint64(i).Bytes()).Bytes()))
+	}
+	return ext
+}
+
+// ExtensionFrozen returns whether the given contract is frozen in the private
+// state, from the proposal of an extension until it is rejected or shared, so
+// that the state shared is the one the approvers committed to.
+func ExtensionFrozen(statedb ExtensionStateReader, contract common.Address) bool {
+	return extensionFrozenFor(statedb, contract) != (common.Hash{})
+}
+
+// extensionFrozen is the vm.FrozenFunc of the EVM, reporting frozen contracts.
+func extensionFrozen(statedb vm.StateDB, contract common.Address) bool {
+	return ExtensionFrozen(statedb, contract)
+}
+
+// extensionFrozenFor returns the extension the given contract is frozen for.
+func extensionFrozenFor(statedb ExtensionStateReader, contract common.Address) common.Hash {
+	return statedb.GetState(ExtensionAddress, extensionSlot(contract.Hash(), extensionFrozenField))
+}
+
+// unfreezeExtension unfreezes the contract of the given extension, if frozen
+// for it.
+func unfreezeExtension(statedb vm.StateDB, ext *Extension) {
+	if extensionFrozenFor(statedb, ext.Contract) == ext.ID {
+		statedb.SetState(ExtensionAddress, extensionSlot(ext.Contract.Hash(), extensionFrozenField), common.Hash{})
+	}
+}
+
+// ExtensionParticipants retrieves the accounts recorded as the participants of
+// the given contract in the private state: its creator and the recipients of
+// its extensions.
+func ExtensionParticipants(statedb ExtensionStateReader, contract common.Address) []common.Address {
+	id := contract.Hash()
+	count := statedb.GetState(ExtensionAddress, extensionSlot(id, extensionParticipantCountField)).Big().Uint64()
+
+	participants := make([]common.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		slot := extensionSlot(id, extensionParticipantField, new(big.Int).SetUint64(i).Bytes())
+		participants = append(participants, common.BytesToAddress(statedb.GetState(ExtensionAddress, slot).Bytes()))
+	}
+	return participants
+}
+
+// writeExtensionParticipants records the participants of the given contract in
+// the private state.
+func writeExtensionParticipants(statedb vm.StateDB, contract common.Address, participants []common.Address) {
+	// Keep the record account from being deleted as empty
+	if statedb.GetNonce(ExtensionAddress) == 0 {
+		statedb.SetNonce(ExtensionAddress, 1)
+	}
+	id := contract.Hash()
+	for i, participant := range participants {
+		statedb.SetState(ExtensionAddress, extensionSlot(id, extensionParticipantField, big.NewInt(int64(i)).Bytes()), participant.Hash())
+	}
+	statedb.SetState(ExtensionAddress, extensionSlot(id, extensionParticipantCountField), common.BigToHash(big.NewInt(int64(len(participants)))))
+}
+
+// sameAddresses reports whether the given lists hold the same addresses, each
+// of them once, in any order.
+func sameAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[common.Address]bool, len(a))
+	for _, addr := range a {
+		set[addr] = true
+	}
+	for _, addr := range b {
+		if !set[addr] {
+			return false
+		}
+		delete(set, addr)
+	}
+	return true
+}
+
+// applyExtension applies a contract extension message sent by the given sender,
+// with the given nonce, to the private state. Like any private transaction, it
+// is charged its intrinsic gas only, so the shared state is bounded by size.
+func applyExtension(statedb vm.StateDB, sender common.Address, nonce uint64, number *big.Int, data []byte) error {
+	var msg ExtensionMessage
+	if err := rlp.DecodeBytes(data, &msg); err != nil {
+		return ErrInvalidExtension
+	}
+	switch msg.Code {
+	case ExtensionProposeMsg:
+		var proposal ExtensionProposal
+		if err := rlp.DecodeBytes(msg.Payload, &proposal); err != nil {
+			return ErrInvalidExtension
+		}
+		return proposeExtension(statedb, ExtensionID(sender, nonce), sender, &proposal, number)
+
+	case ExtensionVoteMsg:
+		var vote ExtensionVote
+		if err := rlp.DecodeBytes(msg.Payload, &vote); err != nil {
+			return ErrInvalidExtension
+		}
+		return voteExtension(statedb, sender, &vote, number)
+
+	case ExtensionShareMsg:
+		var share ExtensionShare
+		if err := rlp.DecodeBytes(msg.Payload, &share); err != nil || share.State == nil {
+			return ErrInvalidExtension
+		}
+		if len(share.State.Storage) > MaxExtensionSlots || len(share.State.Code) > params.MaxCodeSize {
+			return ErrExtensionTooLarge
+		}
+		return shareExtension(statedb, sender, &share, number)
+	}
+	return ErrInvalidExtension
+}
+
+func proposeExtension(statedb vm.StateDB, id common.Hash, proposer common.Address, proposal *ExtensionProposal, number *big.Int) error {
+	if len(proposal.Recipient) != common.HashLength || proposal.RecipientAccount == (common.Address{}) {
+		return ErrInvalidExtension
+	}
+	// The nodes of the existing participants, the proposer's among them, hold
+	// the contract and check the approvers are its participants along with the
+	// recipient. The recipient's node, lacking the contract, can't tell.
+	approvers := proposal.Approvers
+	if statedb.GetCodeSize(proposal.Contract) != 0 {
+		if statedb.GetCodeHash(proposal.Contract) != proposal.CodeHash {
+			return ErrInvalidExtension
+		}
+		participants := ExtensionParticipants(statedb, proposal.Contract)
+		known := false
+		for _, participant := range participants {
+			if participant == proposal.RecipientAccount {
+				return ErrInvalidExtension
+			}
+			known = known || participant == proposer
+		}
+		if !known {
+			return ErrExtensionUnauthorized
+		}
+		// One extension at a time, as the contract is frozen until it completes
+		if ExtensionFrozen(statedb, proposal.Contract) {
+			return ErrExtensionStatus
+		}
+		approvers = append(participants, proposal.RecipientAccount)
+	}
+	if !sameAddresses(proposal.Approvers, approvers) {
+		return ErrInvalidExtension
+	}
+	recipient := false
+	for _, approver := range proposal.Approvers {
+		recipient = recipient || approver == proposal.RecipientAccount
+	}
+	if !recipient {
+		return ErrInvalidExtension
+	}
+	set := func(value common.Hash, field byte, extra ...[]byte) {
+		statedb.SetState(ExtensionAddress, extensionSlot(id, field, extra...), value)
+	}
+	// Keep the record account from being deleted as empty
+	if statedb.GetNonce(ExtensionAddress) == 0 {
+		statedb.SetNonce(ExtensionAddress, 1)
+	}
+	for i, approver := range proposal.Approvers {
+		statedb.SetState(ExtensionAddress, extensionSlot(id, extensionVoteField, approver[:]), common.BigToHash(big.NewInt(extensionVotePending)))
+		set(approver.Hash(), extensionApproverField, big.NewInt(int64(i)).Bytes())
+	}
+	count := common.BigToHash(big.NewInt(int64(len(proposal.Approvers))))
+
+	set(common.BigToHash(big.NewInt(int64(ExtensionPending))), extensionStatusField)
+	set(proposal.Contract.Hash(), extensionContractField)
+	set(proposal.CodeHash, extensionCodeHashField)
+	set(proposer.Hash(), extensionProposerField)
+	set(common.BytesToHash(proposal.Recipient), extensionRecipientField)
+	set(proposal.RecipientAccount.Hash(), extensionRecipientAccountField)
+	set(count, extensionApproverCountField)
+	set(count, extensionPendingField)
+	set(common.BigToHash(number), extensionProposedAtField)
+
+	// Freeze the contract, so that its state stays the one as of the proposal,
+	// which the approvers commit to and the recipient imports
+	if statedb.GetCodeSize(proposal.Contract) != 0 {
+		statedb.SetState(ExtensionAddress, extensionSlot(proposal.Contract.Hash(), extensionFrozenField), id)
+	}
+	addExtensionLog(statedb, number, ExtensionProposedTopic, id)
+	return nil
+}
+
+func voteExtension(statedb vm.StateDB, voter common.Address, vote *ExtensionVote, number *big.Int) error {
+	ext := ReadExtension(statedb, vote.ID)
+	if ext == nil {
+		return ErrUnknownExtension
+	}
+	if ext.Status != ExtensionPending {
+		return ErrExtensionStatus
+	}
+	slot := extensionSlot(vote.ID, extensionVoteField, voter[:])
+	if statedb.GetState(ExtensionAddress, slot).Big().Uint64() != extensionVotePending {
+		return ErrExtensionUnauthorized
+	}
+	if !vote.Accept {
+		statedb.SetState(ExtensionAddress, extensionSlot(vote.ID, extensionStatusField), common.BigToHash(big.NewInt(int64(ExtensionRejected))))
+		unfreezeExtension(statedb, ext)
+		addExtensionLog(statedb, number, ExtensionRejectedTopic, vote.ID)
+		return nil
+	}
+	// The existing participants commit to the state to share, which the
+	// recipient doesn't hold
+	if voter != ext.RecipientAccount {
+		if vote.StateHash == (common.Hash{}) {
+			return ErrInvalidExtension
+		}
+		switch ext.StateHash {
+		case common.Hash{}:
+			statedb.SetState(ExtensionAddress, extensionSlot(vote.ID, extensionStateHashField), vote.StateHash)
+		case vote.StateHash:
+		default:
+			return ErrExtensionStateHash
+		}
+	}
+	statedb.SetState(ExtensionAddress, slot, common.BigToHash(big.NewInt(extensionVoteAccepted)))
+	statedb.SetState(ExtensionAddress, extensionSlot(vote.ID, extensionPendingField), common.BigToHash(new(big.Int).SetUint64(ext.Pending-1)))
+	if ext.Pending == 1 {
+		statedb.SetState(ExtensionAddress, extensionSlot(vote.ID, extensionStatusField), common.BigToHash(big.NewInt(int64(ExtensionAccepted))))
+		statedb.SetState(ExtensionAddress, extensionSlot(vote.ID, extensionAcceptedAtField), common.BigToHash(number))
+		addExtensionLog(statedb, number, ExtensionAcceptedTopic, vote.ID)
+	}
+	return nil
+}
+
+func shareExtension(statedb vm.StateDB, sender common.Address, share *ExtensionShare, number *big.Int) error {
+	ext := ReadExtension(statedb, share.ID)
+	if ext == nil {
+		return ErrUnknownExtension
+	}
+	if ext.Status != ExtensionAccepted {
+		return ErrExtensionStatus
+	}
+	// Only the existing parties, which hold the contract state, may share it
+	if sender != ext.Proposer && statedb.GetState(ExtensionAddress, extensionSlot(share.ID, extensionVoteField, sender[:])) == (common.Hash{}) {
+		return ErrExtensionUnauthorized
+	}
+	// The shared state must be the one the approvers committed to
+	hash := share.State.Hash()
+	if hash != ext.StateHash || crypto.Keccak256Hash(share.State.Code) != ext.CodeHash {
+		return ErrExtensionStateHash
+	}
+	statedb.SetState(ExtensionAddress, extensionSlot(share.ID, extensionStatusField), common.BigToHash(big.NewInt(int64(ExtensionShared))))
+	writeExtensionParticipants(statedb, ext.Contract, ext.Approvers)
+	unfreezeExtension(statedb, ext)
+
+	// Import the contract into the private states which lack it. The existing
+	// parties hold the same state, as the contract was frozen since the proposal
+	if statedb.GetCodeSize(ext.Contract) == 0 {
+		statedb.SetCode(ext.Contract, share.State.Code)
+		statedb.SetNonce(ext.Contract, share.State.Nonce)
+		for _, slot := range share.State.Storage {
+			statedb.SetState(ext.Contract, slot.Key, slot.Value)
+		}
+	}
+	addExtensionLog(statedb, number, ExtensionSharedTopic, share.ID, hash)
+	return nil
+}
+
+func addExtensionLog(statedb vm.StateDB, number *big.Int, topics ...common.Hash) {
+	statedb.AddLog(&types.Log{
+		Address:     ExtensionAddress,
+		Topics:      topics,
+		BlockNumber: number.Uint64(),
+	})
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func extensionMessage(t *testing.T, code uint64, payload interface{}) []byte {
+	blob, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := rlp.EncodeToBytes(&ExtensionMessage{Code: code, Payload: blob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestContractExtension(t *testing.T) {
+	var (
+		proposer = common.HexToAddress("0x01")
+		approver = common.HexToAddress("0x02")
+		outsider = common.HexToAddress("0x03")
+		newParty = common.HexToAddress("0x04")
+		contract = common.HexToAddress("0x0c")
+		code     = []byte{0x60, 0x00}
+		number   = big.NewInt(10)
+	)
+	// The existing party holds the contract, the new party doesn't
+	party, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	party.SetCode(contract, code)
+	party.SetNonce(contract, 1)
+	party.SetState(contract, common.HexToHash("0x01"), common.HexToHash("0xaa"))
+	party.SetState(contract, common.HexToHash("0x02"), common.HexToHash("0xbb"))
+	writeExtensionParticipants(party, contract, []common.Address{proposer, approver})
+	root, _ := party.Commit(false)
+	party, _ = state.New(root, party.Database())
+
+	recipient, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	states := []*state.StateDB{party, recipient}
+
+	apply := func(sender common.Address, nonce uint64, data []byte) []error {
+		errs := make([]error, len(states))
+		for i, statedb := range states {
+			errs[i] = applyExtension(statedb, sender, nonce, number, data)
+		}
+		return errs
+	}
+	proposal := func(approvers ...common.Address) []byte {
+		return extensionMessage(t, ExtensionProposeMsg, &ExtensionProposal{
+			Contract:         contract,
+			CodeHash:         crypto.Keccak256Hash(code),
+			Recipient:        common.HexToHash("0xfeed").Bytes(),
+			RecipientAccount: newParty,
+			Approvers:        approvers,
+		})
+	}
+	// The approvers must be the participants of the contract and the new party
+	if err := apply(proposer, 4, proposal(proposer, newParty))[0]; err != ErrInvalidExtension {
+		t.Errorf("proposal missing a participant: have %v, want %v", err, ErrInvalidExtension)
+	}
+	if err := apply(proposer, 4, proposal(proposer, approver, approver, newParty))[0]; err != ErrInvalidExtension {
+		t.Errorf("proposal with a repeated approver: have %v, want %v", err, ErrInvalidExtension)
+	}
+	if err := apply(outsider, 0, proposal(proposer, approver, newParty))[0]; err != ErrExtensionUnauthorized {
+		t.Errorf("proposal of an outsider: have %v, want %v", err, ErrExtensionUnauthorized)
+	}
+	id := ExtensionID(proposer, 5)
+	for _, err := range apply(proposer, 5, proposal(newParty, proposer, approver)) {
+		if err != nil {
+			t.Fatalf("failed to propose: %v", err)
+		}
+	}
+	ext := ReadExtension(recipient, id)
+	if ext == nil || ext.Status != ExtensionPending || ext.Contract != contract || ext.Proposer != proposer || ext.RecipientAccount != newParty || len(ext.Approvers) != 3 || ext.Pending != 3 || ext.ProposedAt != number.Uint64() {
+		t.Fatalf("extension mismatch: have %+v", ext)
+	}
+	// The contract is frozen by its parties until the extension completes
+	if !ExtensionFrozen(party, contract) || ExtensionFrozen(recipient, contract) {
+		t.Errorf("frozen mismatch: have %v/%v, want true/false", ExtensionFrozen(party, contract), ExtensionFrozen(recipient, contract))
+	}
+	if err := applyExtension(party, proposer, 11, number, proposal(proposer, approver, outsider)); err != ErrExtensionStatus {
+		t.Errorf("proposal of a frozen contract: have %v, want %v", err, ErrExtensionStatus)
+	}
+
+	// Only accepted extensions are shared, by their parties
+	shared, err := NewExtensionState(party, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.Storage) != 2 || shared.Storage[0].Value != common.HexToHash("0xaa") {
+		t.Fatalf("shared state mismatch: have %+v", shared)
+	}
+	share := extensionMessage(t, ExtensionShareMsg, &ExtensionShare{ID: id, State: shared})
+	if err := apply(proposer, 6, share)[1]; err != ErrExtensionStatus {
+		t.Errorf("share before acceptance: have %v, want %v", err, ErrExtensionStatus)
+	}
+	accept := extensionMessage(t, ExtensionVoteMsg, &ExtensionVote{ID: id, Accept: true, StateHash: shared.Hash()})
+	if err := apply(outsider, 0, accept)[1]; err != ErrExtensionUnauthorized {
+		t.Errorf("vote of an outsider: have %v, want %v", err, ErrExtensionUnauthorized)
+	}
+	apply(proposer, 7, accept)
+	if err := apply(proposer, 8, accept)[1]; err != ErrExtensionUnauthorized {
+		t.Errorf("repeated vote: have %v, want %v", err, ErrExtensionUnauthorized)
+	}
+	mismatch := extensionMessage(t, ExtensionVoteMsg, &ExtensionVote{ID: id, Accept: true, StateHash: common.HexToHash("0xbad")})
+	if err := apply(approver, 0, mismatch)[1]; err != ErrExtensionStateHash {
+		t.Errorf("vote on another state: have %v, want %v", err, ErrExtensionStateHash)
+	}
+	apply(approver, 0, accept)
+	apply(newParty, 0, extensionMessage(t, ExtensionVoteMsg, &ExtensionVote{ID: id, Accept: true}))
+	if ext := ReadExtension(recipient, id); ext.Status != ExtensionAccepted || ext.AcceptedAt != number.Uint64() || ext.Pending != 0 || ext.StateHash != shared.Hash() {
+		t.Fatalf("extension not accepted: have %+v", ext)
+	}
+	if err := apply(outsider, 1, share)[1]; err != ErrExtensionUnauthorized {
+		t.Errorf("share of an outsider: have %v, want %v", err, ErrExtensionUnauthorized)
+	}
+
+	// Only the state the approvers committed to is imported
+	tampered := *shared
+	tampered.Storage = []ExtensionSlot{{Key: common.HexToHash("0x01"), Value: common.HexToHash("0xff")}}
+	if err := apply(proposer, 9, extensionMessage(t, ExtensionShareMsg, &ExtensionShare{ID: id, State: &tampered}))[1]; err != ErrExtensionStateHash {
+		t.Errorf("share of another state: have %v, want %v", err, ErrExtensionStateHash)
+	}
+
+	// The new party imports the shared state, which the frozen contract of the
+	// existing party still holds
+	for _, err := range apply(approver, 1, share) {
+		if err != nil {
+			t.Fatalf("failed to share: %v", err)
+		}
+	}
+	if ExtensionFrozen(party, contract) {
+		t.Errorf("contract still frozen after the share")
+	}
+	imported, err := NewExtensionState(recipient, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Hash() != shared.Hash() {
+		t.Errorf("imported state hash mismatch: have %x, want %x", imported.Hash(), shared.Hash())
+	}
+	if ext := ReadExtension(recipient, id); ext.Status != ExtensionShared {
+		t.Errorf("extension not shared: have %+v", ext)
+	}
+	for i, statedb := range states {
+		if participants := ExtensionParticipants(statedb, contract); !sameAddresses(participants, []common.Address{proposer, approver, newParty}) {
+			t.Errorf("state %d: participants mismatch: have %x", i, participants)
+		}
+	}
+	// The new party, lacking the contract, also recorded the proposals the
+	// existing party rejected
+	if logs := recipient.Logs(); len(logs) != 5 || logs[4].Topics[0] != ExtensionSharedTopic || logs[4].Topics[1] != id {
+		t.Errorf("extension logs mismatch: have %v", logs)
+	}
+}
+
+func TestContractExtensionTooLarge(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	shared := &ExtensionState{
+		Code:    []byte{0x60, 0x00},
+		Storage: make([]ExtensionSlot, MaxExtensionSlots+1),
+	}
+	share := extensionMessage(t, ExtensionShareMsg, &ExtensionShare{State: shared})
+	if err := applyExtension(statedb, common.Address{}, 0, big.NewInt(1), share); err != ErrExtensionTooLarge {
+		t.Errorf("share of too many slots: have %v, want %v", err, ErrExtensionTooLarge)
+	}
+}
+
+func TestContractExtensionFrozen(t *testing.T) {
+	var (
+		proposer = common.HexToAddress("0x01")
+		contract = common.HexToAddress("0x0c")
+		code     = []byte{0x60, 0x01, 0x60, 0x00, 0x55} // sstore(0, 1)
+		slot     = common.HexToHash("0x00")
+	)
+	public, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	private, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	private.SetCode(contract, code)
+	writeExtensionParticipants(private, contract, []common.Address{proposer})
+
+	call := func() error {
+		ctx := vm.Context{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			Frozen:      extensionFrozen,
+			BlockNumber: big.NewInt(1),
+		}
+		evm := vm.NewEVM(ctx, public, private, params.TestChainConfig, vm.Config{})
+		_, _, err := evm.Call(vm.AccountRef(proposer), contract, nil, 100000, new(big.Int))
+		return err
+	}
+	propose := extensionMessage(t, ExtensionProposeMsg, &ExtensionProposal{
+		Contract:         contract,
+		CodeHash:         crypto.Keccak256Hash(code),
+		Recipient:        common.HexToHash("0xfeed").Bytes(),
+		RecipientAccount: common.HexToAddress("0x04"),
+		Approvers:        []common.Address{proposer, common.HexToAddress("0x04")},
+	})
+	if err := applyExtension(private, proposer, 0, big.NewInt(1), propose); err != nil {
+		t.Fatal(err)
+	}
+	// A frozen contract can't be modified
+	if err := call(); err == nil || private.GetState(contract, slot) != (common.Hash{}) {
+		t.Errorf("frozen contract modified: err %v, value %x", err, private.GetState(contract, slot))
+	}
+	// Once the extension is rejected, it can again
+	reject := extensionMessage(t, ExtensionVoteMsg, &ExtensionVote{ID: ExtensionID(proposer, 0), Accept: false})
+	if err := applyExtension(private, proposer, 1, big.NewInt(2), reject); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(); err != nil || private.GetState(contract, slot) != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("unfrozen contract not modified: err %v, value %x", err, private.GetState(contract, slot))
+	}
+}
+
+func TestContractExtensionRejected(t *testing.T) {
+	var (
+		proposer = common.HexToAddress("0x01")
+		approver = common.HexToAddress("0x02")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	id := ExtensionID(proposer, 0)
+	propose := extensionMessage(t, ExtensionProposeMsg, &ExtensionProposal{
+		Contract:         common.HexToAddress("0x0c"),
+		Recipient:        common.HexToHash("0xfeed").Bytes(),
+		RecipientAccount: approver,
+		Approvers:        []common.Address{approver},
+	})
+	if err := applyExtension(statedb, proposer, 0, big.NewInt(1), propose); err != nil {
+		t.Fatal(err)
+	}
+	reject := extensionMessage(t, ExtensionVoteMsg, &ExtensionVote{ID: id, Accept: false})
+	if err := applyExtension(statedb, approver, 0, big.NewInt(2), reject); err != nil {
+		t.Fatal(err)
+	}
+	if ext := ReadExtension(statedb, id); ext.Status != ExtensionRejected {
+		t.Errorf("extension not rejected: have %v", ext.Status)
+	}
+	if err := applyExtension(statedb, proposer, 1, big.NewInt(3), []byte{0x01}); err != ErrInvalidExtension {
+		t.Errorf("malformed message: have %v, want %v", err, ErrInvalidExtension)
+	}
+}
@@ -18,7 +18,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/private/constellation"
 )
@@ -354,3 +359,73 @@ func TestPrivateTransaction(t *testing.T) {
 		t.Error("didn't expect public contract address to exist on private state")
 	}
 }
+
+// Tests that the private state tries are garbage collected along with the public
+// ones: the tries of the blocks below the retention window are dereferenced,
+// while the recent ones are kept in memory and flushed to disk on shutdown.
+func TestPrivateStateGarbageCollection(t *testing.T) {
+	engine := ethash.NewFaker()
+
+	db := ethdb.NewMemDatabase()
+	genesis := new(Genesis).MustCommit(db)
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 2*triesInMemory, func(i int, b *BlockGen) { b.SetCoinbase(common.Address{1}) })
+
+	diskdb := ethdb.NewMemDatabase()
+	new(Genesis).MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, params.TestChainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	// Import the blocks, each of them changing the private state of a contract
+	contract := common.Address{2}
+	privateRoots := make([]common.Hash, len(blocks))
+	for i, block := range blocks {
+		parent := chain.CurrentBlock()
+		publicState, err := state.New(parent.Root(), chain.stateCache)
+		if err != nil {
+			t.Fatalf("block %d: failed to open public state: %v", block.Number(), err)
+		}
+		privateState, err := state.New(GetPrivateStateRoot(diskdb, parent.Root()), chain.privateStateCache)
+		if err != nil {
+			t.Fatalf("block %d: failed to open private state: %v", block.Number(), err)
+		}
+		receipts, _, _, _, err := chain.Processor().Process(block, publicState, privateState, vm.Config{})
+		if err != nil {
+			t.Fatalf("block %d: failed to process: %v", block.Number(), err)
+		}
+		privateState.SetNonce(contract, 1)
+		privateState.SetState(contract, common.Hash{}, common.BigToHash(block.Number()))
+		privateRoots[i] = privateState.IntermediateRoot(true)
+		if err := WritePrivateStateRoot(diskdb, block.Root(), privateRoots[i]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.WriteBlockWithState(block, receipts, publicState, privateState); err != nil {
+			t.Fatalf("block %d: failed to write: %v", block.Number(), err)
+		}
+	}
+	// Only the private tries within the retention window are left in memory
+	for i, root := range privateRoots {
+		_, err := state.New(root, chain.privateStateCache)
+		if number := uint64(i + 1); number <= uint64(len(blocks))-triesInMemory {
+			if err == nil {
+				t.Errorf("block %d: private state not garbage collected", number)
+			}
+		} else if err != nil {
+			t.Errorf("block %d: private state garbage collected within the retention window: %v", number, err)
+		}
+	}
+	chain.Stop()
+
+	// The private tries of HEAD, HEAD-1 and HEAD-127 are flushed to disk on shutdown
+	flushed := map[int]bool{len(blocks) - 1: true, len(blocks) - 2: true, len(blocks) - triesInMemory: true}
+	for i := len(blocks) - triesInMemory; i < len(blocks); i++ {
+		_, err := state.New(privateRoots[i], state.NewDatabase(diskdb))
+		if flushed[i] && err != nil {
+			t.Errorf("block %d: private state not flushed on shutdown: %v", i+1, err)
+		}
+		if !flushed[i] && err == nil {
+			t.Errorf("block %d: private state unexpectedly flushed", i+1)
+		}
+	}
+}
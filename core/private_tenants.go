@@ -0,0 +1,195 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrUnknownTenant is returned when selecting the private state of a tenant
+// which isn't configured on the node.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// ErrUnauthorizedTenant is returned when selecting the private state of a
+// tenant without its API token.
+var ErrUnauthorizedTenant = errors.New("unauthorized tenant")
+
+// ErrMissingTenantState is returned when the private state of a tenant wasn't
+// recorded for a block, the tenant having been added after it was processed.
+var ErrMissingTenantState = errors.New("missing tenant private state")
+
+// SetTenants configures the enclave keys of the tenants hosted by the node,
+// each of them having a private state of its own next to the default one.
+// It must be called before any block is processed.
+//
+// A tenant added after blocks were processed has no private state for them, its
+// private state has to be rebuilt by syncing the chain again from genesis. The
+// tenant states being garbage collected as the default one, the head block is
+// rewound to the last one having all of them available.
+func (bc *BlockChain) SetTenants(tenants []string) error {
+	currentBlock := bc.CurrentBlock()
+	bc.tenants = tenants
+	for _, tenant := range tenants {
+		if _, err := bc.tenantStateRoot(tenant, currentBlock.Root()); err != nil {
+			bc.tenants = nil
+			return fmt.Errorf("tenant %s added after block %d: %v", tenant, currentBlock.NumberU64(), err)
+		}
+	}
+	if bc.hasPrivateStates(currentBlock.Root()) {
+		return nil
+	}
+	log.Warn("Head tenant private state missing, repairing chain", "number", currentBlock.Number(), "hash", currentBlock.Hash())
+	if err := bc.repair(&currentBlock); err != nil {
+		return err
+	}
+	bc.currentBlock.Store(currentBlock)
+	return nil
+}
+
+// Tenants returns the enclave keys of the tenants hosted by the node.
+func (bc *BlockChain) Tenants() []string {
+	return bc.tenants
+}
+
+// HasTenant reports whether the given tenant is hosted by the node, the empty
+// tenant selecting the default private state.
+func (bc *BlockChain) HasTenant(tenant string) bool {
+	if tenant == "" {
+		return true
+	}
+	for _, t := range bc.tenants {
+		if t == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+// privateStateRoots returns the roots of the default and the tenant private
+// states at the given block, which are garbage collected and flushed together.
+func (bc *BlockChain) privateStateRoots(blockRoot common.Hash) []common.Hash {
+	roots := make([]common.Hash, 0, len(bc.tenants)+1)
+	for _, tenant := range append([]string{""}, bc.tenants...) {
+		if root := GetTenantPrivateStateRoot(bc.db, tenant, blockRoot); root != (common.Hash{}) {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// tenantStateRoot returns the root of the private state of the given tenant at
+// the given block. No private state root is recorded for the genesis block, its
+// private states being empty.
+func (bc *BlockChain) tenantStateRoot(tenant string, blockRoot common.Hash) (common.Hash, error) {
+	root := GetTenantPrivateStateRoot(bc.db, tenant, blockRoot)
+	if root == (common.Hash{}) && tenant != "" && blockRoot != bc.genesisBlock.Root() {
+		return common.Hash{}, ErrMissingTenantState
+	}
+	return root, nil
+}
+
+// hasPrivateStates reports whether the default and the tenant private states of
+// the given block are available.
+func (bc *BlockChain) hasPrivateStates(blockRoot common.Hash) bool {
+	for _, tenant := range append([]string{""}, bc.tenants...) {
+		root, err := bc.tenantStateRoot(tenant, blockRoot)
+		if err != nil {
+			return false
+		}
+		if _, err := bc.privateStateCache.OpenTrie(root); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// TenantStateAt returns a new mutable public state and the private state of the
+// given tenant based on a particular point in time.
+func (bc *BlockChain) TenantStateAt(root common.Hash, tenant string) (*state.StateDB, *state.StateDB, error) {
+	if tenant == "" {
+		return bc.StateAt(root)
+	}
+	if !bc.HasTenant(tenant) {
+		return nil, nil, ErrUnknownTenant
+	}
+	privateRoot, err := bc.tenantStateRoot(tenant, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicStateDb, err := state.New(root, bc.stateCache)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateStateDb, err := state.New(privateRoot, bc.privateStateCache)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publicStateDb, privateStateDb, nil
+}
+
+// GetTenantReceiptsByHash retrieves the receipts for all transactions in a given
+// block, the receipts of the private transactions being the ones of the given
+// tenant.
+func (bc *BlockChain) GetTenantReceiptsByHash(hash common.Hash, tenant string) types.Receipts {
+	receipts := bc.GetReceiptsByHash(hash)
+	if tenant == "" || receipts == nil {
+		return receipts
+	}
+	return mergeReceipts(receipts, GetTenantPrivateReceipts(bc.db, tenant, hash))
+}
+
+// ProcessTenants processes the transactions of the given block against the
+// private state of each tenant, as of the parent block, storing the resulting
+// private state, receipts and bloom of the tenants. The public state changes are
+// discarded, the block having been validated against the default private state.
+//
+// It must be called before writing the block, which garbage collects the tenant
+// states along with the default one.
+func (bc *BlockChain) ProcessTenants(block *types.Block) error {
+	if len(bc.tenants) == 0 {
+		return nil
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	for _, tenant := range bc.tenants {
+		publicState, err := state.New(parent.Root, bc.stateCache)
+		if err != nil {
+			return err
+		}
+		privateRoot, err := bc.tenantStateRoot(tenant, parent.Root)
+		if err != nil {
+			return err
+		}
+		privateState, err := state.New(privateRoot, bc.privateStateCache)
+		if err != nil {
+			return err
+		}
+		cfg := bc.vmConfig
+		cfg.Tenant = tenant
+		_, privateReceipts, _, _, err := bc.processor.Process(block, publicState, privateState, cfg)
+		if err != nil {
+			return err
+		}
+		root, err := privateState.Commit(bc.chainConfig.IsEIP158(block.Number()))
+		if err != nil {
+			return err
+		}
+		if err := WriteTenantPrivateStateRoot(bc.db, tenant, block.Root(), root); err != nil {
+			return err
+		}
+		if err := WriteTenantPrivateReceipts(bc.db, tenant, block.Hash(), privateReceipts); err != nil {
+			return err
+		}
+		if err := WriteTenantPrivateBlockBloom(bc.db, tenant, block.NumberU64(), privateReceipts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
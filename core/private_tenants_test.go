@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
+)
+
+// tenantPrivateTransactionManager serves the payloads only to their recipient
+// tenants, the default private state not being a party to any of them.
+type tenantPrivateTransactionManager struct {
+	payloads map[string][]byte
+}
+
+func (tpm *tenantPrivateTransactionManager) Send(data []byte, from string, to []string) ([]byte, error) {
+	return nil, fmt.Errorf("to be implemented")
+}
+
+func (tpm *tenantPrivateTransactionManager) SendSignedTx(data []byte, to []string) ([]byte, error) {
+	return nil, fmt.Errorf("to be implemented")
+}
+
+func (tpm *tenantPrivateTransactionManager) Receive(data []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (tpm *tenantPrivateTransactionManager) ReceiveFor(data []byte, to string) ([]byte, error) {
+	return tpm.payloads[to], nil
+}
+
+func TestProcessTenants(t *testing.T) {
+	saved := private.P
+	defer func() {
+		private.P = saved
+	}()
+	// Tenant A is the only recipient of a contract storing 10 at slot 0
+	private.P = &tenantPrivateTransactionManager{
+		payloads: map[string][]byte{"A": common.Hex2Bytes("600a60005560016000f3")},
+	}
+	var (
+		db      = ethdb.NewMemDatabase()
+		gspec   = &Genesis{Config: params.QuorumTestChainConfig}
+		genesis = gspec.MustCommit(db)
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if err := chain.SetTenants([]string{"A", "B"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, _ := types.SignTx(types.NewContractCreation(0, new(big.Int), 1000000, new(big.Int), crypto.Keccak512([]byte("payload"))), types.HomesteadSigner{}, key)
+	tx.SetPrivate()
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   genesis.GasLimit(),
+		Difficulty: genesis.Difficulty(),
+		Root:       genesis.Root(),
+	}
+	block := types.NewBlock(header, types.Transactions{tx}, nil, nil)
+	if err := chain.ProcessTenants(block); err != nil {
+		t.Fatalf("failed to process the tenants: %v", err)
+	}
+
+	contract := crypto.CreateAddress(sender, 0)
+	for tenant, want := range map[string]common.Hash{"": {}, "A": common.BigToHash(big.NewInt(10)), "B": {}} {
+		_, privateState, err := chain.TenantStateAt(block.Root(), tenant)
+		if err != nil {
+			t.Fatalf("tenant %q: %v", tenant, err)
+		}
+		if have := privateState.GetState(contract, common.Hash{}); have != want {
+			t.Errorf("tenant %q: storage mismatch: have %x, want %x", tenant, have, want)
+		}
+	}
+	// The tenant states are left to the garbage collection of the block write
+	if _, err := state.New(GetTenantPrivateStateRoot(db, "A", block.Root()), state.NewDatabase(db)); err == nil {
+		t.Error("tenant private state flushed to disk")
+	}
+	if _, _, err := chain.TenantStateAt(block.Root(), "C"); err != ErrUnknownTenant {
+		t.Errorf("unknown tenant: have %v, want %v", err, ErrUnknownTenant)
+	}
+	receipts := GetTenantPrivateReceipts(db, "A", block.Hash())
+	if len(receipts) != 1 || receipts[0].TxHash != tx.Hash() || receipts[0].ContractAddress != contract {
+		t.Errorf("tenant receipts mismatch: have %v", receipts)
+	}
+	if root := GetPrivateStateRoot(db, block.Root()); root != (common.Hash{}) {
+		t.Errorf("default private state root written: %x", root)
+	}
+}
+
+func TestTenantAddedLater(t *testing.T) {
+	var (
+		db      = ethdb.NewMemDatabase()
+		gspec   = &Genesis{Config: params.QuorumTestChainConfig}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 1, nil)
+	chain, err := NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert the chain: %v", err)
+	}
+	// The tenant has no private state for the block processed without it
+	if err := chain.SetTenants([]string{"A"}); err == nil {
+		t.Fatal("tenant added after genesis accepted")
+	}
+	if tenants := chain.Tenants(); len(tenants) != 0 {
+		t.Errorf("tenants set: %v", tenants)
+	}
+	chain.tenants = []string{"A"}
+	if _, _, err := chain.TenantStateAt(blocks[0].Root(), "A"); err != ErrMissingTenantState {
+		t.Errorf("missing tenant state: have %v, want %v", err, ErrMissingTenantState)
+	}
+	if _, _, err := chain.TenantStateAt(genesis.Root(), "A"); err != nil {
+		t.Errorf("genesis tenant state: %v", err)
+	}
+}
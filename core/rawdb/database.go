@@ -0,0 +1,150 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DatabaseStat is the number of entries and storage taken by a category of
+// database content.
+type DatabaseStat struct {
+	Category string
+	Count    int
+	Size     common.StorageSize
+}
+
+// inspectCategory matches the keys of a category of database content.
+type inspectCategory struct {
+	name  string
+	match func(key []byte) bool
+}
+
+// keyOf returns a matcher for the keys of the given prefix and total length.
+func keyOf(prefix []byte, length int) func([]byte) bool {
+	return func(key []byte) bool {
+		return len(key) == length && bytes.HasPrefix(key, prefix)
+	}
+}
+
+// keyWith returns a matcher for the keys of the given prefix and suffix, and
+// total length.
+func keyWith(prefix, suffix []byte, length int) func([]byte) bool {
+	return func(key []byte) bool {
+		return len(key) == length && bytes.HasPrefix(key, prefix) && bytes.HasSuffix(key, suffix)
+	}
+}
+
+// keyPrefixed returns a matcher for the keys starting with any of the prefixes.
+func keyPrefixed(prefixes ...string) func([]byte) bool {
+	return func(key []byte) bool {
+		for _, prefix := range prefixes {
+			if bytes.HasPrefix(key, []byte(prefix)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// keyIn returns a matcher for the given keys.
+func keyIn(keys ...[]byte) func([]byte) bool {
+	return func(key []byte) bool {
+		for _, k := range keys {
+			if bytes.Equal(key, k) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// inspectCategories lists the categories of database content in report order.
+// A key belongs to the first category matching it, the lengths telling apart
+// the keys whose prefixes are ambiguous.
+var inspectCategories = []inspectCategory{
+	{"Headers", keyOf(headerPrefix, 1+8+common.HashLength)},
+	{"Total difficulties", keyWith(headerPrefix, headerTDSuffix, 1+8+common.HashLength+1)},
+	{"Canonical hashes", keyWith(headerPrefix, headerHashSuffix, 1+8+1)},
+	{"Block number lookups", keyOf(headerNumberPrefix, 1+common.HashLength)},
+	{"Bodies", keyOf(blockBodyPrefix, 1+8+common.HashLength)},
+	{"Receipts", keyOf(blockReceiptsPrefix, 1+8+common.HashLength)},
+	{"Transaction lookups", keyOf(txLookupPrefix, 1+common.HashLength)},
+	{"Bloom bits", keyOf(bloomBitsPrefix, 1+2+8+common.HashLength)},
+	{"Trie nodes and code", func(key []byte) bool { return len(key) == common.HashLength }},
+	{"Preimages", keyOf(preimagePrefix, len(preimagePrefix)+common.HashLength)},
+	{"Chain configs", keyOf(configPrefix, len(configPrefix)+common.HashLength)},
+	{"Consensus data", keyPrefixed("clique-", "istanbul-")},
+	{"Chain indexes", keyPrefixed("i")},
+	{"Private state roots", keyOf(PrivateRootPrefix, 1+common.HashLength)},
+	{"Private blooms", keyOf(PrivateBloomPrefix, 2+8)},
+	{"Tenant private state roots", keyOf(TenantRootPrefix, 2+2*common.HashLength)},
+	{"Tenant private blooms", keyOf(TenantBloomPrefix, 3+common.HashLength+8)},
+	{"Tenant private receipts", keyOf(TenantReceiptsPrefix, 3+2*common.HashLength)},
+	{"Metadata", keyIn(databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, QuorumEIP155ActivatedKey)},
+}
+
+// InspectDatabase traverses the entire database and reports the number of
+// entries and storage taken by each category of content, including the Quorum
+// private state. Keys not belonging to any known category are reported as
+// unaccounted, and the blocks moved to a freezer last.
+func InspectDatabase(db ethdb.Iteratee) ([]DatabaseStat, error) {
+	it := db.NewIterator()
+	defer it.Release()
+
+	stats := make([]DatabaseStat, len(inspectCategories)+1)
+	for i, category := range inspectCategories {
+		stats[i].Category = category.name
+	}
+	stats[len(inspectCategories)].Category = "Unaccounted"
+
+	var (
+		count  int
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for it.Next() {
+		key := it.Key()
+		i := 0
+		for ; i < len(inspectCategories); i++ {
+			if inspectCategories[i].match(key) {
+				break
+			}
+		}
+		stats[i].Count++
+		stats[i].Size += common.StorageSize(len(key) + len(it.Value()))
+
+		count++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+	// The blocks moved to a freezer live outside of the key-value store
+	if ancients, ok := db.(AncientStore); ok {
+		stats = append(stats, DatabaseStat{Category: "Ancient blocks", Count: int(ancients.Ancients()), Size: ancients.AncientSize()})
+	}
+	return stats, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Tests that the database inspection sorts the entries into their categories.
+func TestInspectDatabase(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Extra: []byte("test block")})
+	WriteBlock(db, block)
+	WriteTd(db, block.Hash(), 1, big.NewInt(1))
+	WriteCanonicalHash(db, block.Hash(), 1)
+	WriteHeadBlockHash(db, block.Hash())
+	db.Put(common.HexToHash("0x01").Bytes(), []byte("node"))
+	db.Put([]byte("unknown"), []byte("value"))
+
+	stats, err := InspectDatabase(db)
+	if err != nil {
+		t.Fatalf("failed to inspect database: %v", err)
+	}
+	want := map[string]int{
+		"Headers":              1,
+		"Total difficulties":   1,
+		"Canonical hashes":     1,
+		"Block number lookups": 1,
+		"Bodies":               1,
+		"Trie nodes and code":  1,
+		"Metadata":             1,
+		"Unaccounted":          1,
+	}
+	for _, stat := range stats {
+		if stat.Count != want[stat.Category] {
+			t.Errorf("%s: entry count mismatch: have %d, want %d", stat.Category, stat.Count, want[stat.Category])
+		}
+	}
+	if size := stats[len(stats)-1].Size; size != common.StorageSize(len("unknown")+len("value")) {
+		t.Errorf("unaccounted size mismatch: have %v, want %d", size, len("unknown")+len("value"))
+	}
+}
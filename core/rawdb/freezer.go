@@ -0,0 +1,361 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// The tables of the freezer, each holding an item per frozen block.
+const (
+	freezerHashTable         = "hashes"        // canonical block hashes
+	freezerHeaderTable       = "headers"       // RLP encoded headers
+	freezerBodiesTable       = "bodies"        // RLP encoded bodies
+	freezerReceiptTable      = "receipts"      // RLP encoded receipts in storage form
+	freezerPrivateBloomTable = "privateblooms" // Quorum private blooms
+)
+
+var freezerTables = []string{freezerHashTable, freezerHeaderTable, freezerBodiesTable, freezerReceiptTable, freezerPrivateBloomTable}
+
+const (
+	// freezerBatchLimit is the maximum number of blocks frozen in one go, before
+	// the data is synced to disk and deleted from the key-value store.
+	freezerBatchLimit = 30000
+
+	// freezerRecheckInterval is the frequency to check the chain head for new
+	// blocks to freeze once the freezer caught up.
+	freezerRecheckInterval = time.Minute
+)
+
+// freezer is an append-only store of the canonical blocks older than a threshold,
+// their headers, bodies, receipts and private blooms being moved out of the
+// key-value store into flat files.
+type freezer struct {
+	frozen uint64 // Number of blocks frozen, accessed atomically
+
+	tables    map[string]*freezerTable
+	threshold uint64 // Number of blocks below the head kept in the key-value store
+
+	lock sync.Mutex // Mutex serialising the appends and truncations
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newFreezer opens the freezer within the given directory, discarding the blocks
+// an interrupted freeze left in some of the tables only.
+func newFreezer(dir string, threshold uint64) (*freezer, error) {
+	f := &freezer{
+		tables:    make(map[string]*freezerTable),
+		threshold: threshold,
+		quit:      make(chan struct{}),
+	}
+	for _, name := range freezerTables {
+		table, err := newFreezerTable(dir, name)
+		if err != nil {
+			f.closeTables()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+	frozen := f.tables[freezerHashTable].Items()
+	for _, table := range f.tables {
+		if items := table.Items(); items < frozen {
+			frozen = items
+		}
+	}
+	if err := f.truncate(frozen); err != nil {
+		f.closeTables()
+		return nil, err
+	}
+	log.Info("Opened ancient database", "dir", dir, "blocks", frozen)
+	return f, nil
+}
+
+// Ancients returns the number of blocks frozen.
+func (f *freezer) Ancients() uint64 {
+	return atomic.LoadUint64(&f.frozen)
+}
+
+// AncientSize returns the storage taken by the frozen blocks.
+func (f *freezer) AncientSize() common.StorageSize {
+	var size common.StorageSize
+	for _, table := range f.tables {
+		table.lock.RLock()
+		size += common.StorageSize(table.dataSize + int64(table.items*indexEntrySize))
+		table.lock.RUnlock()
+	}
+	return size
+}
+
+// TruncateAncients discards the frozen blocks from the given number on.
+func (f *freezer) TruncateAncients(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.truncate(items)
+}
+
+// truncate discards the frozen blocks from the given number on. The caller
+// must hold the freezer lock, unless the freezer isn't running yet.
+func (f *freezer) truncate(items uint64) error {
+	if items < f.Ancients() {
+		atomic.StoreUint64(&f.frozen, items)
+	}
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, f.tables[freezerHashTable].Items())
+	return nil
+}
+
+// ancientKey returns the freezer table and block of the given key-value store
+// key, and the block hash the key requires if any.
+func ancientKey(key []byte) (table string, number uint64, hash []byte, ok bool) {
+	switch {
+	case len(key) == 1+8+common.HashLength && bytes.HasPrefix(key, headerPrefix):
+		table = freezerHeaderTable
+	case len(key) == 1+8+common.HashLength && bytes.HasPrefix(key, blockBodyPrefix):
+		table = freezerBodiesTable
+	case len(key) == 1+8+common.HashLength && bytes.HasPrefix(key, blockReceiptsPrefix):
+		table = freezerReceiptTable
+	case len(key) == len(PrivateBloomPrefix)+8 && bytes.HasPrefix(key, PrivateBloomPrefix):
+		return freezerPrivateBloomTable, binary.BigEndian.Uint64(key[len(PrivateBloomPrefix):]), nil, true
+	default:
+		return "", 0, nil, false
+	}
+	return table, binary.BigEndian.Uint64(key[1:9]), key[9:], true
+}
+
+// lookup returns the frozen data of the given key-value store key, or nil if it
+// isn't frozen.
+func (f *freezer) lookup(key []byte) []byte {
+	table, number, hash, ok := ancientKey(key)
+	if !ok || number >= f.Ancients() {
+		return nil
+	}
+	// Only the canonical blocks are frozen, the others live on in the key-value store
+	if hash != nil {
+		if frozen, err := f.tables[freezerHashTable].Retrieve(number); err != nil || !bytes.Equal(frozen, hash) {
+			return nil
+		}
+	}
+	blob, err := f.tables[table].Retrieve(number)
+	if err != nil || len(blob) == 0 {
+		return nil
+	}
+	return blob
+}
+
+// freeze moves the canonical blocks older than the threshold from the key-value
+// store to the freezer, until the freezer is closed.
+func (f *freezer) freeze(db ethdb.Database) {
+	defer f.wg.Done()
+
+	for {
+		frozen, err := f.freezeBatch(db)
+		if err != nil {
+			log.Error("Failed to freeze ancient blocks", "err", err)
+		}
+		// Keep going while catching up, wait for the chain to progress otherwise
+		wait := freezerRecheckInterval
+		if frozen == freezerBatchLimit {
+			wait = 0
+		}
+		select {
+		case <-f.quit:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// freezeBatch freezes the next batch of canonical blocks older than the
+// threshold, returning the number of blocks frozen.
+func (f *freezer) freezeBatch(db ethdb.Database) (int, error) {
+	head := ReadHeadBlockHash(db)
+	if head == (common.Hash{}) {
+		return 0, nil
+	}
+	number := ReadHeaderNumber(db, head)
+	if number == nil || *number < f.threshold {
+		return 0, nil
+	}
+	limit := *number - f.threshold
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var (
+		start  = time.Now()
+		first  = f.Ancients()
+		hashes []common.Hash
+	)
+	for n := first; n < limit && len(hashes) < freezerBatchLimit; n++ {
+		// Stop at any missing header, the chain being rewound concurrently
+		hash := ReadCanonicalHash(db, n)
+		if hash == (common.Hash{}) {
+			break
+		}
+		header := ReadHeaderRLP(db, hash, n)
+		if len(header) == 0 {
+			break
+		}
+		receipts, _ := db.Get(blockReceiptsKey(n, hash))
+		bloom, _ := db.Get(privateBloomKey(n))
+
+		blobs := map[string][]byte{
+			freezerHashTable:         hash.Bytes(),
+			freezerHeaderTable:       header,
+			freezerBodiesTable:       ReadBodyRLP(db, hash, n),
+			freezerReceiptTable:      receipts,
+			freezerPrivateBloomTable: bloom,
+		}
+		for name, blob := range blobs {
+			if err := f.tables[name].Append(n, blob); err != nil {
+				f.truncate(first)
+				return 0, err
+			}
+		}
+		hashes = append(hashes, hash)
+	}
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+	// Make the blocks durable before serving them from the freezer and deleting
+	// them from the key-value store
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			f.truncate(first)
+			return 0, err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, first+uint64(len(hashes)))
+
+	batch := db.NewBatch()
+	for i, hash := range hashes {
+		n := first + uint64(i)
+		batch.Delete(headerKey(n, hash))
+		batch.Delete(blockBodyKey(n, hash))
+		batch.Delete(blockReceiptsKey(n, hash))
+		batch.Delete(privateBloomKey(n))
+	}
+	if err := batch.Write(); err != nil {
+		return len(hashes), err
+	}
+	log.Info("Froze ancient blocks", "blocks", len(hashes), "number", first+uint64(len(hashes))-1, "elapsed", common.PrettyDuration(time.Since(start)))
+	return len(hashes), nil
+}
+
+// close stops freezing blocks and closes the tables.
+func (f *freezer) close() {
+	close(f.quit)
+	f.wg.Wait()
+	f.closeTables()
+}
+
+func (f *freezer) closeTables() {
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			log.Error("Failed to close freezer table", "table", table.name, "err", err)
+		}
+	}
+}
+
+// freezerdb is a key-value store transparently serving the blocks moved to a
+// freezer.
+type freezerdb struct {
+	ethdb.Database
+	freezer *freezer
+}
+
+// NewDatabaseWithFreezer wraps the given key-value store with a freezer within
+// the given directory, to which the canonical blocks older than the threshold
+// are moved in the background. The frozen headers, bodies, receipts and private
+// blooms are served through the wrapper as if they were still in the key-value
+// store. A zero threshold freezes no more blocks, an existing freezer being
+// still served, and leaves a database without one as is.
+func NewDatabaseWithFreezer(db ethdb.Database, dir string, threshold uint64) (ethdb.Database, error) {
+	if dir == "" {
+		return db, nil
+	}
+	if threshold == 0 {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return db, nil
+		}
+	}
+	f, err := newFreezer(dir, threshold)
+	if err != nil {
+		return nil, err
+	}
+	if threshold > 0 {
+		f.wg.Add(1)
+		go f.freeze(db)
+	}
+	return &freezerdb{Database: db, freezer: f}, nil
+}
+
+// Get retrieves the given key from the key-value store, or the freezer.
+func (db *freezerdb) Get(key []byte) ([]byte, error) {
+	value, err := db.Database.Get(key)
+	if err != nil {
+		if blob := db.freezer.lookup(key); blob != nil {
+			return blob, nil
+		}
+	}
+	return value, err
+}
+
+// Has retrieves whether the given key is in the key-value store, or the freezer.
+func (db *freezerdb) Has(key []byte) (bool, error) {
+	ok, err := db.Database.Has(key)
+	if err == nil && !ok {
+		ok = db.freezer.lookup(key) != nil
+	}
+	return ok, err
+}
+
+// Ancients returns the number of blocks frozen.
+func (db *freezerdb) Ancients() uint64 {
+	return db.freezer.Ancients()
+}
+
+// AncientSize returns the storage taken by the frozen blocks.
+func (db *freezerdb) AncientSize() common.StorageSize {
+	return db.freezer.AncientSize()
+}
+
+// TruncateAncients discards the frozen blocks from the given number on.
+func (db *freezerdb) TruncateAncients(items uint64) error {
+	return db.freezer.TruncateAncients(items)
+}
+
+// Close stops the freezer and closes both the freezer and the key-value store.
+func (db *freezerdb) Close() {
+	db.freezer.close()
+	db.Database.Close()
+}
@@ -0,0 +1,240 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// errOutOfBounds is returned if the item requested is not contained within
+	// the freezer table.
+	errOutOfBounds = errors.New("out of bounds")
+
+	// errOutOrderInsertion is returned if the user attempts to inject out-of-order
+	// binary blobs into the freezer.
+	errOutOrderInsertion = errors.New("the append operation is out-order")
+)
+
+// indexEntrySize is the size of an index entry, the big endian end offset of
+// the item within the data file.
+const indexEntrySize = 8
+
+// freezerTable is an append-only table of binary blobs, numbered from zero. The
+// blobs are concatenated in a data file, an index file recording the offset
+// each of them ends at.
+type freezerTable struct {
+	name  string
+	index *os.File // File holding the end offsets of the items
+	data  *os.File // File holding the concatenated items
+
+	items    uint64 // Number of items stored in the table
+	dataSize int64  // Size of the data file, the end offset of the last item
+
+	lock sync.RWMutex // Mutex protecting the files against concurrent truncation
+	log  log.Logger
+}
+
+// newFreezerTable opens the given table within the directory, creating it if
+// needed and repairing a table an interrupted append left inconsistent.
+func newFreezerTable(dir, name string) (*freezerTable, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	t := &freezerTable{
+		name:  name,
+		index: index,
+		data:  data,
+		log:   log.New("table", name),
+	}
+	if err := t.repair(); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair drops any partially written index entry, the index entries pointing
+// past the end of the data file and the data not referenced by the index.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	indexSize := stat.Size()
+	items := uint64(indexSize / indexEntrySize)
+
+	if stat, err = t.data.Stat(); err != nil {
+		return err
+	}
+	dataSize := stat.Size()
+
+	var end int64
+	for ; items > 0; items-- {
+		if end, err = t.offset(items); err != nil {
+			return err
+		}
+		if end <= dataSize {
+			break
+		}
+	}
+	if items == 0 {
+		end = 0
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(end); err != nil {
+		return err
+	}
+	if indexSize != int64(items*indexEntrySize) || dataSize != end {
+		t.log.Warn("Repaired freezer table", "items", items, "size", end)
+	}
+	t.items, t.dataSize = items, end
+	return nil
+}
+
+// offset returns the end offset in the data file of the item before the given
+// one, zero for the first item.
+func (t *freezerTable) offset(item uint64) (int64, error) {
+	if item == 0 {
+		return 0, nil
+	}
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64((item-1)*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(buf[:])), nil
+}
+
+// Items returns the number of items stored in the table.
+func (t *freezerTable) Items() uint64 {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.items
+}
+
+// Append injects the given blob as the given item, which must be the next one.
+// The blob is only durable once the table is synced.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if item != t.items {
+		return errOutOrderInsertion
+	}
+	if _, err := t.data.WriteAt(blob, t.dataSize); err != nil {
+		return err
+	}
+	var buf [indexEntrySize]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(t.dataSize)+uint64(len(blob)))
+	if _, err := t.index.WriteAt(buf[:], int64(t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.items++
+	t.dataSize += int64(len(blob))
+	return nil
+}
+
+// Retrieve returns the given item.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if item >= t.items {
+		return nil, errOutOfBounds
+	}
+	start, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.offset(item + 1)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("corrupted index of table %s at item %d", t.name, item)
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, start); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// truncate discards the items from the given one on.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if items >= t.items {
+		return nil
+	}
+	end, err := t.offset(items)
+	if err != nil {
+		return err
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(end); err != nil {
+		return err
+	}
+	t.items, t.dataSize = items, end
+	return nil
+}
+
+// Sync flushes the appended items to disk, the data before the index so that
+// the index never references data lost in a crash.
+func (t *freezerTable) Sync() error {
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the files of the table.
+func (t *freezerTable) Close() error {
+	var errs []error
+	if err := t.index.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.data.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if errs != nil {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Tests that a freezer table stores, retrieves and truncates its items, across
+// reopenings.
+func TestFreezerTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table, err := newFreezerTable(dir, "test")
+	if err != nil {
+		t.Fatalf("failed to open table: %v", err)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if err := table.Append(i, []byte(fmt.Sprintf("item %d", i))); err != nil {
+			t.Fatalf("failed to append item %d: %v", i, err)
+		}
+	}
+	if err := table.Append(20, []byte("gap")); err != errOutOrderInsertion {
+		t.Fatalf("out of order append error mismatch: have %v, want %v", err, errOutOrderInsertion)
+	}
+	if err := table.Sync(); err != nil {
+		t.Fatalf("failed to sync table: %v", err)
+	}
+	table.Close()
+
+	if table, err = newFreezerTable(dir, "test"); err != nil {
+		t.Fatalf("failed to reopen table: %v", err)
+	}
+	defer table.Close()
+
+	if items := table.Items(); items != 10 {
+		t.Fatalf("item count mismatch: have %d, want %d", items, 10)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if blob, err := table.Retrieve(i); err != nil || string(blob) != fmt.Sprintf("item %d", i) {
+			t.Fatalf("item %d mismatch: have %q, %v", i, blob, err)
+		}
+	}
+	if _, err := table.Retrieve(10); err != errOutOfBounds {
+		t.Fatalf("out of bounds error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	if err := table.truncate(4); err != nil {
+		t.Fatalf("failed to truncate table: %v", err)
+	}
+	if _, err := table.Retrieve(4); err != errOutOfBounds {
+		t.Fatalf("truncated item error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	if err := table.Append(4, []byte("replaced")); err != nil {
+		t.Fatalf("failed to append after truncation: %v", err)
+	}
+	if blob, err := table.Retrieve(4); err != nil || string(blob) != "replaced" {
+		t.Fatalf("replaced item mismatch: have %q, %v", blob, err)
+	}
+}
+
+// Tests that reopening a table drops the items whose data was lost.
+func TestFreezerTableRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table, err := newFreezerTable(dir, "test")
+	if err != nil {
+		t.Fatalf("failed to open table: %v", err)
+	}
+	for i := uint64(0); i < 5; i++ {
+		table.Append(i, bytes.Repeat([]byte{byte(i)}, 10))
+	}
+	table.Close()
+
+	// Cut the last item in half, as a crash before syncing the data might
+	if err := os.Truncate(filepath.Join(dir, "test.dat"), 45); err != nil {
+		t.Fatal(err)
+	}
+	if table, err = newFreezerTable(dir, "test"); err != nil {
+		t.Fatalf("failed to reopen table: %v", err)
+	}
+	defer table.Close()
+
+	if items := table.Items(); items != 4 {
+		t.Fatalf("item count mismatch: have %d, want %d", items, 4)
+	}
+	if err := table.Append(4, []byte("new")); err != nil {
+		t.Fatalf("failed to append after repair: %v", err)
+	}
+	if blob, err := table.Retrieve(4); err != nil || string(blob) != "new" {
+		t.Fatalf("appended item mismatch: have %q, %v", blob, err)
+	}
+}
+
+// Tests that the blocks moved to the freezer are deleted from the key-value
+// store, yet still served through the database.
+func TestFreezerDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := ethdb.NewMemDatabase()
+	if db, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, "missing"), 0); err != nil || db != ethdb.Database(kvdb) {
+		t.Fatalf("disabled freezer wrapped the database: %v", err)
+	}
+	var blocks []*types.Block
+	for i := 0; i < 10; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), Extra: []byte("test block")})
+		receipts := types.Receipts{types.NewReceipt(nil, false, uint64(i))}
+		receipts[0].Logs = []*types.Log{}
+
+		WriteBlock(kvdb, block)
+		WriteReceipts(kvdb, block.Hash(), block.NumberU64(), receipts)
+		WriteCanonicalHash(kvdb, block.Hash(), block.NumberU64())
+		kvdb.Put(privateBloomKey(block.NumberU64()), []byte{byte(i)})
+		blocks = append(blocks, block)
+	}
+	WriteHeadBlockHash(kvdb, blocks[9].Hash())
+
+	// A side chain block of a frozen number stays in the key-value store
+	side := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), Extra: []byte("side block")})
+	WriteBlock(kvdb, side)
+
+	f, err := newFreezer(dir, 3)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	if frozen, err := f.freezeBatch(kvdb); err != nil || frozen != 6 {
+		t.Fatalf("frozen blocks mismatch: have %d, %v, want %d", frozen, err, 6)
+	}
+	db := &freezerdb{Database: kvdb, freezer: f}
+	defer db.Close()
+
+	for i, block := range blocks {
+		number := block.NumberU64()
+		if frozen := i < 6; frozen == HasHeader(kvdb, block.Hash(), number) {
+			t.Errorf("block %d: key-value store presence mismatch: frozen %v", i, frozen)
+		}
+		if header := ReadHeader(db, block.Hash(), number); header == nil || header.Hash() != block.Hash() {
+			t.Errorf("block %d: header mismatch", i)
+		}
+		if body := ReadBody(db, block.Hash(), number); body == nil {
+			t.Errorf("block %d: body missing", i)
+		}
+		if !HasBody(db, block.Hash(), number) {
+			t.Errorf("block %d: body not reported", i)
+		}
+		if receipts := ReadReceipts(db, block.Hash(), number); len(receipts) != 1 || receipts[0].CumulativeGasUsed != uint64(i) {
+			t.Errorf("block %d: receipts mismatch: %v", i, receipts)
+		}
+		if bloom, _ := db.Get(privateBloomKey(number)); !bytes.Equal(bloom, []byte{byte(i)}) {
+			t.Errorf("block %d: private bloom mismatch: have %x", i, bloom)
+		}
+	}
+	if header := ReadHeader(db, side.Hash(), 2); header == nil || header.Hash() != side.Hash() {
+		t.Errorf("side chain header mismatch")
+	}
+	if header := ReadHeader(db, common.Hash{0x01}, 2); header != nil {
+		t.Errorf("unknown header served from the freezer")
+	}
+	stats, err := InspectDatabase(db)
+	if err != nil {
+		t.Fatalf("failed to inspect database: %v", err)
+	}
+	if stat := stats[len(stats)-1]; stat.Category != "Ancient blocks" || stat.Count != 6 {
+		t.Errorf("ancient blocks mismatch: have %s %d", stat.Category, stat.Count)
+	}
+	if err := db.TruncateAncients(4); err != nil {
+		t.Fatalf("failed to truncate freezer: %v", err)
+	}
+	if db.Ancients() != 4 {
+		t.Errorf("ancient count mismatch: have %d, want %d", db.Ancients(), 4)
+	}
+	if header := ReadHeader(db, blocks[5].Hash(), 5); header != nil {
+		t.Errorf("truncated header still served")
+	}
+}
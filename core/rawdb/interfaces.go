@@ -16,6 +16,8 @@
 
 package rawdb
 
+import "github.com/ethereum/go-ethereum/common"
+
 // DatabaseReader wraps the Has and Get method of a backing data store.
 type DatabaseReader interface {
 	Has(key []byte) (bool, error)
@@ -31,3 +33,15 @@ type DatabaseWriter interface {
 type DatabaseDeleter interface {
 	Delete(key []byte) error
 }
+
+// AncientStore is implemented by the databases moving old blocks to a freezer.
+type AncientStore interface {
+	// Ancients returns the number of blocks frozen.
+	Ancients() uint64
+
+	// AncientSize returns the storage taken by the frozen blocks.
+	AncientSize() common.StorageSize
+
+	// TruncateAncients discards the frozen blocks from the given number on.
+	TruncateAncients(items uint64) error
+}
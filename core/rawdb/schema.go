@@ -59,6 +59,16 @@ var (
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
+	// Quorum: private state prefixes, shared with the accessors of the core package.
+	PrivateRootPrefix    = []byte("P")   // PrivateRootPrefix + block root -> private state root
+	PrivateBloomPrefix   = []byte("Pb")  // PrivateBloomPrefix + num (uint64 big endian) -> private bloom
+	TenantRootPrefix     = []byte("Pt")  // TenantRootPrefix + tenant hash + block root -> private state root
+	TenantBloomPrefix    = []byte("Ptb") // TenantBloomPrefix + tenant hash + num (uint64 big endian) -> private bloom
+	TenantReceiptsPrefix = []byte("Ptr") // TenantReceiptsPrefix + tenant hash + block hash -> private receipts
+
+	// QuorumEIP155ActivatedKey flags the activation of EIP155 on a Quorum chain.
+	QuorumEIP155ActivatedKey = []byte("quorum155active")
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
@@ -108,6 +118,11 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// privateBloomKey = PrivateBloomPrefix + num (uint64 big endian)
+func privateBloomKey(number uint64) []byte {
+	return append(PrivateBloomPrefix, encodeBlockNumber(number)...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -209,7 +209,11 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	publicState := st.state
 	if msg, ok := msg.(PrivateMessage); ok && isQuorum && msg.IsPrivate() {
 		isPrivate = true
-		data, err = private.P.Receive(st.data)
+		if tenant := st.evm.Tenant(); tenant != "" {
+			data, err = private.P.ReceiveFor(st.data, tenant)
+		} else {
+			data, err = private.P.Receive(st.data)
+		}
 		// Increment the public account nonce if:
 		// 1. Tx is private and *not* a participant of the group and either call or create
 		// 2. Tx is private we are part of the group and is a call
@@ -244,7 +248,14 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		vmerr error
 	)
 	if contractCreation {
-		ret, _, leftoverGas, vmerr = evm.Create(sender, data, st.gas, st.value)
+		var address common.Address
+		ret, address, leftoverGas, vmerr = evm.Create(sender, data, st.gas, st.value)
+
+		// Quorum: record the creator of a private contract as its participant,
+		// which the contract extension workflow checks the approvers against
+		if isPrivate && len(data) != 0 && vmerr == nil && evm.ChainConfig().IsContractExtension(evm.BlockNumber) {
+			writeExtensionParticipants(evm.PrivateState(), address, []common.Address{sender.Address()})
+		}
 	} else {
 		// Increment the account nonce only if the transaction isn't private.
 		// If the transaction is private it has already been incremented on
@@ -265,7 +276,12 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			return nil, 0, false, nil
 		}
 
-		ret, leftoverGas, vmerr = evm.Call(sender, to, data, st.gas, st.value)
+		// Quorum: the contract extension workflow is recorded in the private state
+		if isPrivate && to == ExtensionAddress && evm.ChainConfig().IsContractExtension(evm.BlockNumber) {
+			leftoverGas, vmerr = st.gas, applyExtension(evm.PrivateState(), sender.Address(), msg.Nonce(), evm.BlockNumber, data)
+		} else {
+			ret, leftoverGas, vmerr = evm.Call(sender, to, data, st.gas, st.value)
+		}
 	}
 	if vmerr != nil {
 		log.Info("VM returned with error", "err", vmerr)
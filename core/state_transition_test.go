@@ -113,3 +113,7 @@ func (spm *StubPrivateTransactionManager) Receive(data []byte) ([]byte, error) {
 	}
 	return nil, nil
 }
+
+func (spm *StubPrivateTransactionManager) ReceiveFor(data []byte, to string) ([]byte, error) {
+	return spm.Receive(data)
+}
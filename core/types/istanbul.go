@@ -40,28 +40,41 @@ type IstanbulExtra struct {
 	Validators    []common.Address
 	Seal          []byte
 	CommittedSeal [][]byte
+
+	// BLSRegistrations are the BLS keys of validators registered by the block.
+	// They trail the other fields, so that the extra-data of blocks without
+	// registrations is encoded the same as before.
+	BLSRegistrations [][]byte
 }
 
 // EncodeRLP serializes ist into the Ethereum RLP format.
 func (ist *IstanbulExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		ist.Validators,
 		ist.Seal,
 		ist.CommittedSeal,
-	})
+	}
+	for _, registration := range ist.BLSRegistrations {
+		fields = append(fields, registration)
+	}
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the istanbul fields from a RLP stream.
 func (ist *IstanbulExtra) DecodeRLP(s *rlp.Stream) error {
 	var istanbulExtra struct {
-		Validators    []common.Address
-		Seal          []byte
-		CommittedSeal [][]byte
+		Validators       []common.Address
+		Seal             []byte
+		CommittedSeal    [][]byte
+		BLSRegistrations [][]byte `rlp:"tail"`
 	}
 	if err := s.Decode(&istanbulExtra); err != nil {
 		return err
 	}
 	ist.Validators, ist.Seal, ist.CommittedSeal = istanbulExtra.Validators, istanbulExtra.Seal, istanbulExtra.CommittedSeal
+	if len(istanbulExtra.BLSRegistrations) > 0 {
+		ist.BLSRegistrations = istanbulExtra.BLSRegistrations
+	}
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 func TestHeaderHash(t *testing.T) {
@@ -86,3 +87,34 @@ func TestExtractToIstanbul(t *testing.T) {
 		}
 	}
 }
+
+func TestIstanbulExtraRegistrations(t *testing.T) {
+	extra := &IstanbulExtra{
+		Validators:    []common.Address{common.HexToAddress("0x44add0ec310f115a0e603b2d7db9f067778eaf8a")},
+		Seal:          []byte{},
+		CommittedSeal: [][]byte{},
+	}
+	plain, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra.BLSRegistrations = [][]byte{{1, 2, 3}, {4, 5}}
+	enc, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Registrations trail the fields known to earlier versions (short lists)
+	if !bytes.HasPrefix(enc[1:], plain[1:]) {
+		t.Errorf("encoding mismatch: have %x, want prefix %x", enc, plain)
+	}
+	for _, data := range [][]byte{plain, enc} {
+		h := &Header{Extra: append(make([]byte, IstanbulExtraVanity), data...)}
+		decoded, err := ExtractIstanbulExtra(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, err := rlp.EncodeToBytes(decoded); err != nil || !bytes.Equal(have, data) {
+			t.Errorf("reencoding mismatch: have %x, want %x", have, data)
+		}
+	}
+}
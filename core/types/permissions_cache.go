@@ -108,6 +108,8 @@ type PermissionConfig struct {
 	Accounts      []common.Address `json:"accounts"` //initial list of account that need full access
 	SubOrgDepth   *big.Int         `json:"subOrgDepth"`
 	SubOrgBreadth *big.Int         `json:"subOrgBreadth"`
+
+	DeactivateEquivocatingNodes bool `json:"deactivateEquivocatingNodes"` // drop the Istanbul validators caught equivocating from the permissioned nodes
 }
 
 type OrgKey struct {
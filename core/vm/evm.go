@@ -50,6 +50,9 @@ type (
 	// GetHashFunc returns the nth block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
 	GetHashFunc func(uint64) common.Hash
+	// FrozenFunc returns whether the contract at the given address is frozen,
+	// calls to it being read-only.
+	FrozenFunc func(StateDB, common.Address) bool
 )
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
@@ -89,6 +92,9 @@ type Context struct {
 	Transfer TransferFunc
 	// GetHash returns the hash corresponding to n
 	GetHash GetHashFunc
+	// Quorum: Frozen reports the private contracts frozen while their state is
+	// shared with a new party, if set
+	Frozen FrozenFunc
 
 	// Message information
 	Origin   common.Address // Provides information for ORIGIN
@@ -272,7 +278,9 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 		}()
 	}
-	ret, err = run(evm, contract, input, false)
+	// Quorum: a frozen contract can't be modified, nor modify other contracts
+	frozen := evm.Context.Frozen != nil && evm.Context.Frozen(evm.StateDB, addr)
+	ret, err = run(evm, contract, input, frozen)
 
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
@@ -584,6 +592,20 @@ func getDualState(env *EVM, addr common.Address) StateDB {
 
 func (env *EVM) PublicState() PublicState   { return env.publicState }
 func (env *EVM) PrivateState() PrivateState { return env.privateState }
+
+// Tenant returns the enclave key of the tenant whose private state is processed.
+func (env *EVM) Tenant() string { return env.vmConfig.Tenant }
+
+// IsPrivateState reports whether the code being executed runs against the
+// private state of a private transaction.
+func (env *EVM) IsPrivateState() bool {
+	return env.privateState != env.publicState && env.StateDB == env.privateState
+}
+
+// IsQuorumReadOnly reports whether the code being executed is a public contract
+// called from a private one, which can't modify the state.
+func (env *EVM) IsQuorumReadOnly() bool { return env.quorumReadOnly }
+
 func (env *EVM) Push(statedb StateDB) {
 	// Quorum : the read only depth to be set up only once for the entire
 	// op code execution. This will be set first time transition from
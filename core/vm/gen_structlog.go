@@ -27,6 +27,9 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 		Depth         int                         `json:"depth"`
 		RefundCounter uint64                      `json:"refund"`
 		Err           error                       `json:"-"`
+		Private       bool                        `json:"private,omitempty"`
+		ReadOnly      bool                        `json:"readOnly,omitempty"`
+		PrivateReads  map[common.Hash]common.Hash `json:"-"`
 		OpName        string                      `json:"opName"`
 		ErrorString   string                      `json:"error"`
 	}
@@ -47,6 +50,9 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 	enc.Depth = s.Depth
 	enc.RefundCounter = s.RefundCounter
 	enc.Err = s.Err
+	enc.Private = s.Private
+	enc.ReadOnly = s.ReadOnly
+	enc.PrivateReads = s.PrivateReads
 	enc.OpName = s.OpName()
 	enc.ErrorString = s.ErrorString()
 	return json.Marshal(&enc)
@@ -66,6 +72,9 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 		Depth         *int                        `json:"depth"`
 		RefundCounter *uint64                     `json:"refund"`
 		Err           error                       `json:"-"`
+		Private       *bool                       `json:"private,omitempty"`
+		ReadOnly      *bool                       `json:"readOnly,omitempty"`
+		PrivateReads  map[common.Hash]common.Hash `json:"-"`
 	}
 	var dec StructLog
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -107,5 +116,14 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 	if dec.Err != nil {
 		s.Err = dec.Err
 	}
+	if dec.Private != nil {
+		s.Private = *dec.Private
+	}
+	if dec.ReadOnly != nil {
+		s.ReadOnly = *dec.ReadOnly
+	}
+	if dec.PrivateReads != nil {
+		s.PrivateReads = dec.PrivateReads
+	}
 	return nil
 }
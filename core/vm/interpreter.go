@@ -46,6 +46,10 @@ type Config struct {
 	EWASMInterpreter string
 	// Type of the EVM interpreter
 	EVMInterpreter string
+
+	// Quorum: enclave key of the tenant whose private state is processed,
+	// empty for the default private state
+	Tenant string
 }
 
 // Interpreter is used to run Ethereum based contracts and will utilise the
@@ -67,6 +67,9 @@ type StructLog struct {
 	Depth         int                         `json:"depth"`
 	RefundCounter uint64                      `json:"refund"`
 	Err           error                       `json:"-"`
+	Private       bool                        `json:"private,omitempty"`  // Quorum: executed against the private state
+	ReadOnly      bool                        `json:"readOnly,omitempty"` // Quorum: public contract called from a private one
+	PrivateReads  map[common.Hash]common.Hash `json:"-"`                  // Quorum: values loaded from the private state
 }
 
 // overrides for gencodec
@@ -114,6 +117,7 @@ type StructLogger struct {
 
 	logs          []StructLog
 	changedValues map[common.Address]Storage
+	privateReads  map[common.Address]Storage // Quorum: values loaded from the private state
 	output        []byte
 	err           error
 }
@@ -122,6 +126,7 @@ type StructLogger struct {
 func NewStructLogger(cfg *LogConfig) *StructLogger {
 	logger := &StructLogger{
 		changedValues: make(map[common.Address]Storage),
+		privateReads:  make(map[common.Address]Storage),
 	}
 	if cfg != nil {
 		logger.cfg = *cfg
@@ -158,6 +163,16 @@ func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost ui
 		)
 		l.changedValues[contract.Address()][address] = value
 	}
+	// Quorum: capture the SLOAD opcodes run against the private state in a
+	// separate container, the private state being otherwise invisible to the
+	// trace.
+	if op == SLOAD && stack.len() >= 1 && env.IsPrivateState() {
+		if l.privateReads[contract.Address()] == nil {
+			l.privateReads[contract.Address()] = make(Storage)
+		}
+		address := common.BigToHash(stack.data[stack.len()-1])
+		l.privateReads[contract.Address()][address] = env.StateDB.GetState(contract.Address(), address)
+	}
 	// Copy a snapstot of the current memory state to a new buffer
 	var mem []byte
 	if !l.cfg.DisableMemory {
@@ -173,12 +188,15 @@ func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost ui
 		}
 	}
 	// Copy a snapshot of the current storage to a new container
-	var storage Storage
+	var storage, privateReads Storage
 	if !l.cfg.DisableStorage {
 		storage = l.changedValues[contract.Address()].Copy()
+		if reads := l.privateReads[contract.Address()]; reads != nil {
+			privateReads = reads.Copy()
+		}
 	}
 	// create a new snaptshot of the EVM.
-	log := StructLog{pc, op, gas, cost, mem, memory.Len(), stck, storage, depth, env.StateDB.GetRefund(), err}
+	log := StructLog{pc, op, gas, cost, mem, memory.Len(), stck, storage, depth, env.StateDB.GetRefund(), err, env.IsPrivateState(), env.IsQuorumReadOnly(), privateReads}
 
 	l.logs = append(l.logs, log)
 	return nil
@@ -237,6 +255,12 @@ func WriteTrace(writer io.Writer, logs []StructLog) {
 				fmt.Fprintf(writer, "%x: %x\n", h, item)
 			}
 		}
+		if len(log.PrivateReads) > 0 {
+			fmt.Fprintln(writer, "Private reads:")
+			for h, item := range log.PrivateReads {
+				fmt.Fprintf(writer, "%x: %x\n", h, item)
+			}
+		}
 		fmt.Fprintln(writer)
 	}
 }
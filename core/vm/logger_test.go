@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -69,3 +70,36 @@ func TestStoreCapture(t *testing.T) {
 		t.Errorf("expected %x, got %x", exp, logger.changedValues[contract.Address()][index])
 	}
 }
+
+func TestPrivateLoadCapture(t *testing.T) {
+	var (
+		publicState, _  = state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+		privateState, _ = state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+		contract        = NewContract(&dummyContractRef{}, &dummyContractRef{}, new(big.Int), 0)
+		value           = common.BigToHash(big.NewInt(1))
+	)
+	publicState.SetState(contract.Address(), common.Hash{}, value)
+	privateState.SetState(contract.Address(), common.Hash{}, value)
+
+	for _, private := range []bool{false, true} {
+		env := NewEVM(Context{}, publicState, publicState, params.TestChainConfig, Config{})
+		if private {
+			env = NewEVM(Context{}, publicState, privateState, params.TestChainConfig, Config{})
+		}
+		logger := NewStructLogger(nil)
+		stack := newstack()
+		stack.push(big.NewInt(0))
+		logger.CaptureState(env, 0, SLOAD, 0, 0, NewMemory(), stack, contract, 0, nil)
+
+		loaded := logger.StructLogs()[0].PrivateReads
+		if private && loaded[common.Hash{}] != value {
+			t.Errorf("private load not captured: have %x, want %x", loaded[common.Hash{}], value)
+		}
+		if !private && len(loaded) != 0 {
+			t.Errorf("public load captured: %v", loaded)
+		}
+		if storage := logger.StructLogs()[0].Storage; len(storage) != 0 {
+			t.Errorf("load captured as storage: %v", storage)
+		}
+	}
+}
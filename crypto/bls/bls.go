@@ -0,0 +1,198 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bls implements BLS signatures over the bn256 curve, with signatures
+// in G1 and public keys in G2, so that signatures on the same message can be
+// aggregated into a single one, verified with a single pairing check.
+//
+// Aggregating public keys is only safe once every key holder has proven the
+// possession of its secret key, see SecretKey.ProvePossession.
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+const (
+	// PublicKeyLength is the length of a marshalled public key.
+	PublicKeyLength = 128
+	// SignatureLength is the length of a marshalled signature.
+	SignatureLength = 64
+)
+
+var (
+	// order is the order of the bn256 groups.
+	order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	// fieldModulus is the modulus of the base field of the bn256 curve.
+	fieldModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+	// Domains separating the hashes of messages from the ones of proofs of
+	// possession.
+	messageDomain    = []byte("BLS_SIG_BN256G1")
+	possessionDomain = []byte("BLS_POP_BN256G1")
+
+	errInvalidPublicKey = errors.New("bls: invalid public key")
+	errInvalidSignature = errors.New("bls: invalid signature")
+)
+
+// SecretKey is a BLS secret key.
+type SecretKey struct {
+	x *big.Int
+}
+
+// DeriveKey derives a secret key from the given seed, e.g. an existing private
+// key, so that no other key needs to be managed.
+func DeriveKey(seed []byte) *SecretKey {
+	for i := byte(0); ; i++ {
+		x := new(big.Int).SetBytes(crypto.Keccak256([]byte("BLS_KEYGEN"), seed, []byte{i}))
+		if x.Mod(x, order).Sign() != 0 {
+			return &SecretKey{x: x}
+		}
+	}
+}
+
+// PublicKey returns the public key matching the secret key.
+func (k *SecretKey) PublicKey() *PublicKey {
+	return &PublicKey{p: new(bn256.G2).ScalarBaseMult(k.x)}
+}
+
+// Sign signs the given message.
+func (k *SecretKey) Sign(msg []byte) *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(messageDomain, msg), k.x)}
+}
+
+// ProvePossession signs the public key of the secret key, proving that the
+// public key isn't derived from the ones of other key holders.
+func (k *SecretKey) ProvePossession() *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(possessionDomain, k.PublicKey().Marshal()), k.x)}
+}
+
+// PublicKey is a BLS public key.
+type PublicKey struct {
+	p *bn256.G2
+}
+
+// UnmarshalPublicKey decodes a public key, checking it's a valid point of the
+// G2 group other than the identity.
+func UnmarshalPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLength {
+		return nil, errInvalidPublicKey
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, errInvalidPublicKey
+	}
+	// Reject the identity, and points outside of the prime order subgroup
+	infinity := make([]byte, PublicKeyLength)
+	if string(b) == string(infinity) || string(new(bn256.G2).ScalarMult(p, order).Marshal()) != string(infinity) {
+		return nil, errInvalidPublicKey
+	}
+	return &PublicKey{p: p}, nil
+}
+
+// Marshal encodes the public key.
+func (pk *PublicKey) Marshal() []byte {
+	return pk.p.Marshal()
+}
+
+// Verify checks the signature of the given message.
+func (pk *PublicKey) Verify(msg []byte, sig *Signature) bool {
+	return verify(pk.p, hashToG1(messageDomain, msg), sig.p)
+}
+
+// VerifyPossession checks a proof of possession of the public key.
+func (pk *PublicKey) VerifyPossession(proof *Signature) bool {
+	return verify(pk.p, hashToG1(possessionDomain, pk.Marshal()), proof.p)
+}
+
+// AggregatePublicKeys returns the public key verifying the aggregation of the
+// signatures of the given keys on a message.
+func AggregatePublicKeys(keys []*PublicKey) *PublicKey {
+	agg := new(bn256.G2).ScalarBaseMult(new(big.Int))
+	for _, key := range keys {
+		agg = new(bn256.G2).Add(agg, key.p)
+	}
+	return &PublicKey{p: agg}
+}
+
+// Signature is a BLS signature, or an aggregation of signatures.
+type Signature struct {
+	p *bn256.G1
+}
+
+// UnmarshalSignature decodes a signature.
+func UnmarshalSignature(b []byte) (*Signature, error) {
+	if len(b) != SignatureLength {
+		return nil, errInvalidSignature
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, errInvalidSignature
+	}
+	return &Signature{p: p}, nil
+}
+
+// Marshal encodes the signature.
+func (sig *Signature) Marshal() []byte {
+	return sig.p.Marshal()
+}
+
+// AggregateSignatures aggregates the given signatures of the same message into
+// one, to be verified against the aggregation of the signers' public keys.
+func AggregateSignatures(sigs []*Signature) *Signature {
+	agg := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	for _, sig := range sigs {
+		agg = new(bn256.G1).Add(agg, sig.p)
+	}
+	return &Signature{p: agg}
+}
+
+// verify checks that e(sig, g2) == e(h, pk).
+func verify(pk *bn256.G2, h, sig *bn256.G1) bool {
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	return bn256.PairingCheck([]*bn256.G1{sig, new(bn256.G1).Neg(h)}, []*bn256.G2{g2, pk})
+}
+
+// hashToG1 maps a message to a point of G1, by hashing it to x coordinates
+// until one is on the curve y² = x³ + 3.
+func hashToG1(domain, msg []byte) *bn256.G1 {
+	three := big.NewInt(3)
+	for i := byte(0); ; i++ {
+		x := new(big.Int).SetBytes(crypto.Keccak256(domain, msg, []byte{i}))
+		x.Mod(x, fieldModulus)
+
+		rhs := new(big.Int).Exp(x, three, fieldModulus)
+		rhs.Add(rhs, three).Mod(rhs, fieldModulus)
+		y := new(big.Int).ModSqrt(rhs, fieldModulus)
+		if y == nil {
+			continue
+		}
+		point := make([]byte, 64)
+		math.ReadBits(x, point[:32])
+		math.ReadBits(y, point[32:])
+
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(point); err != nil {
+			continue
+		}
+		return p
+	}
+}
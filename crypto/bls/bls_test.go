@@ -0,0 +1,76 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bls
+
+import (
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	key := DeriveKey([]byte("seed"))
+	msg := []byte("message")
+
+	pub, err := UnmarshalPublicKey(key.PublicKey().Marshal())
+	if err != nil {
+		t.Fatalf("failed to decode public key: %v", err)
+	}
+	sig, err := UnmarshalSignature(key.Sign(msg).Marshal())
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	if !pub.Verify(msg, sig) {
+		t.Errorf("valid signature rejected")
+	}
+	if pub.Verify([]byte("other message"), sig) {
+		t.Errorf("signature of another message accepted")
+	}
+	if DeriveKey([]byte("other seed")).PublicKey().Verify(msg, sig) {
+		t.Errorf("signature accepted with another key")
+	}
+	if !pub.VerifyPossession(key.ProvePossession()) {
+		t.Errorf("valid proof of possession rejected")
+	}
+	if pub.VerifyPossession(sig) {
+		t.Errorf("message signature accepted as proof of possession")
+	}
+	if _, err := UnmarshalPublicKey(make([]byte, PublicKeyLength)); err == nil {
+		t.Errorf("identity accepted as public key")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	msg := []byte("message")
+
+	var (
+		keys []*PublicKey
+		sigs []*Signature
+	)
+	for _, seed := range []string{"a", "b", "c"} {
+		key := DeriveKey([]byte(seed))
+		keys = append(keys, key.PublicKey())
+		sigs = append(sigs, key.Sign(msg))
+	}
+	if !AggregatePublicKeys(keys).Verify(msg, AggregateSignatures(sigs)) {
+		t.Errorf("valid aggregated signature rejected")
+	}
+	if AggregatePublicKeys(keys).Verify(msg, AggregateSignatures(sigs[:2])) {
+		t.Errorf("aggregated signature accepted with a missing signature")
+	}
+	if AggregatePublicKeys(keys[:2]).Verify(msg, AggregateSignatures(sigs)) {
+		t.Errorf("aggregated signature accepted with a missing key")
+	}
+}
@@ -389,7 +389,11 @@ type storageEntry struct {
 
 // StorageRangeAt returns the storage at the given block height and transaction index.
 func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
-	_, _, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
+	tenant, err := api.eth.APIBackend.tenant(ctx)
+	if err != nil {
+		return StorageRangeResult{}, err
+	}
+	_, _, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0, tenant)
 	if err != nil {
 		return StorageRangeResult{}, err
 	}
@@ -18,6 +18,7 @@ package eth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"math/big"
 
@@ -97,26 +98,39 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumb
 }
 
 func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (vm.MinimalApiState, *types.Header, error) {
+	// Quorum: the private state of a tenant is selected per request, the requests
+	// not authorized to read the default private state being served the public
+	// state only
+	tenant, err := b.tenant(ctx)
+	publicOnly := err == errOperatorTokenRequired
+	if err != nil && !publicOnly {
+		return nil, nil, err
+	}
 	// Pending state is only known by the miner
 	if blockNr == rpc.PendingBlockNumber {
-		if b.eth.protocolManager.raftMode {
+		// The miner only keeps the pending default private state
+		if b.eth.protocolManager.raftMode || tenant != "" || publicOnly {
 			// Use latest instead.
-			header, err := b.HeaderByNumber(ctx, rpc.LatestBlockNumber)
-			if header == nil || err != nil {
-				return nil, nil, err
-			}
-			publicState, privateState, err := b.eth.BlockChain().StateAt(header.Root)
-			return EthAPIState{publicState, privateState}, header, err
+			blockNr = rpc.LatestBlockNumber
+		} else {
+			block, publicState, privateState := b.eth.miner.Pending()
+			return EthAPIState{publicState, privateState}, block.Header(), nil
 		}
-		block, publicState, privateState := b.eth.miner.Pending()
-		return EthAPIState{publicState, privateState}, block.Header(), nil
 	}
 	// Otherwise resolve the block number and return its state
 	header, err := b.HeaderByNumber(ctx, blockNr)
 	if header == nil || err != nil {
 		return nil, nil, err
 	}
-	stateDb, privateState, err := b.eth.BlockChain().StateAt(header.Root)
+	if publicOnly {
+		stateDb, _, err := b.eth.BlockChain().StateAt(header.Root)
+		if err != nil {
+			return nil, nil, err
+		}
+		privateState, err := state.New(common.Hash{}, stateDb.Database())
+		return EthAPIState{stateDb, privateState}, header, err
+	}
+	stateDb, privateState, err := b.eth.BlockChain().TenantStateAt(header.Root, tenant)
 	return EthAPIState{stateDb, privateState}, header, err
 }
 
@@ -125,11 +139,14 @@ func (b *EthAPIBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.
 }
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.eth.blockchain.GetReceiptsByHash(hash), nil
+	return b.receipts(ctx, hash)
 }
 
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
-	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
+	receipts, err := b.receipts(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
 	if receipts == nil {
 		return nil, nil
 	}
@@ -158,8 +175,9 @@ func (b *EthAPIBackend) GetEVM(ctx context.Context, msg core.Message, state vm.M
 		to = *msg.To()
 	}
 
+	// A simulated private transaction always executes against the private state
 	privateState := statedb.privateState
-	if !privateState.Exist(to) {
+	if msg, ok := msg.(core.PrivateMessage); (!ok || !msg.IsPrivate()) && !privateState.Exist(to) {
 		privateState = statedb.state
 	}
 
@@ -266,6 +284,59 @@ func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 	}
 }
 
+// errOperatorTokenRequired is returned if a request selects the default private
+// state of a node hosting tenants without the operator token.
+var errOperatorTokenRequired = errors.New("default private state requires the operator token")
+
+// tenant returns the tenant selected by the request, empty for the default
+// private state. Selecting a tenant requires its API token, and the default
+// private state of a node hosting tenants, which holds the payloads of all of
+// them, the operator token.
+func (b *EthAPIBackend) tenant(ctx context.Context) (string, error) {
+	tenant, _ := ctx.Value("Tenant").(string)
+	if !b.eth.blockchain.HasTenant(tenant) {
+		return "", core.ErrUnknownTenant
+	}
+	if tenant == "" && len(b.eth.blockchain.Tenants()) == 0 {
+		return "", nil
+	}
+	token, _ := ctx.Value("TenantToken").(string)
+	if !authorizedTenant(b.eth.config.TenantTokens, tenant, token) {
+		if tenant == "" {
+			return "", errOperatorTokenRequired
+		}
+		return "", core.ErrUnauthorizedTenant
+	}
+	return tenant, nil
+}
+
+// authorizedTenant reports whether the given token is the API token of the
+// given tenant, the operator token being the one of the empty tenant.
+func authorizedTenant(tokens map[string]string, tenant, token string) bool {
+	want, ok := tokens[tenant]
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
+
+// HasTenants reports whether the node hosts the private states of tenants, whose
+// private logs are then withheld from the subscriptions.
+func (b *EthAPIBackend) HasTenants() bool {
+	return len(b.eth.blockchain.Tenants()) > 0
+}
+
+// receipts returns the receipts of the given block, the receipts of the private
+// transactions being the ones of the tenant of the request, or their public
+// receipts if it isn't authorized to read the default private state.
+func (b *EthAPIBackend) receipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	tenant, err := b.tenant(ctx)
+	if err == errOperatorTokenRequired {
+		return b.eth.blockchain.GetPublicReceiptsByHash(hash), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.eth.blockchain.GetTenantReceiptsByHash(hash, tenant), nil
+}
+
 // used by Quorum
 type EthAPIState struct {
 	state, privateState *state.StateDB
@@ -0,0 +1,43 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import "testing"
+
+func TestAuthorizedTenant(t *testing.T) {
+	tokens := map[string]string{"": "secret-operator", "A": "secret-a", "B": "secret-b"}
+
+	tests := []struct {
+		tenant, token string
+		authorized    bool
+	}{
+		{"", "", false},
+		{"", "secret-a", false},
+		{"", "secret-operator", true},
+		{"A", "secret-operator", false},
+		{"A", "secret-a", true},
+		{"A", "", false},
+		{"A", "secret-b", false},
+		{"A", "secret-a ", false},
+		{"C", "", false},
+	}
+	for i, tt := range tests {
+		if authorized := authorizedTenant(tokens, tt.tenant, tt.token); authorized != tt.authorized {
+			t.Errorf("test %d: tenant %q with token %q: have %v, want %v", i, tt.tenant, tt.token, authorized, tt.authorized)
+		}
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
@@ -138,6 +139,11 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 	}
 	sub := notifier.CreateSubscription()
 
+	// Quorum: trace against the private state of the tenant of the request
+	tenant, err := api.eth.APIBackend.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 	// Ensure we have a valid starting state before doing any work
 	origin := start.NumberU64()
 	database := state.NewDatabase(api.eth.ChainDb())
@@ -148,7 +154,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			return nil, fmt.Errorf("parent block #%d not found", number-1)
 		}
 	}
-	statedb, privateStateDb, err := api.eth.blockchain.StateAt(start.Root())
+	statedb, privateStateDb, err := api.eth.blockchain.TenantStateAt(start.Root(), tenant)
 	if err != nil {
 		// If the starting state is missing, allow some number of blocks to be reexecuted
 		reexec := defaultTraceReexec
@@ -161,7 +167,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			if start == nil {
 				break
 			}
-			statedb, privateStateDb, err = api.eth.blockchain.StateAt(start.Root())
+			statedb, privateStateDb, err = api.eth.blockchain.TenantStateAt(start.Root(), tenant)
 			if err == nil {
 				break
 			}
@@ -202,7 +208,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					msg, _ := tx.AsMessage(signer)
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain, nil)
 
-					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, task.privateStateDb, config)
+					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, task.privateStateDb, tenant, config)
 					if err != nil {
 						task.results[i] = &txTraceResult{Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
@@ -283,7 +289,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				traced += uint64(len(txs))
 			}
 			// Generate the next state snapshot fast without tracing
-			_, _, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, privateStateDb, vm.Config{})
+			_, _, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, privateStateDb, vm.Config{Tenant: tenant})
 			if err != nil {
 				failed = err
 				break
@@ -430,7 +436,11 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
-	statedb, privateStateDb, err := api.computeStateDB(parent, reexec)
+	tenant, err := api.eth.APIBackend.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statedb, privateStateDb, err := api.computeStateDB(parent, reexec, tenant)
 	if err != nil {
 		return nil, err
 	}
@@ -458,7 +468,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 				msg, _ := txs[task.index].AsMessage(signer)
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 
-				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, task.privateStateDb, config)
+				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, task.privateStateDb, tenant, config)
 				if err != nil {
 					results[task.index] = &txTraceResult{Error: err.Error()}
 					continue
@@ -481,7 +491,7 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 		msg, _ := tx.AsMessage(signer)
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
 
-		vmenv := vm.NewEVM(vmctx, statedb, privateStateDb, api.config, vm.Config{})
+		vmenv := vm.NewEVM(vmctx, statedb, privateStateDb, api.config, vm.Config{Tenant: tenant})
 		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
 			failed = err
 			break
@@ -502,10 +512,11 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 // computeStateDB retrieves the state database associated with a certain block.
 // If no state is locally available for the given block, a number of blocks are
-// attempted to be reexecuted to generate the desired state.
-func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*state.StateDB, *state.StateDB, error) {
+// attempted to be reexecuted to generate the desired state. The private state
+// is the one of the given tenant.
+func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64, tenant string) (*state.StateDB, *state.StateDB, error) {
 	// If we have the state fully available, use that
-	statedb, privateStateDb, err := api.eth.blockchain.StateAt(block.Root())
+	statedb, privateStateDb, err := api.eth.blockchain.TenantStateAt(block.Root(), tenant)
 	if err == nil {
 		return statedb, privateStateDb, nil
 	}
@@ -518,7 +529,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 		if block == nil {
 			break
 		}
-		statedb, privateStateDb, err = api.eth.blockchain.StateAt(block.Root())
+		statedb, privateStateDb, err = api.eth.blockchain.TenantStateAt(block.Root(), tenant)
 		if err == nil {
 			break
 		}
@@ -547,7 +558,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 		if block = api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1); block == nil {
 			return nil, nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
 		}
-		_, _, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, privateStateDb, vm.Config{})
+		_, _, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, privateStateDb, vm.Config{Tenant: tenant})
 		if err != nil {
 			return nil, nil, err
 		}
@@ -589,18 +600,22 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
-	msg, vmctx, statedb, privateStateDb, err := api.computeTxEnv(blockHash, int(index), reexec)
+	tenant, err := api.eth.APIBackend.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg, vmctx, statedb, privateStateDb, err := api.computeTxEnv(blockHash, int(index), reexec, tenant)
 	if err != nil {
 		return nil, err
 	}
 	// Trace the transaction and return
-	return api.traceTx(ctx, msg, vmctx, statedb, privateStateDb, config)
+	return api.traceTx(ctx, msg, vmctx, statedb, privateStateDb, tenant, config)
 }
 
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
-func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, privateStateDb *state.StateDB, config *TraceConfig) (interface{}, error) {
+func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, privateStateDb *state.StateDB, tenant string, config *TraceConfig) (interface{}, error) {
 	// Assemble the structured logger or the JavaScript tracer
 	var (
 		tracer vm.Tracer
@@ -635,12 +650,29 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	}
 
 	// Set the private state to public state if it is not a private message
-	if msg, ok := message.(core.PrivateMessage); !ok || !api.config.IsQuorum || !msg.IsPrivate() {
+	isPrivate := false
+	if msg, ok := message.(core.PrivateMessage); ok && api.config.IsQuorum && msg.IsPrivate() {
+		isPrivate = true
+	} else {
 		privateStateDb = statedb
 	}
+	// Expose the decrypted payload of a private transaction on the parties. As in
+	// the state transition, failing to retrieve it means the node, or the tenant,
+	// isn't a party.
+	var payload []byte
+	if isPrivate && private.P != nil {
+		if tenant != "" {
+			payload, _ = private.P.ReceiveFor(message.Data(), tenant)
+		} else {
+			payload, _ = private.P.Receive(message.Data())
+		}
+	}
+	if tracer, ok := tracer.(*tracers.Tracer); ok && isPrivate {
+		tracer.SetPrivate(payload)
+	}
 
 	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, privateStateDb, api.config, vm.Config{Debug: true, Tracer: tracer})
+	vmenv := vm.NewEVM(vmctx, statedb, privateStateDb, api.config, vm.Config{Debug: true, Tracer: tracer, Tenant: tenant})
 
 	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
 	if err != nil {
@@ -650,10 +682,12 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
 		return &ethapi.ExecutionResult{
-			Gas:         gas,
-			Failed:      failed,
-			ReturnValue: fmt.Sprintf("%x", ret),
-			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
+			Gas:            gas,
+			Failed:         failed,
+			ReturnValue:    fmt.Sprintf("%x", ret),
+			StructLogs:     ethapi.FormatLogs(tracer.StructLogs()),
+			Private:        isPrivate,
+			PrivatePayload: payload,
 		}, nil
 
 	case *tracers.Tracer:
@@ -665,7 +699,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 }
 
 // computeTxEnv returns the execution environment of a certain transaction.
-func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, reexec uint64) (core.Message, vm.Context, *state.StateDB, *state.StateDB, error) {
+func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, reexec uint64, tenant string) (core.Message, vm.Context, *state.StateDB, *state.StateDB, error) {
 	// Create the parent state database
 	block := api.eth.blockchain.GetBlockByHash(blockHash)
 	if block == nil {
@@ -675,7 +709,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 	if parent == nil {
 		return nil, vm.Context{}, nil, nil, fmt.Errorf("parent %x not found", block.ParentHash())
 	}
-	statedb, privateStateDb, err := api.computeStateDB(parent, reexec)
+	statedb, privateStateDb, err := api.computeStateDB(parent, reexec, tenant)
 	if err != nil {
 		return nil, vm.Context{}, nil, nil, err
 	}
@@ -690,7 +724,7 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 			return msg, context, statedb, privateStateDb, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
-		vmenv := vm.NewEVM(context, statedb, privateStateDb, api.config, vm.Config{})
+		vmenv := vm.NewEVM(context, statedb, privateStateDb, api.config, vm.Config{Tenant: tenant})
 		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
 			return nil, vm.Context{}, nil, nil, fmt.Errorf("tx %x failed: %v", tx.Hash(), err)
 		}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -33,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	istanbulBackend "github.com/ethereum/go-ethereum/consensus/istanbul/backend"
+	"github.com/ethereum/go-ethereum/consensus/istanbul/validator"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -50,6 +52,7 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/private"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -140,6 +143,10 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		}
 	}
 
+	if err := checkIstanbulConfig(chainConfig.Istanbul); err != nil {
+		return nil, err
+	}
+
 	if !core.GetIsQuorumEIP155Activated(chainDb) && chainConfig.ChainID != nil {
 		//Upon starting the node, write the flag to disallow changing ChainID/EIP155 block after HF
 		core.WriteQuorumEIP155Activation(chainDb)
@@ -157,7 +164,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		gasPrice:       config.MinerGasPrice,
 		etherbase:      config.Etherbase,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
-		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
+		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms, config.Tenants...),
 	}
 
 	// force to set the istanbul etherbase to node key address
@@ -186,6 +193,22 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(config.Tenants) > 0 {
+		if private.P == nil {
+			return nil, errors.New("tenants require a private transaction manager")
+		}
+		if err := eth.blockchain.SetTenants(config.Tenants); err != nil {
+			return nil, err
+		}
+	}
+	for tenant := range config.TenantTokens {
+		if tenant == "" && len(config.Tenants) == 0 {
+			return nil, errors.New("operator token without tenants")
+		}
+		if !eth.blockchain.HasTenant(tenant) {
+			return nil, fmt.Errorf("API token of unknown tenant %q", tenant)
+		}
+	}
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compat)
@@ -202,6 +225,14 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb, config.RaftMode); err != nil {
 		return nil, err
 	}
+	if err := eth.protocolManager.SetPrivateStatePeers(config.PrivateStatePeers); err != nil {
+		return nil, err
+	}
+	if config.Checkpoint != nil {
+		if err := eth.protocolManager.downloader.SetCheckpoint(config.Checkpoint); err != nil {
+			return nil, err
+		}
+	}
 
 	eth.miner = miner.New(eth, eth.chainConfig, eth.EventMux(), eth.engine, config.MinerRecommit, config.MinerGasFloor, config.MinerGasCeil, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.MinerExtraData, eth.chainConfig.IsQuorum))
@@ -243,13 +274,46 @@ func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Data
 	if db, ok := db.(*ethdb.LDBDatabase); ok {
 		db.Meter("eth/db/chaindata/")
 	}
-	return db, nil
+	// Serve the blocks moved to the freezer, freezing more if requested
+	frdb, err := rawdb.NewDatabaseWithFreezer(db, ctx.ResolvePath(filepath.Join(name, "ancient")), config.FreezerThreshold)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
+// checkIstanbulConfig verifies the Istanbul settings of the genesis block which
+// the consensus engine can't do without.
+func checkIstanbulConfig(config *params.IstanbulConfig) error {
+	if config == nil {
+		return nil
+	}
+	if _, ok := validator.ProposerSelector(istanbul.ProposerPolicy(config.ProposerPolicy)); !ok {
+		return fmt.Errorf("invalid genesis Istanbul config: unknown proposer policy %d", config.ProposerPolicy)
+	}
+	if config.PolicyBlock != nil {
+		if _, ok := validator.ProposerSelector(istanbul.ProposerPolicy(config.NewPolicy)); !ok {
+			return fmt.Errorf("invalid genesis Istanbul config: unknown proposer policy %d", config.NewPolicy)
+		}
+	}
+	if config.RoundChangeTimeoutGrowth != "" {
+		if _, err := istanbul.ParseTimeoutGrowth(config.RoundChangeTimeoutGrowth); err != nil {
+			return fmt.Errorf("invalid genesis Istanbul config: %v", err)
+		}
+	}
+	if config.RoundChangeTimeoutReset != "" {
+		if _, err := istanbul.ParseTimeoutReset(config.RoundChangeTimeoutReset); err != nil {
+			return fmt.Errorf("invalid genesis Istanbul config: %v", err)
+		}
+	}
+	return nil
 }
 
 // CreateConsensusEngine creates the required type of consensus engine instance for an Ethereum service
 func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainConfig, config *Config, notify []string, noverify bool, db ethdb.Database) consensus.Engine {
-	// If proof-of-authority is requested, set it up
-	if chainConfig.Clique != nil {
+	// If proof-of-authority is requested, set it up, unless Istanbul takes over
+	if chainConfig.Clique != nil && (chainConfig.Istanbul == nil || chainConfig.Istanbul.TransitionBlock == nil) {
 		return clique.New(chainConfig.Clique, db)
 	}
 	// If Istanbul is requested, set it up
@@ -259,6 +323,39 @@ func CreateConsensusEngine(ctx *node.ServiceContext, chainConfig *params.ChainCo
 		}
 		config.Istanbul.ProposerPolicy = istanbul.ProposerPolicy(chainConfig.Istanbul.ProposerPolicy)
 		config.Istanbul.Ceil2Nby3Block = chainConfig.Istanbul.Ceil2Nby3Block
+		config.Istanbul.ValidatorContractBlock = chainConfig.Istanbul.ValidatorContractBlock
+		config.Istanbul.ValidatorContract = chainConfig.Istanbul.ValidatorContract
+		config.Istanbul.ProposerPolicyBlock = chainConfig.Istanbul.PolicyBlock
+		config.Istanbul.NewProposerPolicy = istanbul.ProposerPolicy(chainConfig.Istanbul.NewPolicy)
+		config.Istanbul.ValidatorWeights = chainConfig.Istanbul.ValidatorWeights
+		if chainConfig.Istanbul.LivenessWindow != 0 {
+			config.Istanbul.LivenessWindow = chainConfig.Istanbul.LivenessWindow
+		}
+		// The genesis round change timeout settings apply unless set on the node.
+		// They are validated by checkIstanbulConfig when the service starts.
+		config.Istanbul.RoundChangeTimeoutBlock = chainConfig.Istanbul.RoundChangeTimeoutBlock
+		if config.Istanbul.RoundChangeTimeoutGrowth == "" {
+			config.Istanbul.RoundChangeTimeoutGrowth = istanbul.TimeoutGrowth(chainConfig.Istanbul.RoundChangeTimeoutGrowth)
+		}
+		if config.Istanbul.RoundChangeTimeoutIncrement == 0 {
+			config.Istanbul.RoundChangeTimeoutIncrement = chainConfig.Istanbul.RoundChangeTimeoutIncrement
+		}
+		if config.Istanbul.MaxRoundChangeTimeout == 0 {
+			config.Istanbul.MaxRoundChangeTimeout = chainConfig.Istanbul.MaxRoundChangeTimeout
+		}
+		if config.Istanbul.RoundChangeTimeoutReset == "" {
+			config.Istanbul.RoundChangeTimeoutReset = istanbul.TimeoutReset(chainConfig.Istanbul.RoundChangeTimeoutReset)
+		}
+		config.Istanbul.BLSSealBlock = chainConfig.Istanbul.BLSSealBlock
+		config.Istanbul.BLSPublicKeys = make(map[common.Address][]byte, len(chainConfig.Istanbul.BLSKeys))
+		for addr, key := range chainConfig.Istanbul.BLSKeys {
+			config.Istanbul.BLSPublicKeys[addr] = key
+		}
+		config.Istanbul.TransitionBlock = chainConfig.Istanbul.TransitionBlock
+		config.Istanbul.RaftBlock = chainConfig.Istanbul.RaftBlock
+		if chainConfig.Clique != nil {
+			return istanbulBackend.NewTransition(&config.Istanbul, ctx.NodeKey(), db, clique.New(chainConfig.Clique, db))
+		}
 
 		return istanbulBackend.New(&config.Istanbul, ctx.NodeKey(), db)
 	}
@@ -540,6 +637,12 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
+	// Let the consensus engine maintain its connections between validators
+	if overlay, ok := s.engine.(consensus.Overlay); ok {
+		if err := overlay.StartOverlay(s.blockchain, srvr); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -548,6 +651,9 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
+	if overlay, ok := s.engine.(consensus.Overlay); ok {
+		overlay.StopOverlay()
+	}
 	s.engine.Close()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
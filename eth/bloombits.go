@@ -93,14 +93,17 @@ type BloomIndexer struct {
 	gen     *bloombits.Generator // generator to rotate the bloom bits crating the bloom index
 	section uint64               // Section is the section number being processed currently
 	head    common.Hash          // Head is the hash of the last header processed
+	tenants []string             // Quorum: tenants whose private blooms are indexed too
 }
 
 // NewBloomIndexer returns a chain indexer that generates bloom bits data for the
-// canonical chain for fast logs filtering.
-func NewBloomIndexer(db ethdb.Database, size, confirms uint64) *core.ChainIndexer {
+// canonical chain for fast logs filtering. The private blooms of the given
+// tenants are indexed along with the default one.
+func NewBloomIndexer(db ethdb.Database, size, confirms uint64, tenants ...string) *core.ChainIndexer {
 	backend := &BloomIndexer{
-		db:   db,
-		size: size,
+		db:      db,
+		size:    size,
+		tenants: tenants,
 	}
 	table := ethdb.NewTable(db, string(rawdb.BloomBitsIndexPrefix))
 
@@ -121,6 +124,10 @@ func (b *BloomIndexer) Process(ctx context.Context, header *types.Header) error
 	publicBloom := header.Bloom
 	privateBloom := core.GetPrivateBlockBloom(b.db, header.Number.Uint64())
 	publicBloom.OrBloom(privateBloom.Bytes())
+	for _, tenant := range b.tenants {
+		tenantBloom := core.GetTenantPrivateBlockBloom(b.db, tenant, header.Number.Uint64())
+		publicBloom.OrBloom(tenantBloom.Bytes())
+	}
 
 	b.gen.AddBloom(uint(header.Number.Uint64()-b.section*b.size), publicBloom)
 	b.head = header.Hash()
@@ -89,6 +89,12 @@ type Config struct {
 	SyncMode  downloader.SyncMode
 	NoPruning bool
 
+	// Trusted block whose state is synced instead of replaying the chain up to it
+	Checkpoint *downloader.Checkpoint `toml:",omitempty"`
+
+	// Enode URLs of the party peers allowed to fetch the private state of a checkpoint
+	PrivateStatePeers []string `toml:",omitempty"`
+
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
@@ -99,6 +105,7 @@ type Config struct {
 	DatabaseCache      int
 	TrieCache          int
 	TrieTimeout        time.Duration
+	FreezerThreshold   uint64 `toml:",omitempty"` // Number of recent blocks kept out of the freezer, 0 disables freezing
 
 	// Mining-related options
 	Etherbase      common.Address `toml:",omitempty"`
@@ -124,6 +131,10 @@ type Config struct {
 
 	RaftMode             bool
 	EnableNodePermission bool
+	// Enclave keys of the tenants with a private state of their own
+	Tenants []string `toml:",omitempty"`
+	// API tokens authenticating the RPC requests of each tenant
+	TenantTokens map[string]string `toml:"-"`
 	// Istanbul options
 	Istanbul istanbul.Config
 
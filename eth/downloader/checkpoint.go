@@ -0,0 +1,191 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// backupStateBatch is the number of state entries read from a private state
+// backup at once.
+const backupStateBatch = 1024
+
+var (
+	errCheckpointUnreached    = errors.New("peer chain below the trusted checkpoint")
+	errPrivatePeerUnavailable = errors.New("private state peer not connected")
+	errPrivateRootMismatch    = errors.New("private state root of the backup doesn't match the checkpoint")
+)
+
+// Checkpoint is a trusted block whose state a node joining a permissioned network
+// syncs instead of replaying the chain from genesis. The public state is fetched
+// from any peer, the private state from the designated party peer or from a local
+// backup, both being verified against the checkpoint before the node switches to
+// full sync.
+type Checkpoint struct {
+	Number        uint64
+	Hash          common.Hash
+	PrivateRoot   common.Hash `toml:",omitempty"` // Private state root after the block, empty if retrieved from the backup
+	PrivatePeer   string      `toml:",omitempty"` // Enode URL of the party peer serving the private state
+	PrivateBackup string      `toml:",omitempty"` // Chain database holding a copy of the private state
+}
+
+// SetCheckpoint configures the trusted block fast sync pivots at. It must be set
+// before synchronisation starts.
+func (d *Downloader) SetCheckpoint(cp *Checkpoint) error {
+	if cp.Number == 0 || cp.Hash == (common.Hash{}) {
+		return errors.New("checkpoint needs a block number and hash")
+	}
+	if cp.PrivatePeer != "" && cp.PrivateBackup != "" {
+		return errors.New("checkpoint private state can't be both fetched from a peer and a backup")
+	}
+	if cp.PrivatePeer != "" {
+		// The root can't be looked up remotely, the peer only serves trie nodes
+		if cp.PrivateRoot == (common.Hash{}) {
+			return errors.New("checkpoint private state peer needs the private state root")
+		}
+		node, err := enode.ParseV4(cp.PrivatePeer)
+		if err != nil {
+			return fmt.Errorf("invalid checkpoint private state peer: %v", err)
+		}
+		d.checkpointPeer = fmt.Sprintf("%x", node.ID().Bytes()[:8])
+	}
+	if cp.PrivateBackup != "" {
+		if _, err := os.Stat(cp.PrivateBackup); err != nil {
+			return fmt.Errorf("invalid checkpoint private state backup: %v", err)
+		}
+	}
+	if cp.PrivateRoot != (common.Hash{}) && cp.PrivatePeer == "" && cp.PrivateBackup == "" {
+		return errors.New("checkpoint private state root needs a peer or a backup to retrieve it from")
+	}
+	d.checkpoint = cp
+	return nil
+}
+
+// verifyCheckpoint checks that the given headers match the trusted checkpoint.
+func (d *Downloader) verifyCheckpoint(headers []*types.Header) error {
+	if d.checkpoint == nil {
+		return nil
+	}
+	for _, header := range headers {
+		if header.Number.Uint64() == d.checkpoint.Number && header.Hash() != d.checkpoint.Hash {
+			return fmt.Errorf("checkpoint %d hash mismatch: have %x, want %x", d.checkpoint.Number, header.Hash(), d.checkpoint.Hash)
+		}
+	}
+	return nil
+}
+
+// syncCheckpointState completes the state of the checkpoint block once its public
+// state is synced: the private state is retrieved from the configured source and
+// both states are verified to be available before the pivot is committed.
+func (d *Downloader) syncCheckpointState(header *types.Header) error {
+	root := d.checkpoint.PrivateRoot
+	if dir := d.checkpoint.PrivateBackup; dir != "" {
+		backup, err := ethdb.NewDatabase("", dir, 16, 16)
+		if err != nil {
+			return fmt.Errorf("failed to open private state backup: %v", err)
+		}
+		defer backup.Close()
+
+		// The backup must hold the checkpoint and agree on its private state root
+		if rawdb.ReadHeader(backup, header.Hash(), header.Number.Uint64()) == nil {
+			return fmt.Errorf("checkpoint %d missing from private state backup", header.Number)
+		}
+		mapped := core.GetPrivateStateRoot(backup, header.Root)
+		if root != (common.Hash{}) && root != mapped {
+			return errPrivateRootMismatch
+		}
+		root = mapped
+
+		if root != (common.Hash{}) {
+			if err := d.syncStateFromBackup(root, backup); err != nil {
+				return err
+			}
+		}
+	} else if d.checkpoint.PrivatePeer != "" {
+		if err := d.syncStateFromPeer(root, d.checkpointPeer); err != nil {
+			return err
+		}
+	}
+	statedb := state.NewDatabase(d.stateDB)
+	if _, err := state.New(header.Root, statedb); err != nil {
+		return fmt.Errorf("checkpoint public state unavailable: %v", err)
+	}
+	if _, err := state.New(root, statedb); err != nil {
+		return fmt.Errorf("checkpoint private state unavailable: %v", err)
+	}
+	log.Info("Synced the state of the trusted checkpoint", "number", header.Number, "hash", header.Hash(), "root", header.Root, "private", root)
+	return core.WritePrivateStateRoot(d.stateDB, header.Root, root)
+}
+
+// syncStateFromPeer downloads the state trie of the given root from a single peer.
+func (d *Downloader) syncStateFromPeer(root common.Hash, id string) error {
+	if d.peers.Peer(id) == nil {
+		return errPrivatePeerUnavailable
+	}
+	s := newStateSync(d, root)
+	s.peer = id
+	select {
+	case d.stateSyncStart <- s:
+	case <-d.quitCh:
+		return errCancelStateFetch
+	}
+	return s.Wait()
+}
+
+// syncStateFromBackup copies the state trie of the given root from a backup
+// database, each entry being verified against its hash.
+func (d *Downloader) syncStateFromBackup(root common.Hash, backup ethdb.Database) error {
+	sched := state.NewStateSync(root, d.stateDB)
+	for sched.Pending() > 0 {
+		missing := sched.Missing(backupStateBatch)
+		results := make([]trie.SyncResult, len(missing))
+		for i, hash := range missing {
+			blob, err := backup.Get(hash[:])
+			if err != nil {
+				return fmt.Errorf("state entry %x missing from backup", hash)
+			}
+			if crypto.Keccak256Hash(blob) != hash {
+				return fmt.Errorf("state entry %x corrupted in backup", hash)
+			}
+			results[i] = trie.SyncResult{Hash: hash, Data: blob}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			return fmt.Errorf("invalid state entry %x in backup: %v", results[index].Hash, err)
+		}
+		batch := d.stateDB.NewBatch()
+		if _, err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
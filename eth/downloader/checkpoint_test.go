@@ -0,0 +1,200 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+var testPrivateContract = common.Address{0xc0}
+
+// writeTestPrivateState commits a private state holding a contract to the given
+// database, returning its root.
+func writeTestPrivateState(t *testing.T, db ethdb.Database) common.Hash {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	statedb.SetCode(testPrivateContract, []byte{0x60, 0x0a})
+	statedb.SetState(testPrivateContract, common.Hash{}, common.Hash{0x0a})
+
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit private state: %v", err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to flush private state: %v", err)
+	}
+	return root
+}
+
+// assertCheckpointState checks that the checkpoint block was committed as the
+// head of the fast sync along with its private state.
+func assertCheckpointState(t *testing.T, tester *downloadTester, chain *testChain, number int, root common.Hash) {
+	if head := tester.CurrentBlock().Hash(); head != chain.headBlock().Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", head, chain.headBlock().Hash())
+	}
+	if receipts := len(tester.ownReceipts); receipts != number+1 {
+		t.Errorf("fast synced receipts mismatch: have %d, want %d", receipts, number+1)
+	}
+	header := chain.headerm[chain.chain[number]]
+	if have := core.GetPrivateStateRoot(tester.stateDb, header.Root); have != root {
+		t.Fatalf("private state root mismatch: have %x, want %x", have, root)
+	}
+	privateState, err := state.New(root, state.NewDatabase(tester.stateDb))
+	if err != nil {
+		t.Fatalf("failed to open private state: %v", err)
+	}
+	if code := privateState.GetCode(testPrivateContract); len(code) != 2 {
+		t.Errorf("private code mismatch: have %x", code)
+	}
+	if value := privateState.GetState(testPrivateContract, common.Hash{}); value != (common.Hash{0x0a}) {
+		t.Errorf("private storage mismatch: have %x", value)
+	}
+}
+
+// Tests that a fast sync pivots at the trusted checkpoint, copying its private
+// state from a backup database.
+func TestCheckpointSyncBackup(t *testing.T) {
+	t.Parallel()
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	number := chain.len() / 2
+	header := chain.headerm[chain.chain[number]]
+
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	backup, err := ethdb.NewLDBDatabase(dir, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := writeTestPrivateState(t, backup)
+	rawdb.WriteHeader(backup, header)
+	core.WritePrivateStateRoot(backup, header.Root, root)
+	backup.Close()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	if err := tester.downloader.SetCheckpoint(&Checkpoint{Number: uint64(number), Hash: header.Hash(), PrivateBackup: dir}); err != nil {
+		t.Fatalf("failed to set checkpoint: %v", err)
+	}
+	tester.newPeer("peer", 63, chain)
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertCheckpointState(t, tester, chain, number, root)
+}
+
+// Tests that the private state of the checkpoint is only fetched from the
+// designated party peer.
+func TestCheckpointSyncPrivatePeer(t *testing.T) {
+	t.Parallel()
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	number := chain.len() / 2
+	header := chain.headerm[chain.chain[number]]
+
+	key, _ := crypto.GenerateKey()
+	party := enode.NewV4(&key.PublicKey, net.ParseIP("127.0.0.1"), 30303, 30303, 0)
+	partyID := fmt.Sprintf("%x", party.ID().Bytes()[:8])
+
+	// A peer serving the private state but not designated isn't used
+	tester := newTester()
+	defer tester.terminate()
+
+	root := writeTestPrivateState(t, tester.peerDb)
+	checkpoint := &Checkpoint{Number: uint64(number), Hash: header.Hash(), PrivateRoot: root, PrivatePeer: party.String()}
+	if err := tester.downloader.SetCheckpoint(checkpoint); err != nil {
+		t.Fatalf("failed to set checkpoint: %v", err)
+	}
+	tester.newPeer("peer", 63, chain)
+	if err := tester.sync("peer", nil, FastSync); err != errPrivatePeerUnavailable {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, errPrivatePeerUnavailable)
+	}
+	// The designated one serves it
+	tester = newTester()
+	defer tester.terminate()
+
+	if err := tester.downloader.SetCheckpoint(checkpoint); err != nil {
+		t.Fatalf("failed to set checkpoint: %v", err)
+	}
+	tester.newPeer("peer", 63, chain)
+	tester.newPeer(partyID, 63, chain)
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertCheckpointState(t, tester, chain, number, root)
+}
+
+// Tests that chains not matching the trusted checkpoint, or not reaching it, are
+// rejected.
+func TestCheckpointSyncMismatch(t *testing.T) {
+	t.Parallel()
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	number := uint64(chain.len() / 2)
+
+	tester := newTester()
+	defer tester.terminate()
+
+	tester.downloader.SetCheckpoint(&Checkpoint{Number: number, Hash: common.Hash{0x01}})
+	tester.newPeer("peer", 63, chain)
+	if err := tester.sync("peer", nil, FastSync); err != errInvalidChain {
+		t.Errorf("mismatching chain error mismatch: have %v, want %v", err, errInvalidChain)
+	}
+	tester.downloader.SetCheckpoint(&Checkpoint{Number: uint64(chain.len()), Hash: common.Hash{0x01}})
+	if err := tester.sync("peer", nil, FastSync); err != errCheckpointUnreached {
+		t.Errorf("short chain error mismatch: have %v, want %v", err, errCheckpointUnreached)
+	}
+}
+
+// Tests that inconsistent checkpoints are refused.
+func TestSetCheckpoint(t *testing.T) {
+	tester := newTester()
+	defer tester.terminate()
+
+	invalid := []*Checkpoint{
+		{Hash: common.Hash{0x01}},
+		{Number: 1},
+		{Number: 1, Hash: common.Hash{0x01}, PrivatePeer: "enode://invalid"},
+		{Number: 1, Hash: common.Hash{0x01}, PrivateRoot: common.Hash{0x02}},
+		{Number: 1, Hash: common.Hash{0x01}, PrivateBackup: "/nonexistent/chaindata"},
+		{Number: 1, Hash: common.Hash{0x01}, PrivateRoot: common.Hash{0x02}, PrivatePeer: "enode://peer", PrivateBackup: "chaindata"},
+	}
+	for i, cp := range invalid {
+		if err := tester.downloader.SetCheckpoint(cp); err == nil {
+			t.Errorf("checkpoint %d: accepted %+v", i, cp)
+		}
+	}
+	if tester.downloader.checkpoint != nil {
+		t.Errorf("invalid checkpoint set")
+	}
+}
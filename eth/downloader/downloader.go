@@ -146,6 +146,10 @@ type Downloader struct {
 	quitCh   chan struct{} // Quit channel to signal termination
 	quitLock sync.RWMutex  // Lock to prevent double closes
 
+	// Quorum
+	checkpoint     *Checkpoint // Trusted block the fast sync pivots at, if any
+	checkpointPeer string      // Identifier of the peer serving the private state of the checkpoint
+
 	// Testing hooks
 	syncInitHook     func(uint64, uint64)  // Method to call upon initiating a new sync run
 	bodyFetchHook    func([]*types.Header) // Method to call upon starting a block body fetch
@@ -449,7 +453,16 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 	// Ensure our origin point is below any fast sync pivot point
 	pivot := uint64(0)
 	if d.mode == FastSync {
-		if height <= uint64(fsMinFullBlocks) {
+		if d.checkpoint != nil {
+			// Quorum: pivot at the trusted checkpoint, whatever the chain height
+			if height < d.checkpoint.Number {
+				return errCheckpointUnreached
+			}
+			pivot = d.checkpoint.Number
+			if pivot <= origin {
+				origin = pivot - 1
+			}
+		} else if height <= uint64(fsMinFullBlocks) {
 			origin = 0
 		} else {
 			pivot = height - uint64(fsMinFullBlocks)
@@ -1311,6 +1324,11 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 							unknown = append(unknown, header)
 						}
 					}
+					// Quorum: reject any chain not matching the trusted checkpoint
+					if err := d.verifyCheckpoint(chunk); err != nil {
+						log.Warn("Checkpoint verification failed", "err", err)
+						return errInvalidChain
+					}
 					// If we're importing pure headers, verify based on their recentness
 					frequency := fsHeaderCheckFrequency
 					if chunk[len(chunk)-1].Number.Uint64()+uint64(fsHeaderForceVerify) > pivot {
@@ -1427,7 +1445,9 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 	// Figure out the ideal pivot block. Note, that this goalpost may move if the
 	// sync takes long enough for the chain head to move significantly.
 	pivot := uint64(0)
-	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
+	if d.checkpoint != nil {
+		pivot = d.checkpoint.Number
+	} else if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
 		pivot = height - uint64(fsMinFullBlocks)
 	}
 	// To cater for moving pivot points, track the pivot block and subsequently
@@ -1461,7 +1481,7 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 		// Split around the pivot block and process the two sides via fast/full sync
 		if atomic.LoadInt32(&d.committed) == 0 {
 			latest = results[len(results)-1].Header
-			if height := latest.Number.Uint64(); height > pivot+2*uint64(fsMinFullBlocks) {
+			if height := latest.Number.Uint64(); d.checkpoint == nil && height > pivot+2*uint64(fsMinFullBlocks) {
 				log.Warn("Pivot became stale, moving", "old", pivot, "new", height-uint64(fsMinFullBlocks))
 				pivot = height - uint64(fsMinFullBlocks)
 			}
@@ -1490,6 +1510,11 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 				if stateSync.err != nil {
 					return stateSync.err
 				}
+				if d.checkpoint != nil {
+					if err := d.syncCheckpointState(P.Header); err != nil {
+						return err
+					}
+				}
 				if err := d.commitPivotBlock(P); err != nil {
 					return err
 				}
@@ -1725,19 +1750,29 @@ func (d *Downloader) syncWithPeerUntil(p *peerConnection, hash common.Hash, td *
 	d.syncStatsChainHeight = remoteHeight
 	d.syncStatsLock.Unlock()
 
+	// A node joining from genesis syncs the state of the trusted checkpoint
+	// instead of replaying the chain up to it
+	pivot := uint64(0)
+	if d.checkpoint != nil && localHeight == 0 && remoteHeight >= d.checkpoint.Number {
+		log.Info("Syncing the state of the trusted checkpoint", "number", d.checkpoint.Number, "hash", d.checkpoint.Hash)
+		d.mode, pivot = FastSync, d.checkpoint.Number
+		d.committed = 0
+	}
 	d.queue.Prepare(localHeight+1, d.mode)
 	if d.syncInitHook != nil {
 		d.syncInitHook(localHeight, remoteHeight)
 	}
 
-	pivot := uint64(0)
-
 	fetchers := []func() error{
 		func() error { return d.fetchBoundedHeaders(p, localHeight+1, remoteHeight) },
 		func() error { return d.fetchBodies(localHeight + 1) },
 		func() error { return d.fetchReceipts(localHeight + 1) }, // Receipts are only retrieved during fast sync
 		func() error { return d.processHeaders(localHeight+1, pivot, td) },
-		d.processFullSyncContent, //This must be added to clear the buffer of downloaded content as it's being filled
+	}
+	if d.mode == FastSync {
+		fetchers = append(fetchers, func() error { return d.processFastSyncContent(remoteHeader) })
+	} else {
+		fetchers = append(fetchers, d.processFullSyncContent) //This must be added to clear the buffer of downloaded content as it's being filled
 	}
 	return d.spawnSync(fetchers)
 }
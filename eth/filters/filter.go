@@ -222,8 +222,9 @@ func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, e
 			return logs, err
 		}
 
+		tenant, _ := ctx.Value("Tenant").(string)
 		bloomMatches := bloomFilter(header.Bloom, f.addresses, f.topics) ||
-			bloomFilter(core.GetPrivateBlockBloom(f.db, uint64(blockNumber)), f.addresses, f.topics)
+			bloomFilter(core.GetTenantPrivateBlockBloom(f.db, tenant, uint64(blockNumber)), f.addresses, f.topics)
 		if bloomMatches {
 			found, err := f.checkMatches(ctx, header)
 			if err != nil {
@@ -94,6 +94,8 @@ type EventSystem struct {
 	lightMode bool
 	lastHead  *types.Header
 
+	publicLogsOnly bool // Quorum: whether the private logs are withheld from the subscribers
+
 	// Subscriptions
 	txsSub        event.Subscription         // Subscription for new transaction event
 	logsSub       event.Subscription         // Subscription for new log event
@@ -110,6 +112,15 @@ type EventSystem struct {
 	chainCh   chan core.ChainEvent       // Channel to receive new chain event
 }
 
+// Quorum
+// tenantBackend is implemented by the backends which may host the private states
+// of several tenants. The logs of the default private state, holding the
+// payloads of all of them, are then withheld from the subscriptions, which can't
+// select the private state they are served from.
+type tenantBackend interface {
+	HasTenants() bool
+}
+
 // NewEventSystem creates a new manager that listens for event on the given mux,
 // parses and filters them. It uses the all map to retrieve filter changes. The
 // work loop holds its own index that is used to forward events to filters.
@@ -128,6 +139,9 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 		rmLogsCh:  make(chan core.RemovedLogsEvent, rmLogsChanSize),
 		chainCh:   make(chan core.ChainEvent, chainEvChanSize),
 	}
+	if b, ok := backend.(tenantBackend); ok {
+		m.publicLogsOnly = b.HasTenants()
+	}
 
 	// Subscribe events
 	m.txsSub = m.backend.SubscribeNewTxsEvent(m.txsCh)
@@ -316,6 +330,23 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 
 type filterIndex map[Type]map[rpc.ID]*subscription
 
+// visibleLogs returns the logs which may be broadcast to the subscribers. If the
+// private logs are withheld, only the logs of the public transactions are, the
+// logs of the transactions not yet indexed, such as the pending ones, being
+// withheld as well.
+func (es *EventSystem) visibleLogs(logs []*types.Log) []*types.Log {
+	if !es.publicLogsOnly {
+		return logs
+	}
+	var public []*types.Log
+	for _, log := range logs {
+		if tx, _, _, _ := rawdb.ReadTransaction(es.backend.ChainDb(), log.TxHash); tx != nil && !tx.IsPrivate() {
+			public = append(public, log)
+		}
+	}
+	return public
+}
+
 // broadcast event to filters that match criteria.
 func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 	if ev == nil {
@@ -324,6 +355,7 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 
 	switch e := ev.(type) {
 	case []*types.Log:
+		e = es.visibleLogs(e)
 		if len(e) > 0 {
 			for _, f := range filters[LogsSubscription] {
 				if matchedLogs := filterLogs(e, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
@@ -332,6 +364,7 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 			}
 		}
 	case core.RemovedLogsEvent:
+		e.Logs = es.visibleLogs(e.Logs)
 		for _, f := range filters[LogsSubscription] {
 			if matchedLogs := filterLogs(e.Logs, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
 				f.logs <- matchedLogs
@@ -339,9 +372,10 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 		}
 	case *event.TypeMuxEvent:
 		if muxe, ok := e.Data.(core.PendingLogsEvent); ok {
+			pendingLogs := es.visibleLogs(muxe.Logs)
 			for _, f := range filters[PendingLogsSubscription] {
 				if e.Time.After(f.created) {
-					if matchedLogs := filterLogs(muxe.Logs, nil, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
+					if matchedLogs := filterLogs(pendingLogs, nil, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
 						f.logs <- matchedLogs
 					}
 				}
@@ -497,6 +497,60 @@ func TestLogFilter(t *testing.T) {
 	}
 }
 
+// tenantTestBackend is a test backend hosting the private states of tenants.
+type tenantTestBackend struct {
+	*testBackend
+}
+
+func (b *tenantTestBackend) HasTenants() bool { return true }
+
+// TestPrivateLogsWithheld tests that the logs of private transactions aren't
+// broadcast to the subscribers of a node hosting tenants.
+func TestPrivateLogsWithheld(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = ethdb.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &tenantTestBackend{&testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}}
+		api        = NewPublicFilterAPI(backend, false)
+
+		publicTx  = types.NewTransaction(0, common.Address{}, new(big.Int), 0, new(big.Int), nil)
+		privateTx = types.NewTransaction(1, common.Address{}, new(big.Int), 0, new(big.Int), nil)
+		pendingTx = types.NewTransaction(2, common.Address{}, new(big.Int), 0, new(big.Int), nil)
+	)
+	privateTx.SetPrivate()
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, types.Transactions{publicTx, privateTx}, nil, nil)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteTxLookupEntries(db, block)
+
+	logs := []*types.Log{
+		{TxHash: publicTx.Hash(), BlockNumber: 1},
+		{TxHash: privateTx.Hash(), BlockNumber: 1},
+		{TxHash: pendingTx.Hash(), BlockNumber: 2},
+	}
+	matched := make(chan []*types.Log)
+	sub, err := api.events.SubscribeLogs(ethereum.FilterQuery{}, matched)
+	if err != nil {
+		t.Fatalf("failed to subscribe to the logs: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	logsFeed.Send(logs)
+	select {
+	case have := <-matched:
+		if len(have) != 1 || have[0] != logs[0] {
+			t.Errorf("broadcast logs mismatch: have %v, want %v", have, logs[:1])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("public log not broadcast")
+	}
+}
+
 // TestPendingLogsSubscription tests if a subscription receives the correct pending logs that are posted to the event feed.
 func TestPendingLogsSubscription(t *testing.T) {
 	t.Parallel()
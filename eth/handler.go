@@ -103,6 +103,8 @@ type ProtocolManager struct {
 
 	raftMode bool
 	engine   consensus.Engine
+
+	privateStatePeers map[enode.ID]bool // Party peers allowed to fetch the private state nodes
 }
 
 // NewProtocolManager returns a new Ethereum sub protocol manager. The Ethereum sub protocol manages peers capable
@@ -198,6 +200,22 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	return manager, nil
 }
 
+// SetPrivateStatePeers sets the party peers, given as enode URLs, which are allowed
+// to fetch the private state nodes not yet flushed to disk when syncing the
+// private state of a checkpoint.
+func (pm *ProtocolManager) SetPrivateStatePeers(urls []string) error {
+	peers := make(map[enode.ID]bool, len(urls))
+	for _, url := range urls {
+		node, err := enode.ParseV4(url)
+		if err != nil {
+			return fmt.Errorf("invalid private state peer %q: %v", url, err)
+		}
+		peers[node.ID()] = true
+	}
+	pm.privateStatePeers = peers
+	return nil
+}
+
 func (pm *ProtocolManager) removePeer(id string) {
 	// Short circuit if the peer was already removed
 	peer := pm.peers.Peer(id)
@@ -590,7 +608,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return errResp(ErrDecode, "msg %v: %v", msg, err)
 			}
 			// Retrieve the requested state entry, stopping if enough was found
-			if entry, err := pm.blockchain.TrieNode(hash); err == nil {
+			retrieve := pm.blockchain.TrieNode
+			if pm.privateStatePeers[p.ID()] {
+				retrieve = pm.blockchain.PrivateTrieNode
+			}
+			if entry, err := retrieve(hash); err == nil {
 				data = append(data, entry)
 				bytes += len(entry)
 			}
@@ -628,7 +650,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return errResp(ErrDecode, "msg %v: %v", msg, err)
 			}
 			// Retrieve the requested block's receipts, skipping if unknown to us
-			results := pm.blockchain.GetReceiptsByHash(hash)
+			results := pm.blockchain.GetPublicReceiptsByHash(hash)
 			if results == nil {
 				if header := pm.blockchain.GetHeaderByHash(hash); header == nil || header.ReceiptHash != types.EmptyRootHash {
 					continue
@@ -880,3 +902,11 @@ func (self *ProtocolManager) FindPeers(targets map[common.Address]bool) map[comm
 	}
 	return m
 }
+
+func (self *ProtocolManager) Peers() map[common.Address]consensus.Peer {
+	m := make(map[common.Address]consensus.Peer)
+	for _, p := range self.peers.Peers() {
+		m[crypto.PubkeyToAddress(*p.Node().Pubkey())] = p
+	}
+	return m
+}
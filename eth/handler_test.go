@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -82,7 +83,7 @@ func TestNodeInfo(t *testing.T) {
 	}{
 		{"ethash", nil, nil, false},
 		{"raft", nil, nil, true},
-		{"istanbul", nil, &params.IstanbulConfig{1, 1, big.NewInt(0)}, false},
+		{"istanbul", nil, &params.IstanbulConfig{Epoch: 1, ProposerPolicy: 1, Ceil2Nby3Block: big.NewInt(0)}, false},
 		{"clique", &params.CliqueConfig{1, 1}, nil, false},
 	}
 
@@ -432,6 +433,52 @@ func testGetNodeData(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that the private state nodes not yet flushed to disk are only served to
+// the party peers allowed to fetch them.
+func TestGetPrivateNodeData(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+
+	_, privateState, err := pm.blockchain.State()
+	if err != nil {
+		t.Fatalf("failed to open the private state: %v", err)
+	}
+	privateState.SetNonce(common.Address{1}, 1)
+	root, err := privateState.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit the private state: %v", err)
+	}
+	party, _ := newTestPeer("party", 63, pm, true)
+	defer party.close()
+	other, _ := newTestPeer("other", 63, pm, true)
+	defer other.close()
+
+	pm.privateStatePeers = map[enode.ID]bool{party.ID(): true}
+
+	for _, tt := range []struct {
+		peer *testPeer
+		want int
+	}{
+		{other, 0},
+		{party, 1},
+	} {
+		p2p.Send(tt.peer.app, GetNodeDataMsg, []common.Hash{root})
+		msg, err := tt.peer.app.ReadMsg()
+		if err != nil {
+			t.Fatalf("%s: failed to read node data response: %v", tt.peer.Name(), err)
+		}
+		var data [][]byte
+		if err := msg.Decode(&data); err != nil {
+			t.Fatalf("%s: failed to decode response node data: %v", tt.peer.Name(), err)
+		}
+		if len(data) != tt.want {
+			t.Fatalf("%s: node data count mismatch: have %d, want %d", tt.peer.Name(), len(data), tt.want)
+		}
+		if tt.want > 0 && crypto.Keccak256Hash(data[0]) != root {
+			t.Errorf("%s: data hash mismatch: have %x, want %x", tt.peer.Name(), crypto.Keccak256Hash(data[0]), root)
+		}
+	}
+}
+
 // Tests that the transaction receipts can be retrieved based on hashes.
 func TestGetReceipt63(t *testing.T) { testGetReceipt(t, 63) }
 
@@ -199,6 +199,11 @@ func (p *peer) Send(msgcode uint64, data interface{}) error {
 	return p2p.Send(p.rw, msgcode, data)
 }
 
+// Version returns the version of the protocol spoken with the peer.
+func (p *peer) Version() int {
+	return p.version
+}
+
 // SendTransactions sends transactions to the peer and includes the hashes
 // in its transaction hash set for future reference.
 func (p *peer) SendTransactions(txs types.Transactions) error {
@@ -117,7 +117,7 @@ func bigram_tracerJs() (*asset, error) {
 	return a, nil
 }
 
-var _call_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x59\xdf\x6f\x1b\x37\xf2\x7f\x96\xfe\x8a\x49\x1e\x6a\x09\x51\x24\x27\xe9\xb7\x5f\xc0\xae\x7a\xd0\x39\x4a\x6a\xc0\x8d\x03\x5b\x69\x10\x04\x79\xa0\x76\x67\x25\xd6\x5c\x72\x4b\x72\x2d\xef\xa5\xfe\xdf\x0f\x33\xe4\xae\x56\x3f\xec\xe8\x7a\xb8\x43\xef\x45\xd0\x2e\x67\x86\xc3\x99\xcf\xfc\xe2\x8e\x46\x70\x66\x8a\xca\xca\xc5\xd2\xc3\xcb\xe3\x17\xff\x0f\xb3\x25\xc2\xc2\x3c\x47\xbf\x44\x8b\x65\x0e\x93\xd2\x2f\x8d\x75\xdd\xd1\x08\x66\x4b\xe9\x20\x93\x0a\x41\x3a\x28\x84\xf5\x60\x32\xf0\x5b\xf4\x4a\xce\xad\xb0\xd5\xb0\x3b\x1a\x05\x9e\xbd\xcb\x24\x21\xb3\x88\xe0\x4c\xe6\x57\xc2\xe2\x09\x54\xa6\x84\x44\x68\xb0\x98\x4a\xe7\xad\x9c\x97\x1e\x41\x7a\x10\x3a\x1d\x19\x0b\xb9\x49\x65\x56\x91\x48\xe9\xa1\xd4\x29\x5a\xde\xda\xa3\xcd\x5d\xad\xc7\xdb\x77\x1f\xe0\x02\x9d\x43\x0b\x6f\x51\xa3\x15\x0a\xde\x97\x73\x25\x13\xb8\x90\x09\x6a\x87\x20\x1c\x14\xf4\xc6\x2d\x31\x85\x39\x8b\x23\xc6\x37\xa4\xca\x75\x54\x05\xde\x98\x52\xa7\xc2\x4b\xa3\x07\x80\x92\x34\x87\x5b\xb4\x4e\x1a\x0d\xaf\xea\xad\xa2\xc0\x01\x18\x4b\x42\x7a\xc2\xd3\x01\x2c\x98\x82\xf8\xfa\x20\x74\x05\x4a\xf8\x35\xeb\x01\x06\x59\x9f\x3b\x05\xa9\x79\x9b\xa5\x29\x10\xfc\x52\x78\x3a\xf5\x4a\x2a\x05\x73\x84\xd2\x61\x56\xaa\x01\x49\x9b\x97\x1e\x3e\x9e\xcf\x7e\xbe\xfc\x30\x83\xc9\xbb\x4f\xf0\x71\x72\x75\x35\x79\x37\xfb\x74\x0a\x2b\xe9\x97\xa6\xf4\x80\xb7\x18\x44\xc9\xbc\x50\x12\x53\x58\x09\x6b\x85\xf6\x15\x98\x8c\x24\xfc\x32\xbd\x3a\xfb\x79\xf2\x6e\x36\xf9\xfb\xf9\xc5\xf9\xec\x13\x18\x0b\x6f\xce\x67\xef\xa6\xd7\xd7\xf0\xe6\xf2\x0a\x26\xf0\x7e\x72\x35\x3b\x3f\xfb\x70\x31\xb9\x82\xf7\x1f\xae\xde\x5f\x5e\x4f\x87\x70\x8d\xa4\x15\x12\xff\xb7\x6d\x9e\xb1\xf7\x2c\x42\x8a\x5e\x48\xe5\x6a\x4b\x7c\x32\x25\xb8\xa5\x29\x55\x0a\x4b\x71\x8b\x60\x31\x41\x79\x8b\x29\x08\x48\x4c\x51\x1d\xec\x54\x92\x25\x94\xd1\x0b\x3e\xf3\x83\x80\x84\xf3\x0c\xb4\xf1\x03\x70\x88\xf0\xe3\xd2\xfb\xe2\x64\x34\x5a\xad\x56\xc3\x85\x2e\x87\xc6\x2e\x46\x2a\x88\x73\xa3\x9f\x86\x5d\x92\x99\x08\xa5\x66\x56\x24\x68\xc9\x39\x02\xb2\x92\xcc\xaf\xcc\x4a\x83\xb7\x42\x3b\x91\x90\xab\xe9\x7f\xc2\x60\x14\x1e\xf0\x8e\x9e\xbc\x23\xd0\x82\xc5\xc2\x58\xfa\xaf\x54\x8d\x33\xa9\x3d\x5a\x2d\x14\xcb\x76\x90\x8b\x14\x61\x5e\x81\x68\x0b\x1c\xb4\x0f\x43\x30\x0a\xee\x06\xa9\x33\x63\x73\x86\xe5\xb0\xfb\xb5\xdb\x89\x1a\x3a\x2f\x92\x1b\x52\x90\xe4\x27\xa5\xb5\xa8\x3d\x99\xb2\xb4\x4e\xde\x22\x93\x40\xa0\x89\xf6\x9c\xfe\xfa\x0b\xe0\x1d\x26\x65\x90\xd4\x69\x84\x9c\xc0\xe7\xaf\xf7\x5f\x06\x5d\x16\x9d\xa2\x4b\x50\xa7\x98\xf2\xf9\x6e\x1c\xac\x96\x6c\x51\x58\xe1\xd1\x2d\xc2\x6f\xa5\xf3\x2d\x9a\xcc\x9a\x1c\x84\x06\x53\x12\xe2\xdb\xd6\x91\xda\x1b\x16\x28\xe8\xbf\x46\xcb\x1a\x0d\xbb\x9d\x86\xf9\x04\x32\xa1\x1c\xc6\x7d\x9d\xc7\x82\x4e\x23\xf5\xad\xb9\x21\xc9\xc6\x12\x84\x6d\x05\xa6\x48\x4c\x1a\x83\x81\xce\xd1\x1c\x03\xdd\xb0\xdb\x21\xbe\x13\xc8\x4a\xcd\xdb\xf6\x94\x59\x0c\x20\x9d\xf7\xe1\x6b\xb7\x43\x62\xcf\x44\xe1\x4b\x8b\x6c\x4f\xb4\xd6\x58\x07\x32\xcf\x31\x95\xc2\xa3\xaa\xba\x9d\xce\xad\xb0\x61\x01\xc6\xa0\xcc\x62\xb8\x40\x3f\xa5\xc7\x5e\xff\xb4\xdb\xe9\xc8\x0c\x7a\x61\xf5\xc9\x78\xcc\xd9\x27\x93\x1a\xd3\x20\xbe\xe3\x97\xd2\x0d\x33\x51\x2a\xdf\xec\x4b\x4c\x1d\x8b\xbe\xb4\x9a\xfe\xde\x07\x2d\x3e\x22\x18\xad\x2a\x48\x28\xcb\x88\x39\x85\xa7\xab\x9c\xc7\x3c\x1e\xce\x0d\x20\x13\x8e\x4c\x28\x33\x58\x21\x14\x16\x9f\x27\x4b\x24\xdf\xe9\x04\xa3\x96\xae\x72\xec\xd4\x31\xd0\x6e\x43\x53\x0c\xbd\x79\x57\xe6\x73\xb4\xbd\x3e\x7c\x07\xc7\x77\xd9\x71\x1f\xc6\x63\xfe\x53\xeb\x1e\x79\xa2\xbe\x24\xc5\x14\xf1\xa0\xcc\x7f\xed\xad\xd4\x8b\x70\xd6\xa8\xeb\x79\x06\x02\x34\xae\x20\x31\x9a\x41\x4d\x5e\x99\xa3\xd4\x0b\x48\x2c\x0a\x8f\xe9\x00\x44\x9a\x82\x37\x01\x79\x0d\xce\x36\xb7\x84\xef\xbe\xe3\xbd\xc6\x70\x74\x76\x35\x9d\xcc\xa6\x47\x2d\x25\xa4\xbe\xcc\xb2\xa8\x07\xf3\x0e\x0b\xc4\x9b\xde\x8b\xfe\xf0\x56\xa8\x12\x2f\xb3\xa0\x51\xa4\x9d\xea\x14\xc6\x91\xe7\xd9\x36\xcf\xcb\x0d\x1e\x62\x1a\x8d\x60\xe2\x1c\xe6\x73\x85\xbb\xb1\x17\x83\x93\xe3\xd4\x79\x4a\x4e\x04\xb4\xc4\xe4\x85\x42\x02\x50\xbd\x6b\xb4\x34\x6b\xdc\xf1\x55\x81\x27\x00\x00\xa6\x18\xf0\x0b\x82\x3d\xbf\xf0\xe6\x67\xbc\x63\x77\xd4\xd6\x22\x00\x4d\xd2\xd4\xa2\x73\xbd\x7e\x3f\x90\x4b\x5d\x94\xfe\x64\x83\x3c\xc7\xdc\xd8\x6a\xe8\x28\xf7\xf4\xf8\x68\x83\x70\xd2\x9a\x67\x21\xdc\xb9\x26\x9e\x08\xca\xb7\xc2\xf5\xd6\x4b\x67\xc6\xf9\x93\x7a\x89\x1e\xea\x35\xb6\x05\xb1\x1d\x1d\xdf\x1d\xed\x5a\xeb\xb8\xbf\x76\xfa\x8b\x1f\xfa\xc4\x72\x7f\xda\x40\xb9\xc9\x08\xc3\xa2\x74\xcb\x1e\x23\x67\xbd\xba\x8e\xfa\x31\x78\x5b\xe2\x5e\xa4\x33\x7a\x76\x91\xe3\x50\x65\x94\x36\xbc\x2d\x13\x46\xd0\x42\x70\x52\xe1\xa0\x16\x94\x64\x5d\x39\x67\x9b\x7b\x63\x1e\x04\xd2\xf5\xf4\xe2\xcd\xeb\xe9\xf5\xec\xea\xc3\xd9\xac\x0d\x27\x85\x99\x27\xa5\x36\xcf\xa0\x50\x2f\xfc\x92\xf5\x27\x71\x9b\xab\x9f\x89\xe7\xf9\x8b\x2f\xe1\x0d\x8c\xf7\x44\x77\xe7\x71\x0e\xf8\xfc\x85\x65\xdf\xef\x9a\x6f\x93\x34\x18\xf3\x6b\x00\x91\x29\xee\xdb\x39\x62\x4f\xd8\xe5\xe8\x97\x26\xe5\x3c\x98\x88\x90\x4a\x6b\x2b\xa6\x46\xe3\xc1\xc1\xd7\xab\xa3\x6f\x72\x71\x71\x04\x7f\xfc\x01\xad\xe7\xb3\xcb\xd7\xd3\xf6\xbb\xd7\xd3\x8b\xe9\xdb\xc9\x6c\xba\x4d\x7b\x3d\x9b\xcc\xce\xcf\xf8\x6d\x3f\x5a\x65\x34\x82\xeb\x1b\x59\x70\x42\xe5\x34\x65\xf2\x82\x3b\xc3\x46\x5f\x37\x00\xbf\x34\xd4\x73\xd9\x58\x2f\x32\xa1\x93\x3a\x8f\xbb\xda\x69\xde\x90\xcb\x4c\x1d\x2b\xbb\xa9\xa0\x0d\xd4\x7e\xe3\x46\xe9\xde\x5b\x8c\x9b\xa6\x3d\x6f\x6a\xbd\xd6\x06\x0d\x1e\xe1\x5c\xc7\x49\xa6\x77\xf8\x21\xe1\x6f\x70\x0c\x27\xf0\x22\x66\x92\x47\x52\xd5\x4b\x78\x46\xe2\xff\x44\xc2\x7a\xb5\x87\xf3\xaf\x99\xb6\xbc\x61\xe2\x9a\xdc\x9b\xff\x7e\x3a\x33\xa5\xbf\xcc\xb2\x13\xd8\x36\xe2\xf7\x3b\x46\x6c\xe8\x2f\x50\xef\xd2\xff\xdf\x0e\xfd\x3a\xf5\x11\xaa\x4c\x01\x4f\x76\x20\x12\x12\xcf\x93\xad\x38\x88\xc6\xe5\x6e\x86\xa5\xc1\xf8\x81\x64\xfb\x72\x13\xc3\x0f\x65\x8b\x7f\x2b\xd9\xee\xed\xca\xa8\xf7\xda\xec\xbb\x06\x60\xd1\x5b\x89\xb7\x34\x59\x1d\x39\x16\x49\xfd\xa9\x59\x09\x9d\xe0\x10\x3e\x62\x90\xa8\x11\x39\xb9\xc4\x7e\x96\xda\x11\x6e\xf1\xa8\x27\x8d\x93\x09\x43\x4c\x70\xdb\x69\x11\x72\x51\xd1\x64\x92\x95\xfa\xa6\x82\x85\x70\x90\x56\x5a\xe4\x32\x71\x41\x1e\xf7\xb2\x16\x17\xc2\xb2\x58\x8b\xbf\x97\xe8\x68\xcc\x21\x20\x8b\xc4\x97\x42\xa9\x0a\x16\x92\x66\x15\xe2\xee\xbd\x7c\x75\x7c\x0c\xce\xcb\x02\x75\x3a\x80\x1f\x5e\x8d\x7e\xf8\x1e\x6c\xa9\xb0\x3f\xec\xb6\xd2\x78\x73\xd4\xe8\x0d\x5a\x88\xe8\x79\x8d\x85\x5f\xf6\xfa\xf0\xd3\x03\xf5\xe0\x81\xe4\xbe\x97\x16\x9e\xc3\x8b\x2f\x43\xd2\x6b\xbc\x81\xdb\xe0\x49\x40\xe5\x30\x4a\xa3\xf9\xee\xf2\xf5\x65\xef\x46\x58\xa1\xc4\x1c\xfb\x27\x3c\xef\xb1\xad\x56\x22\x36\xfc\xe4\x14\x28\x94\x90\x1a\x44\x92\x98\x52\x7b\x32\x7c\xdd\xbb\xab\x8a\xf2\xfb\x91\xaf\xe5\xf1\x68\x24\x92\x04\x9d\xab\xd3\x3d\x7b\x8d\xd4\x11\x39\x71\x83\xd4\x4e\xa6\xd8\xf2\x0a\x65\x07\xc3\xa9\x39\x52\xd0\xe4\x58\x0b\xcc\x8d\xa3\x4d\xe6\x08\x2b\x4b\x73\x86\x93\x3a\xe1\x41\x3b\x45\xb2\xb6\x03\xa3\x41\x80\x32\x3c\xdd\x73\x8c\x83\xb0\x0b\x37\x0c\xf9\x9e\xb6\xa5\x9c\xa3\xcd\x6a\xb8\x09\xe4\x36\x54\xb9\xa3\xdf\x6a\x07\x34\xe0\x9d\x74\x9e\x1b\x48\xd2\x52\x3a\x08\x48\x96\x7a\x31\x80\xc2\x14\x9c\xa7\x0f\xec\x25\xaf\xa6\xbf\x4e\xaf\x9a\xe2\x7f\xb8\x13\xeb\x16\xff\x69\x33\x01\x81\xa5\xf1\xc2\x63\xfa\x74\x4f\xcf\xbe\x07\x50\xe3\x07\x00\x45\xf2\xd7\xb5\xf1\x7d\xeb\x38\x4a\x38\xbf\x76\xcc\x02\xc3\xf8\xd2\x56\xc0\x95\xca\xbb\xad\xdc\xbd\x9d\x1c\x4c\x51\x57\x08\x52\x8a\xd3\x0e\x25\xf6\x3d\x9d\x75\x34\xb8\x6f\x03\x4f\x40\xa0\x69\x25\x00\x5e\xaf\x3b\x34\x11\x72\x3e\x6b\x68\x4a\x4f\x4e\xa7\x2a\xbd\x4e\x71\x0b\xe1\x3e\x38\xf6\x6d\x4c\x72\x73\xb9\x38\xd7\xbe\x57\x2f\x9e\x6b\x78\x0e\xf5\x03\xa5\x6e\x78\xbe\x11\x2b\x7b\x72\x60\x27\x45\x85\x1e\x61\x2d\xe2\x14\xb6\x5e\x91\xa0\x70\x68\x36\x8d\x45\xbf\x5b\x82\x8f\xa3\x34\x32\xcb\x13\x8b\x7e\x88\xbf\x97\x42\xb9\xde\x71\xd3\x12\x84\x13\x78\xc3\x45\x6c\xdc\x94\xb1\xba\xce\x11\xcf\x46\x93\x11\x05\x06\xb6\x68\x8d\x9a\x2d\x9d\x87\xda\x94\xe2\xa3\x12\xa2\x88\x98\x1c\x1a\x8f\x45\xf8\xed\xeb\x32\x3b\x6d\x02\x78\xda\x94\xfd\x4c\x48\x55\x5a\x7c\x7a\x0a\x7b\x92\x8b\x2b\x6d\x26\x12\xf6\xa5\x43\xe0\x11\xd4\x81\x33\x39\x2e\xcd\x2a\x28\xb0\x2f\x45\xed\x82\xa3\xc1\xc1\x56\x91\xe0\xbb\x14\xe1\xa0\x74\x62\x81\x2d\x70\x34\x06\xaf\x1d\xb5\x77\x2e\xfe\xd3\xd0\x79\xd6\x3c\x7e\x03\x45\x61\x97\x6f\x42\xe3\x31\x6c\xec\xf5\xf2\x4e\x2f\x53\x13\x71\x47\xd3\x7a\xa8\x55\x0d\x0d\x47\x83\x9c\x7f\xc5\xef\xff\x19\xc7\x07\xcf\xc7\xdf\x43\x03\x6d\x9b\x36\x9c\x71\x93\x38\x9c\x74\xdd\xc4\x7c\x1b\x05\xcd\xea\x43\x00\x78\xa8\x3f\x22\xa8\xea\xdf\x30\xf1\x6b\xb8\x72\x4b\x43\x4f\x85\xc5\x5b\x69\x4a\xaa\x56\xf8\xbf\x34\xff\x35\xfd\xdd\x7d\xb7\x73\x1f\xef\xbc\xd8\x7d\xed\x4b\xaf\xd5\x32\xde\xd9\x86\xd6\xa8\x55\x2b\x0c\x17\xd2\x78\x15\x96\x85\xdb\xd4\x0e\xf3\x3f\x72\xf9\x15\xe3\xdd\x9b\x82\x6a\x7f\x2c\x45\xca\xa2\x48\xab\xa6\xfa\x0d\x42\xd7\x01\x4b\xa1\xd3\x38\x79\x88\x34\x95\x24\x8f\xb1\x48\x1a\x8a\x85\x90\xba\xbb\xd7\x8c\xdf\x2c\xb9\xfb\x90\xb1\xd3\xc8\xb6\xab\x66\x9c\x18\x69\xbc\x63\x8d\xbb\x07\x54\xc7\xad\x58\xda\xbe\xc7\x8b\x57\x81\x46\xbb\x32\xe7\xb6\x17\xc4\xad\x90\x4a\xd0\xa8\xc5\xed\x94\x4e\x21\x51\x28\x74\xb8\xbd\xc7\xcc\x9b\x5b\xb4\xae\x7b\x00\xc8\xff\x0c\xc6\xb7\x92\x63\xfd\x18\xcd\x71\x78\xcc\x1e\x1a\xb1\xe1\xf8\x6f\x94\xf0\x3e\xc2\xab\x65\xde\x10\x59\xd2\xf3\x87\x1d\xd4\xbe\x7b\x58\x48\x71\x83\x44\x34\x3f\xc1\x71\xab\x09\xff\xab\x04\xd9\x2e\xc4\x2e\x9a\x66\x2c\x1e\xde\x1b\x33\x00\x85\x82\x47\xa2\xfa\xb3\x4b\xdd\x7c\x3e\x36\xa1\xd5\xd1\x1b\xda\xb7\x9d\xf0\xe5\x4b\xac\x25\xd6\xd7\x1d\xa1\x8f\x9f\x23\x6a\x90\x1e\xad\xa0\xe1\x87\xd0\x15\xbf\x14\x90\x96\x8e\xc5\xb1\x5f\x24\x05\x5d\x14\x1c\xaf\xed\xa9\x3e\x4b\xbd\x18\x76\x3b\xe1\x7d\x2b\xde\x13\x7f\xb7\x8e\xf7\x50\x0c\x99\x33\x5e\x00\x34\xf3\x7f\xe2\xef\xb8\x67\xe4\x19\x79\xeb\x12\x80\xd6\xe8\x55\x18\xa0\xb7\x46\x7e\x66\x8c\x63\xff\xf6\xcd\x22\xad\xf1\xbb\x0d\x80\x33\xe9\x42\xb8\x20\x66\x2b\x24\xfc\xdd\x6e\x44\xd4\x0c\x14\x0c\x27\xfb\x19\x68\x69\x0f\xd3\xd6\x35\x04\x11\xf3\xab\xb0\x1a\x0a\xfb\x49\x7b\x35\xbc\x8a\x07\x95\x79\xcb\x36\x32\x67\xdb\xdc\x9f\xee\x4f\x72\xc7\x35\x1e\xf7\x27\x33\xb2\x79\x03\xd8\x07\x58\xdb\x83\xc5\x2e\xc9\x63\xa9\x92\xa5\xd7\x99\xed\x01\x56\x96\xde\x6a\x3d\xfc\xdd\xe1\x22\x1b\xe2\xb6\x8a\x1b\x34\xfb\x84\xc4\x3c\x13\xe9\x82\x65\x6b\x01\x01\xd5\x41\x57\x46\xb4\xfc\x07\x46\x89\xed\xf8\xa9\x97\xc0\x62\xf8\xb0\xc0\x0d\x29\x85\x8f\x99\x73\xf1\x2f\x1d\xcd\x8c\xeb\xb8\x48\xd1\x49\x8b\x29\x64\x12\x55\x0a\x26\x45\xcb\x13\xe9\x6f\xce\xe8\xf0\x09\x09\xad\x24\x89\xe1\x53\x59\xf8\x6a\xcd\x1f\xf0\xb4\x4c\xd0\x57\x90\xa1\xe0\x6f\x41\xde\x40\x21\x9c\x83\x1c\x05\xcd\xa0\x59\xa9\x54\x05\xc6\xa6\x48\xc2\x9b\xa1\x8c\x42\xd2\x40\xe9\xd0\x3a\x58\x2d\x4d\x2c\x93\xdc\xa5\x15\xd4\x74\x4a\x3f\x88\xf7\x2e\xd2\x15\x4a\x54\x20\x3d\x95\xe4\x78\xa8\x76\x94\x36\x1f\x60\xf8\x2b\x8e\xa1\xaa\xbb\x1b\xa2\xf5\x5c\xb7\x19\xa3\xfc\x9a\x9e\x36\xa3\x33\xce\x35\x9b\x71\xb9\xbe\x91\xda\x0c\xc2\xba\x6c\x6c\x46\x5a\xbb\x08\x6d\x86\x13\xaf\xf0\xd3\x66\x20\xb5\xfa\x65\x5e\x60\x70\x34\x0c\xfc\xb4\x15\x5a\xac\x65\x8c\xad\xf0\xb9\xb1\x21\xe7\xa7\x41\x04\x0c\x79\xb1\x47\xc6\xb9\xc1\x8a\x32\x71\xb0\x51\xab\xac\x84\x17\x9f\x6f\xb0\xfa\xb2\xbf\x8a\x44\x38\xb6\xe8\x9a\xb2\x51\x43\x3a\xac\x3d\x12\xc8\x8d\x16\x72\x7c\x7c\x0a\xf2\xc7\x36\x43\x5d\xf9\x40\x3e\x7b\x56\xef\xd9\x5e\xff\x2c\xbf\xd4\xd1\xd9\x20\x7e\x6b\xbd\xbf\xa1\x51\x8c\x91\x40\x43\x41\xd1\xbd\xef\xfe\x33\x00\x00\xff\xff\xb5\x25\x8b\x4d\x94\x21\x00\x00")
+var _call_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x5a\x5f\x6f\x1b\xb7\xb2\x7f\x96\x3e\xc5\x24\x0f\xb5\x84\x28\x92\x9d\xf4\xf6\x02\x72\xd5\x0b\x5d\x47\x49\x0d\xb8\xb1\x61\x2b\x0d\x82\x20\x0f\xd4\xee\xac\xc4\x9a\x4b\x6e\x49\xae\xe4\x6d\xea\xef\x7e\x31\xfc\xb3\xda\x95\x64\xc7\x2d\xee\x39\xe8\x79\xd3\x92\x9c\xe1\x70\xf8\x9b\xbf\xd4\x68\x04\x67\xaa\xa8\x34\x5f\xae\x2c\xbc\x3a\x3e\xf9\x6f\x98\xaf\x10\x96\xea\x25\xda\x15\x6a\x2c\x73\x98\x96\x76\xa5\xb4\xe9\x8e\x46\x30\x5f\x71\x03\x19\x17\x08\xdc\x40\xc1\xb4\x05\x95\x81\xdd\x59\x2f\xf8\x42\x33\x5d\x0d\xbb\xa3\x91\xa7\x39\x38\x4d\x1c\x32\x8d\x08\x46\x65\x76\xc3\x34\x8e\xa1\x52\x25\x24\x4c\x82\xc6\x94\x1b\xab\xf9\xa2\xb4\x08\xdc\x02\x93\xe9\x48\x69\xc8\x55\xca\xb3\x8a\x58\x72\x0b\xa5\x4c\x51\xbb\xad\x2d\xea\xdc\x44\x39\xde\xbd\xff\x00\x17\x68\x0c\x6a\x78\x87\x12\x35\x13\x70\x55\x2e\x04\x4f\xe0\x82\x27\x28\x0d\x02\x33\x50\xd0\x88\x59\x61\x0a\x0b\xc7\x8e\x08\xdf\x92\x28\x37\x41\x14\x78\xab\x4a\x99\x32\xcb\x95\x1c\x00\x72\x92\x1c\xd6\xa8\x0d\x57\x12\x5e\xc7\xad\x02\xc3\x01\x28\x4d\x4c\x7a\xcc\xd2\x01\x34\xa8\x82\xe8\xfa\xc0\x64\x05\x82\xd9\x2d\xe9\x13\x14\xb2\x3d\x77\x0a\x5c\xba\xe3\xad\x54\x81\x60\x57\xcc\x92\x26\x36\x5c\x08\x58\x20\x94\x06\xb3\x52\x0c\x88\xdb\xa2\xb4\xf0\xf1\x7c\xfe\xf3\xe5\x87\x39\x4c\xdf\x7f\x82\x8f\xd3\xeb\xeb\xe9\xfb\xf9\xa7\x53\xd8\x70\xbb\x52\xa5\x05\x5c\xa3\x67\xc5\xf3\x42\x70\x4c\x61\xc3\xb4\x66\xd2\x56\xa0\x32\xe2\xf0\xcb\xec\xfa\xec\xe7\xe9\xfb\xf9\xf4\x7f\xcf\x2f\xce\xe7\x9f\x40\x69\x78\x7b\x3e\x7f\x3f\xbb\xb9\x81\xb7\x97\xd7\x30\x85\xab\xe9\xf5\xfc\xfc\xec\xc3\xc5\xf4\x1a\xae\x3e\x5c\x5f\x5d\xde\xcc\x86\x70\x83\x24\x15\x12\xfd\xb7\x75\x9e\xb9\xdb\xd3\x08\x29\x5a\xc6\x85\x89\x9a\xf8\xa4\x4a\x30\x2b\x55\x8a\x14\x56\x6c\x8d\xa0\x31\x41\xbe\xc6\x14\x18\x24\xaa\xa8\x9e\x7c\xa9\xc4\x8b\x09\x25\x97\xee\xcc\x0f\x02\x12\xce\x33\x90\xca\x0e\xc0\x20\xc2\x8f\x2b\x6b\x8b\xf1\x68\xb4\xd9\x6c\x86\x4b\x59\x0e\x95\x5e\x8e\x84\x67\x67\x46\x3f\x0d\xbb\xc4\x33\x61\x42\xcc\x35\x4b\x50\x13\x5a\x19\x64\x25\xa9\x5f\xa8\x8d\x04\xab\x99\x34\x2c\xa1\xab\xa6\xdf\xb4\xc4\x5d\x12\xde\xd1\x97\x35\x04\x5a\xd0\x58\x28\x4d\xbf\x85\x88\x38\xe3\xd2\xa2\x96\x4c\x38\xde\x06\x72\x96\x22\x2c\x2a\x60\x4d\x86\x83\xe6\x61\x08\x46\xfe\xba\x81\xcb\x4c\xe9\xdc\xc1\x72\xd8\xfd\xda\xed\x04\x09\x8d\x65\xc9\x2d\x09\x48\xfc\x93\x52\x6b\x94\x96\x54\x59\x6a\xc3\xd7\xe8\x96\x80\x5f\x13\xf4\x39\xfb\xf5\x17\xc0\x3b\x4c\x4a\xcf\xa9\x53\x33\x19\xc3\xe7\xaf\xf7\x5f\x06\x5d\xc7\x3a\x45\x93\xa0\x4c\x31\x25\xd1\x92\x5b\x03\x9b\x95\xd3\x28\x6c\xf0\x68\x8d\xf0\x5b\x69\x6c\x63\x4d\xa6\x55\x0e\x4c\x82\x2a\x09\xf1\x4d\xed\x70\x69\x95\x63\xc8\xe8\xb7\x44\xed\x24\x1a\x76\x3b\x35\xf1\x18\x32\x26\x0c\x86\x7d\x8d\xc5\x82\x4e\xc3\xe5\x5a\xdd\x62\xea\xc0\x83\x6b\xd4\x15\xa8\x22\x51\x69\x30\x06\x3a\x6b\x7d\x0c\x34\xc3\x6e\x87\xe8\xc6\x90\x95\xd2\x6d\xdb\x13\x6a\x39\x80\x74\xd1\x87\xaf\xdd\x0e\xed\x7e\xc6\x0a\x5b\x6a\x74\x66\x89\x5a\x2b\x6d\x80\xe7\x39\xa6\x9c\x59\x14\x55\xb7\xd3\x59\x33\xed\x27\x60\x02\x42\x2d\x87\x4b\xb4\x33\xfa\xec\xf5\x4f\xbb\x9d\x0e\xcf\xa0\xe7\x67\x9f\x4d\x26\xce\xfb\x64\x5c\x62\xea\xd9\x77\xec\x8a\x9b\x61\xc6\x4a\x61\xeb\x7d\x89\xa8\xa3\xd1\x96\x5a\xd2\xcf\x7b\x2f\xc5\x47\x04\x25\x45\x05\x09\x79\x19\xb6\x20\xf3\x34\x95\xb1\x98\x87\xc3\x99\x01\x64\xcc\x90\x0a\x79\x06\x1b\x84\x42\xe3\xcb\x64\x85\xc9\x2d\x28\x99\x60\x90\xd2\x54\x86\x54\x08\x13\xa0\xdd\x86\xaa\x18\x5a\xf5\xbe\xcc\x17\xa8\x7b\x7d\xf8\x0e\x8e\xef\xb2\xe3\x3e\x4c\x26\xee\x47\x94\x3d\xd0\x04\x79\xe9\xac\xaa\x08\x07\x75\xf4\x37\x56\x73\xb9\xec\xf5\x1b\xb2\x9e\x67\xc0\x40\xe2\x06\x12\x25\x09\x02\x96\x6e\x65\x81\x5c\x2e\x21\xd1\xc8\x2c\xa6\x03\x60\x69\x0a\x56\x39\x54\x6d\x71\xd6\xde\x12\xbe\xfb\xce\xed\x35\x81\xa3\xb3\xeb\xd9\x74\x3e\x3b\x6a\x08\xc1\xe5\x65\x96\x05\x39\x1c\x04\x87\x05\xe2\x6d\xef\xa4\x3f\x5c\x33\x51\xe2\x65\xe6\x25\x0a\x6b\x67\x32\x85\x49\xa0\x79\xb1\x4b\xf3\xaa\x45\x43\xda\x1f\x8d\x60\x6a\x0c\xe6\x0b\x81\xfb\xb6\x17\x8c\xd3\xd9\xa9\xb1\x4a\x7b\x2f\x95\xa8\xbc\x10\x48\x00\x8a\xbb\x06\x4d\x3b\x89\x3b\xb6\x2a\x70\x0c\x00\xa0\x8a\x81\x1b\x20\xd8\xbb\x01\xab\x7e\xc6\x3b\x77\x1d\x51\x5b\x04\xa0\x69\x9a\x6a\x34\xa6\xd7\xef\xfb\xe5\x5c\x16\xa5\x1d\xb7\x96\xe7\x98\x2b\x5d\x0d\x0d\xf9\x9e\x9e\x3b\xda\xc0\x9f\x34\xd2\x2c\x99\x39\x97\x44\x13\x40\xf9\x8e\x99\xde\x76\xea\x4c\x19\x3b\x8e\x53\xf4\x11\xe7\x9c\x2e\x88\xec\xe8\xf8\xee\x68\x5f\x5b\xc7\xfd\xed\xa5\x9f\xfc\xd0\x27\x76\xf7\xa7\x35\x94\x6b\x8f\x30\x2c\x4a\xb3\xea\xd1\x67\x7f\x3b\xbb\xb5\xfa\x09\x58\x5d\xe2\x41\xa4\x3b\xf4\xec\x23\xc7\xa0\xc8\xc8\x6d\x58\x5d\x26\x0e\x41\x4b\xe6\x9c\x8a\x33\x6a\x46\x4e\xd6\x94\x0b\xda\x0f\xac\x52\x0f\x02\xe9\x66\x76\xf1\xf6\xcd\xec\x66\x7e\xfd\xe1\x6c\xde\x84\x93\xc0\xcc\xc2\x04\x76\xce\x20\x50\x2e\xed\xca\xc9\x4f\xa6\xd0\x9e\xfd\x4c\x34\x2f\x4f\xbe\xf8\x11\x98\x1c\xb0\xee\xce\xe3\x14\xf0\xf9\x8b\xe3\x7d\xdf\xfd\xc6\x52\xaf\xcc\xaf\x1e\x44\xaa\xb8\x6f\xfa\x88\x03\x66\x97\xa3\x5d\x29\xca\x03\xd6\x2a\x71\x4e\x7f\xab\xc5\x54\x49\x7c\xb2\xf1\xf5\xa2\xf5\x4d\x2f\x2e\x8e\xe0\xcf\x3f\x6b\x6b\x9c\x5e\x5c\x9c\x5d\xbe\x99\x35\xc7\xde\xcc\x2e\x66\xef\xa6\xf3\xd9\xee\xda\x9b\xf9\x74\x7e\x7e\xe6\x46\xfb\x41\x2b\xa3\x11\xdc\xdc\xf2\xc2\x39\x54\xe7\xa6\x54\x5e\xb8\xcc\xb0\x96\xd7\x0c\xc0\xae\x14\xe5\x5c\x3a\xc4\x8b\x8c\xc9\x24\xfa\x71\x13\x2f\xcd\x2a\xba\x32\x15\x6d\x65\x07\xa8\x27\x6d\xa0\xf6\xeb\x6b\xe4\xe6\x4a\x23\xd9\x2b\x17\x98\xf6\xac\x8a\x72\x6d\x15\xea\x34\xea\x5c\x87\x72\x4e\xa6\xf7\xf4\x43\xc2\xff\xc0\x31\x8c\xe1\x24\x78\x92\x47\x5c\xd5\x2b\x78\x01\x2a\xcb\xfe\x86\xc3\x7a\x7d\x80\xf2\x9f\xe9\xb6\xac\x72\xd4\x71\xb9\x55\xff\x7e\x77\xa6\x4a\x7b\x99\x65\x63\xd8\x55\xe2\xf7\x7b\x4a\xac\xd7\x5f\xa0\xdc\x5f\xff\x5f\x7b\xeb\xb7\xae\x8f\x50\xa5\x0a\x78\xb6\x07\x11\x1f\xc1\x9e\xed\xd8\x41\x50\x2e\x99\xb6\xbf\x7c\x98\x3c\xe0\x6c\x5f\xb5\x31\xbc\xf5\x16\x94\x7d\x33\x7d\x1b\x92\x41\xca\x94\x9c\x35\x17\x9a\xaf\x99\x45\x8a\xa6\x96\xec\x5c\xa6\xf5\x90\x55\xbe\x7c\x49\x40\x23\x4b\x4d\x14\x3b\x5d\x0c\xc9\x1e\xdc\x9a\x69\x92\xa8\x52\xda\xa6\x49\x10\xff\x61\x64\xe1\x9d\xb6\x17\x02\x50\x18\x84\x5d\x0e\xbd\x36\x21\xed\x74\x49\x59\x4b\x93\xf2\xff\x39\x56\x1c\x4c\x2a\x9d\x42\x5a\x69\xe3\x00\x34\x5a\xcd\x71\x4d\x85\xe1\x91\x71\x2c\x29\xbd\x56\x1b\x26\x13\x1c\xc2\x47\xda\x60\x34\x02\x89\x94\xb7\xaa\x98\x8e\xd3\x09\x29\x54\xbb\x94\x3a\x14\x56\x24\x28\x55\x83\x14\x7e\x10\x72\x56\x51\x61\x95\x95\xf2\xb6\x82\x25\x33\x90\x56\x92\xe5\x3c\x21\x0d\x8f\x46\x8e\x0e\x34\x2e\x99\x76\x6c\x35\xfe\x5e\xa2\xa1\x2a\x8d\xd2\x07\x96\xd8\x92\x09\x51\xc1\x92\x53\xa9\x45\xd4\xbd\x57\xaf\x8f\x8f\xc1\x58\x5e\xa0\x4c\x07\xf0\xc3\xeb\xd1\x0f\xdf\x83\x2e\x05\xf6\x87\xc1\x41\xb7\xb5\x13\xf4\x4d\x17\x11\xc0\xff\x06\x0b\xbb\xea\xf5\xe1\xa7\x07\xc2\x59\xbc\xa1\xf6\xe4\xe7\x83\x6b\xe1\x25\x9c\x7c\x19\x92\x5c\x75\x6a\xeb\xb2\x88\x26\x06\x3c\x37\xaa\xd7\x2f\xdf\x5c\xf6\x6e\x99\x66\x82\x2d\xb0\x3f\x76\xed\x00\xa7\xab\x0d\x0b\xf5\x0a\x5d\x0a\x14\x82\x71\x09\xcc\x63\x8d\x14\x1f\x4b\x0f\x51\x41\xaa\xe4\x91\x8d\xfc\x5c\x65\xc7\x92\x04\x8d\x89\xd1\xca\xdd\x1a\x89\xc3\x72\xa2\x06\x2e\x0d\x27\xbe\x71\x27\x52\xaa\x51\x2e\xb2\x84\x15\x54\xf8\x46\x86\xb9\x32\x56\xb8\xdb\xda\x68\xaa\xf9\x0c\x97\x09\xc1\x01\x52\x24\x6d\x1b\x50\x12\x18\x08\xe5\x9a\x13\x2e\xe3\x02\xa6\x97\x66\xe8\xc3\x15\x6d\x4b\x99\x9e\x54\x9b\x61\x1b\xc8\x5b\xdc\x4d\x7c\x41\xb2\x93\xcd\x48\xc0\x3b\x6e\x2c\xc5\x5f\xa7\x0f\x6e\x08\x8c\xa5\x96\x5c\x2e\x07\x50\xa8\x82\x1c\xcb\x37\xa3\x71\x88\x35\xd7\xb3\x5f\x67\xd7\x75\xee\xf2\xf4\x4b\x8c\x15\xca\xf3\xba\x80\x03\x4d\xd5\x91\xc5\xf4\xf9\x81\x92\xe3\x00\xa0\x26\x0f\x00\x8a\xf8\x07\x71\x46\x23\xb8\x6a\x1c\x47\x30\x63\xb7\x17\xb3\x44\xeb\x46\x9b\x02\x98\x52\x58\xb3\x13\x7a\x76\x36\x29\x54\x11\x03\x1c\x09\x45\xec\x86\x14\x97\x0e\x14\x06\x41\xe1\xb5\x26\x09\x78\x0c\x7c\xf1\xd0\x70\x00\x6e\x3e\x26\x98\xcc\x87\x2c\xe7\x2b\x55\x69\xe9\xd2\x13\x95\xe2\xd6\x89\x2d\x99\xf9\x60\x30\xdd\xfa\xe8\x05\x5f\x9e\x4b\xdb\x8b\x93\xe7\x12\x5e\x42\xfc\xa0\xc8\x03\x2f\x5b\xb6\x72\xc0\x85\x77\x52\x14\x68\xb1\xa6\x3a\x97\xa7\xb0\x33\x44\x8c\xfc\xa1\x9d\x6a\x34\xda\xfd\x0c\xe2\x38\x70\x23\xb5\x3c\xd3\x68\x87\xf8\x7b\xc9\x84\xe9\x1d\xd7\x5e\xd8\xbb\x61\xab\x5c\x0c\x9e\xd4\x51\x38\x86\x69\xa2\x69\x0a\x17\x92\xa4\x70\xf0\xa0\x8d\x48\x96\x2e\xe8\x48\x67\x2a\xc5\x47\x39\x04\x16\x8d\x00\xe1\x98\x05\xf8\x1d\x4a\x92\x3b\xcd\x05\xf0\xbc\xce\x5a\x32\xc6\x45\xa9\xf1\xf9\x29\x1c\x70\x2e\xa6\xd4\x19\x4b\x9c\xe9\x1b\x04\x57\x41\x1b\x30\x2a\xc7\x95\xda\x78\x01\x0e\xb9\xa8\x7d\x70\xd4\x85\xc6\x4e\x90\x20\x8c\x90\xc5\x97\x86\x2d\xb1\x01\x8e\x5a\xe1\xf1\xa2\xe0\xd9\xc3\x67\xfa\xeb\xd0\x79\x51\x7f\x7e\x03\x45\xdd\xce\x93\xa0\xf1\x18\x36\x0e\xde\xf2\x5e\x2a\x16\x17\xb9\xfa\xb2\xf1\x11\x45\xf5\xf9\x52\x8d\x9c\xbf\x72\xef\xff\x9a\x8b\xf7\x37\xdf\xb9\xff\x4b\x86\xb6\xbb\xd6\x67\x8d\xed\xc5\xfe\xa4\xdb\x24\xe6\xdb\x28\xa8\x67\x1f\x02\xc0\x23\xe9\xdd\xb9\xfc\x0d\x13\xbb\x85\x6b\x23\xc7\xc3\x35\x57\x25\x45\x2b\xfc\x4f\x2a\x5f\xeb\xfc\xee\xbe\xdb\xb9\x0f\x2d\x3b\x67\xb7\xcd\x9e\xdd\x66\x15\x5a\xce\x3e\x35\xda\x76\x1b\x29\x24\x53\x97\xd0\x35\xbb\x1c\x42\xa8\x75\xe7\xe8\x1f\xe9\xdd\x05\x7b\xb7\xaa\xc8\x55\x1d\x8a\x84\x46\x96\x56\x75\xf4\x1b\xf8\xac\x03\x56\x4c\xa6\xa1\x70\x62\x69\xca\x89\x9f\x73\x42\x24\x21\x5b\x32\x2e\x43\x54\xdc\x39\xe9\x41\x9d\x37\x43\xee\x21\x64\xec\x25\xb2\xcd\xa8\x19\x0a\x5e\xaa\x4e\x9d\xc4\xdd\x27\x44\xc7\x1d\x5b\xda\x6d\x43\x86\x4e\xa6\x92\xa6\xcc\x5d\xda\x0b\x6c\xcd\xb8\x60\x54\x29\x92\xaf\x21\xff\x96\x08\x64\xd2\xa5\x4e\x74\x79\x8a\x9e\x2d\xc2\x89\x1f\x05\xf9\xdf\xc1\xf8\x8e\x73\x8c\x9f\x41\x1d\x4f\xb7\xd9\xa7\x5a\xac\x3f\xfe\x5b\xc1\xac\x0d\xf0\x6a\xa8\xd7\x5b\x16\xb7\xee\x5d\x0a\xa5\xed\x3e\xcd\xa4\x08\x0a\x6e\xcd\x4f\x70\x1c\x54\xf1\x4f\x32\xb2\x7d\x88\x5d\xd4\xc9\x58\x38\xbc\x55\x6a\x00\x02\x29\xcb\xe6\x36\xbe\x1a\xc5\xe4\xb3\xbd\x55\x9b\x79\xb4\x5e\x9f\xbe\xed\x99\x2f\xe9\x94\x58\x85\x6e\x8d\x7f\xa1\x59\x20\x4a\xe0\x16\x35\xb5\x7f\x81\xd0\x15\x1e\x3a\xc8\x10\x8c\x73\x06\x44\x93\x71\x0a\x00\x81\x71\x78\x75\xa0\x3c\x8d\xcb\xe5\xb0\xdb\xf1\xe3\x0d\x7b\x4f\xec\xdd\xd6\xde\xe9\xd6\x02\x65\xe8\x5f\xd4\xed\x8b\xc4\xde\xb9\x9c\x71\xd0\xdd\xef\x61\xd0\x1c\x95\x78\xbe\xcd\xb0\xd3\xb1\xa0\xc9\xd8\xb5\xd8\x6d\x8c\xd2\x9c\x1b\x6b\x01\xdc\x71\x59\x32\xe3\xd9\xec\x98\x84\xbd\xdb\xb7\x88\x48\x40\xc6\x30\x3e\x4c\x40\x53\x07\x88\x76\xba\x28\x24\x8f\x1b\xf2\xe2\xfa\xc0\x3e\x6e\xce\xfa\xa1\x70\x50\x9e\x37\x74\xc3\x73\xa4\xd1\xfb\x88\x6c\x1a\x0c\x65\x7f\xc0\xa9\xd7\xec\x81\x5e\x40\xac\x16\x76\xc0\x79\x1c\x21\x7c\xd8\xff\x39\x66\x11\xe3\x0f\x90\x3e\xce\xfd\x31\xef\xea\xb8\x47\x67\xf8\x00\xe9\x69\xb7\x9d\xad\xd8\xbb\xa7\xb3\xac\x17\x37\x45\x6c\xad\x39\xc4\x24\xb8\xa6\xb0\xce\x5f\x46\x64\x50\x03\xd7\x49\xeb\xcc\x80\xff\x81\x81\x67\xed\xc1\xa9\xa4\x4e\x31\xd1\x55\x41\x56\x54\xb0\x4a\x28\x96\x92\x01\xba\xb7\xa1\x5b\x49\x0f\x8c\x54\xa3\x36\x7b\x41\xad\x27\x35\x4b\x6d\xf4\x41\x68\x75\x28\x5b\x2f\x0e\x7d\x24\x0b\x39\xbb\x45\xf3\x44\x10\x5c\x85\xfd\x63\xee\xd8\x58\x1f\xa6\xea\x57\x21\xef\x8d\x82\x79\x36\x5c\x48\x3c\x28\x3d\x3b\xba\xa7\x21\x97\x93\x93\x07\x51\x0b\x97\xff\x94\x86\xca\xe6\xad\x6b\x48\xd1\x70\x4d\x8f\x7b\x1c\x45\x0a\x8a\xde\xf2\xe9\x0c\xbf\x19\x7a\x78\xa1\x47\x40\xd4\x9c\x09\xfe\x87\xeb\x23\x0f\xfd\xff\x0e\xdc\x13\xac\xe4\x09\xda\x0a\x32\x64\xee\x35\x8f\xda\x61\xcc\x18\xc8\x91\x51\x19\x4e\x0f\xb4\x15\x28\x9d\x22\x31\xaf\xeb\x52\xf2\x4a\x8a\x1e\xcd\x35\xbd\x62\xaa\x90\x29\xb8\x0a\xa5\xa0\xbc\x9b\xdb\x41\x68\x3d\x71\x53\x08\x56\x01\xb7\x94\x95\x84\x43\x35\x1d\x55\xfd\x84\x46\x5e\xca\x28\x4d\xf7\xb7\xe7\xa5\x62\x69\xdb\x76\x53\x74\x37\xce\x43\xb5\x1d\x54\x28\xed\xda\xae\x69\xdb\x53\x6c\xfb\xa1\x18\x39\xdb\xce\x26\x8e\xd2\x57\xdb\xa3\xb8\x19\xe7\x4c\xda\xbe\x24\x86\xd5\x38\xe1\x8c\xa0\x26\x70\x5f\x3b\xde\x85\x08\xa2\x7b\x71\x49\x8a\xa9\x97\xbb\x2f\x02\x42\xa7\x13\x60\x13\x77\x08\x9f\x8e\x55\x6c\x22\x8e\xa1\xd5\x53\x1c\x04\x64\xd1\xed\xf7\x48\xa9\xb7\x58\x51\x10\xf3\xba\x0d\x78\x25\x14\xfb\x81\xcf\xb7\x58\x7d\x39\x1c\x80\x83\x59\x36\xd6\xd5\x11\x37\x9a\xb6\x9f\x7b\xc4\xa1\xd5\x52\xf0\xc9\xf1\x29\xf0\x1f\x9b\x04\x31\x69\x00\xfe\xe2\x45\xdc\xb3\x39\xff\x99\x7f\xd9\xb3\xfb\x9d\xf9\x6d\x69\xd0\x30\x26\xbf\xe6\xb4\xdb\xb9\xef\xde\x77\xff\x6f\x00\xd7\x92\xdf\x8d\x8e\x23\x00\x00")
 
 func call_tracerJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -133,7 +133,7 @@ func call_tracerJs() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "call_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf5, 0xb3, 0xb6, 0xe8, 0x19, 0xc3, 0xa, 0xce, 0xfd, 0x50, 0x84, 0xf7, 0x8a, 0xc5, 0x99, 0x10, 0x58, 0xc4, 0x69, 0xfb, 0x8, 0xad, 0x67, 0xea, 0x12, 0x38, 0xcb, 0xd, 0x2a, 0x94, 0xa1, 0x70}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x70, 0x9b, 0x7a, 0x8d, 0xeb, 0xd4, 0x72, 0x63, 0x53, 0xcb, 0xa, 0xc5, 0xc6, 0x87, 0xd4, 0x6c, 0x94, 0xd7, 0x45, 0x48, 0xf0, 0x17, 0x1a, 0x4c, 0xd2, 0xbc, 0x7d, 0x9d, 0xde, 0x13, 0xc0, 0xc9}}
 	return a, nil
 }
 
@@ -197,7 +197,7 @@ func opcount_tracerJs() (*asset, error) {
 	return a, nil
 }
 
-var _prestate_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x57\xdd\x6f\x1b\xb9\x11\x7f\xde\xfd\x2b\xa6\x7e\x91\x84\x53\x56\xce\x15\xb8\x02\x72\x5d\x60\xa3\x28\x89\x00\x9d\x6d\x48\x4a\x5d\xf7\x70\x0f\x5c\x72\x76\xc5\x13\x45\x2e\x48\xae\x3e\x10\xf8\x7f\x2f\x86\xfb\x21\xcb\x67\x27\x6e\xeb\x27\x2f\x39\xfc\xcd\xf7\x6f\x46\xa3\x11\x4c\x4c\x79\xb4\xb2\x58\x7b\xf8\xf9\xf2\xfd\xdf\x60\xb5\x46\x28\xcc\x3b\xf4\x6b\xb4\x58\x6d\x21\xad\xfc\xda\x58\x17\x8f\x46\xb0\x5a\x4b\x07\xb9\x54\x08\xd2\x41\xc9\xac\x07\x93\x83\x7f\x26\xaf\x64\x66\x99\x3d\x26\xf1\x68\x54\xbf\x79\xf1\x9a\x10\x72\x8b\x08\xce\xe4\x7e\xcf\x2c\x8e\xe1\x68\x2a\xe0\x4c\x83\x45\x21\x9d\xb7\x32\xab\x3c\x82\xf4\xc0\xb4\x18\x19\x0b\x5b\x23\x64\x7e\x24\x48\xe9\xa1\xd2\x02\x6d\x50\xed\xd1\x6e\x5d\x6b\xc7\xe7\x9b\xaf\x30\x47\xe7\xd0\xc2\x67\xd4\x68\x99\x82\xbb\x2a\x53\x92\xc3\x5c\x72\xd4\x0e\x81\x39\x28\xe9\xc4\xad\x51\x40\x16\xe0\xe8\xe1\x27\x32\x65\xd9\x98\x02\x9f\x4c\xa5\x05\xf3\xd2\xe8\x21\xa0\x24\xcb\x61\x87\xd6\x49\xa3\xe1\xaf\xad\xaa\x06\x70\x08\xc6\x12\x48\x9f\x79\x72\xc0\x82\x29\xe9\xdd\x00\x98\x3e\x82\x62\xfe\xf4\xf4\x0d\x01\x39\xf9\x2d\x40\xea\xa0\x66\x6d\x4a\x04\xbf\x66\x9e\xbc\xde\x4b\xa5\x20\x43\xa8\x1c\xe6\x95\x1a\x12\x5a\x56\x79\xb8\x9f\xad\xbe\xdc\x7e\x5d\x41\x7a\xf3\x00\xf7\xe9\x62\x91\xde\xac\x1e\xae\x60\x2f\xfd\xda\x54\x1e\x70\x87\x35\x94\xdc\x96\x4a\xa2\x80\x3d\xb3\x96\x69\x7f\x04\x93\x13\xc2\xaf\xd3\xc5\xe4\x4b\x7a\xb3\x4a\x3f\xcc\xe6\xb3\xd5\x03\x18\x0b\x9f\x66\xab\x9b\xe9\x72\x09\x9f\x6e\x17\x90\xc2\x5d\xba\x58\xcd\x26\x5f\xe7\xe9\x02\xee\xbe\x2e\xee\x6e\x97\xd3\x04\x96\x48\x56\x21\xbd\xff\x71\xcc\xf3\x90\x3d\x8b\x20\xd0\x33\xa9\x5c\x1b\x89\x07\x53\x81\x5b\x9b\x4a\x09\x58\xb3\x1d\x82\x45\x8e\x72\x87\x02\x18\x70\x53\x1e\xdf\x9c\x54\xc2\x62\xca\xe8\x22\xf8\xfc\x6a\x41\xc2\x2c\x07\x6d\xfc\x10\x1c\x22\xfc\x7d\xed\x7d\x39\x1e\x8d\xf6\xfb\x7d\x52\xe8\x2a\x31\xb6\x18\xa9\x1a\xce\x8d\xfe\x91\xc4\x84\x59\x5a\x74\x9e\x79\x5c\x59\xc6\xd1\x82\xa9\x7c\x59\x79\x07\xae\xca\x73\xc9\x25\x6a\x0f\x52\xe7\xc6\x6e\x43\xa5\x80\x37\xc0\x2d\x32\x8f\xc0\x40\x19\xce\x14\xe0\x01\x79\x15\xee\xea\x48\x87\x72\xb5\x4c\x3b\xc6\xc3\x69\x6e\xcd\x96\x7c\xad\x9c\xa7\x7f\x9c\xc3\x6d\xa6\x50\x40\x81\x1a\x9d\x74\x90\x29\xc3\x37\x49\xfc\x2d\x8e\x9e\x18\x43\x75\x12\x3c\x6c\x84\x42\x6d\xec\xb1\x67\x11\xb2\x4a\x2a\x21\x75\x91\xc4\x51\x2b\x3d\x06\x5d\x29\x35\x8c\x03\x84\x32\x66\x53\x95\x29\xe7\xa6\x0a\xb6\xff\x81\xdc\xd7\x60\xae\x44\x2e\x73\x2a\x0e\xd6\xdd\x7a\x13\xae\x3a\xbd\x26\x23\xf9\x24\x8e\xce\x60\xc6\x90\x57\x3a\xb8\xd3\x67\x42\xd8\x21\x88\x6c\xf0\x2d\x8e\xa2\x1d\xb3\x84\x05\xd7\xe0\xcd\x17\x3c\x84\xcb\xc1\x55\x1c\x45\x32\x87\xbe\x5f\x4b\x97\xb4\xc0\xbf\x31\xce\x7f\x87\xeb\xeb\xeb\xd0\xd4\xb9\xd4\x28\x06\x40\x10\xd1\x4b\x62\xf5\x4d\x94\x31\xc5\x34\xc7\x31\xf4\x2e\x0f\x3d\xf8\x09\x44\x96\x14\xe8\x3f\xd4\xa7\xb5\xb2\xc4\x9b\xa5\xb7\x52\x17\xfd\xf7\xbf\x0c\x86\xe1\x95\x36\xe1\x0d\x34\xe2\x37\xa6\x13\xae\xef\xb9\x11\xe1\xba\xb1\xb9\x96\x9a\x18\xd1\x08\x35\x52\xce\x1b\xcb\x0a\x1c\xc3\xb7\x47\xfa\x7e\x24\xaf\x1e\xe3\xe8\xf1\x2c\xca\xcb\x5a\xe8\x95\x28\x37\x10\x80\xda\xdb\xae\xce\x0b\x49\x9d\xfa\x34\x01\x01\xef\x7b\x49\x58\xb6\xa6\x3c\x4b\xc2\x06\x8f\x3f\xce\x04\x5d\x48\x71\xe8\x2e\x36\x78\x1c\x5c\xc5\xaf\xa6\x28\x69\x8c\xfe\x4d\x8a\xc3\xcb\xf9\x22\xc0\x1d\x53\x1d\x60\x1d\xbf\x25\x21\x9c\xec\x1a\x04\xdd\x41\x07\xc9\xfe\xe5\x1a\x2e\x2e\x0f\x97\xff\xe7\xdf\x45\x63\xc1\x0b\x25\xf3\xcc\xec\x37\x98\xf6\x78\x9e\x4f\x8b\xae\x52\x9e\xda\x4e\xea\x9d\xd9\x10\x81\xae\x29\x4f\x4a\x85\xd4\x98\x92\xaa\xc6\xd5\x0c\x96\x21\x6a\x90\x1e\x2d\x23\x0a\x37\x3b\xb4\x34\xbd\xc0\xa2\xaf\xac\x76\x5d\x3a\x73\xa9\x99\x6a\x81\x9b\xec\x7b\xcb\x78\xdd\xbb\xf5\xf9\x93\x9c\x72\x7f\x08\xd9\x0c\x3e\x8e\x46\x90\x7a\x20\x3f\xa1\x34\x52\xfb\x21\xec\x11\x34\xa2\x20\x02\x12\x28\x2a\xee\x03\x5e\x6f\xc7\x54\x85\xbd\x9a\x64\x88\xaa\xc3\x53\x53\xd1\x44\x7a\x42\x42\xc3\x60\xe0\xd6\xec\xc2\xa8\xcd\x18\xdf\x40\xd3\xf8\xc6\xca\x42\xea\xb8\x89\xe9\x59\xd3\x93\x45\x09\x01\x07\xb3\x42\xcd\x50\xee\xe9\xe4\x43\xc8\x7f\x26\x8b\x99\xf6\xcf\x8a\xa8\x8e\x7c\xfb\x74\xf0\x7b\xd2\x34\x71\xe2\x88\x78\xfb\x3f\x0f\x86\xf0\xfe\x97\xae\x32\xbd\x21\x28\xf8\x31\x98\x37\xaf\x43\xc5\xcf\x2b\xe2\xe5\x67\x41\x0d\x31\xc9\x4f\x41\x6b\xe2\xaa\x8c\xd2\x51\xfb\x19\xe2\x78\xce\x26\x57\xdf\xc1\x3d\xf7\xad\xc5\x6d\x42\x93\x30\x21\x5e\x07\xad\x53\xf4\x11\xb9\xc5\x2d\x4d\x17\xca\x02\x67\x4a\xa1\xed\x39\x08\xdc\x35\x6c\xca\x29\xe4\x0b\xb7\xa5\x3f\xb6\x33\xc7\x33\x5b\xa0\x77\x3f\x36\x2c\xe0\xbc\x7b\xd7\x52\x71\x08\xc5\xb1\x44\xb8\xbe\x86\xde\x64\x31\x4d\x57\xd3\x5e\xd3\x4c\xa3\x11\xdc\x63\xd8\xc8\x32\x25\x33\xa1\x8e\x20\x50\xa1\xc7\xda\x2e\xa3\x43\x88\x3a\x6a\x1a\xd2\x6a\x45\x4b\x0f\x1e\xa4\xf3\x52\x17\x50\x33\xd6\x9e\xe6\x7b\x03\x17\x7a\x84\xb3\xca\x51\xb5\x3e\x1b\x86\xde\xd0\x66\x63\x91\xf8\x8d\xe6\x50\x68\x37\xa6\x64\xb7\x09\xe5\xd2\x3a\x0f\xa5\x62\x1c\x13\xc2\xeb\x8c\x79\x3d\xbf\x0d\x33\x93\xea\x45\x68\xc1\x00\x74\x1a\xb4\x4c\xd1\xa0\x26\xf5\x0e\xfa\x2d\xc6\x20\x8e\x22\xdb\x4a\x3f\xc1\xbe\x3a\x51\x82\xf3\x58\x3e\x25\x04\x5a\x70\x70\x87\x44\xe5\x81\x0d\xea\xa1\x4c\xba\xfe\xf9\x6b\xb3\x05\xa0\x4b\xe2\x88\xde\x3d\xe9\x6b\x65\x8a\xf3\xbe\x16\x75\x58\x78\x65\x2d\xe5\xbf\x1b\x05\x39\xf5\xf8\x1f\x95\xf3\x14\x53\x4b\xe1\x69\xd8\xe2\x25\xb2\x0e\xd4\x4c\x53\x7f\xf0\xe7\x21\x4a\xf3\x33\xcc\x2b\x52\xd7\x4c\xcb\x7a\xab\x2c\x8d\x47\xed\x25\x53\xea\x48\x79\xd8\x5b\x5a\xa7\x68\x81\x1a\x82\x93\x24\x15\x18\x27\x88\x4a\xcd\x55\x25\xea\x32\x08\x75\xdc\xe0\xb9\x60\xf3\xf9\x1e\xb6\x45\xe7\x58\x81\x09\x55\x52\x2e\x0f\xcd\x26\xab\xa1\x57\x93\x5c\x7f\xd0\x4b\x3a\x23\xcf\x29\x46\x99\x22\x69\x8b\x8c\xb8\x3a\x15\xc2\xa2\x73\xfd\x41\xc3\x39\x5d\x66\xef\xd7\xa8\x29\xf8\xa0\x71\x0f\xdd\x8a\xc4\x38\xa7\x95\x51\x0c\x81\x09\x41\xd4\xf6\x6c\x9d\x89\xa3\xc8\xed\xa5\xe7\x6b\x08\x9a\x4c\x79\xea\xc5\x41\x53\xff\x9c\x39\x84\x8b\xe9\xbf\x56\x93\xdb\x8f\xd3\xc9\xed\xdd\xc3\xc5\x18\xce\xce\x96\xb3\x7f\x4f\xbb\xb3\x0f\xe9\x3c\xbd\x99\x4c\x2f\xc6\xa7\x39\x74\xee\x90\x37\xad\x0b\xa4\xd0\x79\xc6\x37\x49\x89\xb8\xe9\x5f\x9e\xf3\xc0\xc9\xc1\x28\xca\x2c\xb2\xcd\xd5\xc9\x98\xba\x41\x1b\x1d\x2d\xe5\xc2\x35\xbc\x1a\xac\xab\xd7\xad\x99\x34\xf2\xfd\x96\xc8\x4f\x2b\x51\xa0\x8a\xef\xda\x91\xce\xe7\x9d\xe7\xf4\x41\xe1\xe8\x0e\x3e\x4e\xe7\xd3\xcf\xe9\x6a\x7a\x26\xb5\x5c\xa5\xab\xd9\xa4\x3e\xfa\xaf\x43\xf4\xfe\xcd\x21\xea\x2d\x97\xab\xdb\xc5\xb4\x37\x6e\xbe\xe6\xb7\xe9\xc7\xde\x9f\x14\x36\x7b\xd3\xf7\x8a\xcc\x9b\x7b\x63\xc5\xff\x92\xab\x27\xbb\x43\xce\x5e\x5a\x1d\x02\x09\x71\x5f\x3d\xfb\x89\x00\x4c\xb7\xfc\x91\xd7\x3f\x93\xa2\xf0\xfe\x45\xc6\x78\x8c\x1f\xe3\xff\x04\x00\x00\xff\xff\xb5\x44\x89\xaf\xbc\x0f\x00\x00")
+var _prestate_tracerJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x57\x6d\x6f\x1a\x47\x10\xfe\x6c\x7e\xc5\x34\x5f\x00\x85\x1c\x4e\x2a\xb5\x92\x5d\x57\x22\x84\x24\x48\xc4\xb6\x00\xd7\x75\xa3\x7c\xb8\x97\x3d\xd8\xfa\xb8\x3d\xed\xee\x81\x51\xe4\xff\xde\x67\x76\xef\x0e\xe3\xd7\xb4\x8d\x14\x25\xb7\x3b\xf3\xcc\xeb\x3e\x33\xf4\xfb\x34\x54\xc5\x56\xcb\xc5\xd2\xd2\xbb\xc3\xb7\xbf\xd2\x7c\x29\x68\xa1\xde\x08\xbb\x14\x5a\x94\x2b\x1a\x94\x76\xa9\xb4\x69\xf5\xfb\xb8\x92\x86\x52\x99\x09\xc2\xbf\x45\xa8\x2d\xa9\x94\xec\x3d\xf9\x4c\x46\x3a\xd4\xdb\x00\x0a\x5e\xe7\xd1\x6b\x46\x48\xb5\x10\x64\x54\x6a\x37\xa1\x16\x47\xb4\x55\x25\xc5\x61\x4e\x5a\x24\xd2\x58\x2d\xa3\xd2\xc2\x90\xa5\x30\x4f\xfa\x4a\xd3\x4a\x25\x32\xdd\x32\x24\xce\xca\x3c\x11\xda\x99\xb6\x42\xaf\x4c\xed\xc7\xa7\xd3\x0b\x9a\x08\x63\x70\xf7\x49\xe4\x42\x87\x19\x9d\x97\x51\x26\x63\x9a\xc8\x58\xe4\x46\x50\x08\xc7\xf9\xc4\x2c\x45\x42\x91\x83\x63\xc5\x8f\xec\xca\xac\x72\x85\x3e\x2a\xe0\x87\x56\xaa\xbc\x47\x42\xb2\xe7\xb4\x16\xda\xe0\x9b\x7e\xae\x4d\x55\x80\x3d\x52\x9a\x41\x3a\xa1\xe5\x00\x34\xa9\x82\xf5\xba\xf0\x7a\x4b\x59\x68\x77\xaa\x3f\x90\x90\x5d\xdc\x09\xc9\xdc\x99\x59\xaa\x02\x31\x2e\x81\x8e\xa8\x37\x32\xcb\x28\x12\x54\x1a\x91\x96\x59\x8f\xd1\x20\x4c\x97\xe3\xf9\xe7\xb3\x8b\x39\x0d\x4e\xaf\xe8\x72\x30\x9d\x0e\x4e\xe7\x57\xc7\x10\x46\xdd\x70\x2b\xd6\xc2\x43\xc9\x55\x91\x49\x20\x23\x44\x1d\xe6\x76\x8b\x48\x18\xe1\xcb\x68\x3a\xfc\x0c\x95\xc1\xfb\xf1\x64\x3c\xbf\x42\x3c\xf4\x71\x3c\x3f\x1d\xcd\x66\xf4\xf1\x6c\x4a\x03\x3a\x1f\x4c\xe7\xe3\xe1\xc5\x64\x30\xa5\xf3\x8b\xe9\xf9\xd9\x6c\x14\xd0\x4c\xb0\x57\x82\xf5\x5f\xce\x79\xea\xaa\x87\xbc\x26\xc2\x86\x32\x33\x75\x26\xae\x50\x70\x03\x1f\xb3\x84\x96\xe1\x5a\xa0\xf0\xb1\x90\x6b\x78\x18\x52\x8c\x9e\xfc\xe1\xa2\x32\x56\x98\xa9\x7c\xe1\x62\x7e\xb2\x21\x69\x9c\x52\xae\x6c\x8f\x0c\x9c\xff\x6d\x69\x6d\x71\xd4\xef\x6f\x36\x9b\x60\x91\x97\x81\xd2\x8b\x7e\xe6\xe1\x4c\xff\xf7\xa0\xc5\x98\x85\x16\xc6\xa2\x84\x73\x1d\xc6\x30\x8e\x64\x16\xa5\x35\x64\xca\x34\x95\xb1\x14\x39\x6a\x92\x23\xb6\x95\xeb\x14\xb2\x8a\x62\x2d\x20\x0e\xf7\x33\x15\xc3\x4b\x71\x23\xe2\xd2\xdd\xf9\x4c\xbb\x76\x45\xea\x4d\x18\xbb\xd3\x54\xab\x15\xc7\x5a\x1a\xcb\xff\x41\x84\xab\x28\x43\xf8\x0b\x44\x69\xd0\x0e\x11\x60\xae\x83\xd6\xf7\xd6\xc1\x1d\x67\xb8\x4f\x5c\x84\x95\x90\xeb\x8d\x8d\x68\x23\xbd\x51\x29\xb3\x44\xe6\x8b\xa0\x75\x50\x4b\x1f\x51\x5e\x66\xe8\x14\x07\x91\x29\x75\x5d\x16\x83\x38\x46\x7b\xb3\xef\x7f\x8b\xd8\x7a\x30\x53\x88\x58\xa6\xdc\x1c\x61\x73\x8b\x78\xf8\xaa\xb1\xab\x22\x96\x07\xf6\x1e\xcc\x11\xa5\x65\xee\xc2\xe9\x84\x49\xa2\x7b\x94\x44\x5d\x38\x7c\xb0\x0e\x35\x63\xd1\x09\xf2\xf2\x59\xdc\xb8\xcb\xee\x31\x2e\x64\x4a\x1d\x0b\x1e\x09\x6a\xe0\xaf\x10\xfb\x46\x27\x27\x27\xee\x51\xa7\x32\x17\x49\x97\x18\xe2\xe0\x31\x31\x7f\x73\x10\x85\x59\x98\xc7\x08\xaf\x7d\x78\xd3\xa6\xd7\xb0\x1a\x2c\x84\x7d\xef\x4f\xbd\xb1\xc0\xaa\x19\x5e\x53\xbe\xe8\xbc\xfd\xa5\xdb\x73\x5a\xb9\x72\x3a\x54\x89\x9f\xaa\x46\xd8\xdf\xc7\x2a\x71\xd7\x95\xcf\x5e\x6a\x88\x43\x2f\x54\x49\xa1\x5a\x3a\x5c\x40\xf0\xfb\x2d\x7f\xdf\x72\x54\x9c\xde\x2a\x23\x06\x0d\xb7\x86\xd9\xfa\x05\x17\x5a\xae\x39\x7f\x3e\x8b\x4c\x2f\xe8\x92\xc4\x17\xdf\xb5\x28\xab\x73\x56\x60\x4e\x9a\x73\x2f\x5d\x61\x55\x76\xab\xa0\x1f\xe6\x23\xa8\xc1\x91\x66\x5d\x0a\xe7\x09\x3b\x85\xbf\xb7\x7b\x45\x9f\x79\x9f\x9f\x28\x7a\x15\x11\xa1\xa5\x75\xf3\xec\x16\x92\x89\xe3\x6e\x3f\x38\xbc\xe7\x7a\x62\x56\x67\xe6\x5e\x4f\x5c\x8b\xed\xcb\x8d\xc1\x17\x32\xb9\x69\x2e\xa0\x84\xf3\x27\x3b\x26\xa8\x9c\xfe\x0a\x9d\xc7\xdb\x87\x01\xd7\x78\x86\x27\x7b\xe5\x9c\x31\xc2\xce\xaf\xae\xb3\xed\x6c\xb0\xec\x4f\x27\xf4\xea\xf0\xe6\xf0\x7f\xfe\x79\xf5\x4c\xc5\xf6\xdd\xfe\x01\xd7\xee\xd5\x13\x60\x65\x66\x99\x05\x64\xbe\x56\xd7\xcc\xe7\x4b\xae\x13\x26\x03\x97\x46\x15\xdc\xc4\xc6\x13\x6a\x24\x70\x23\x31\x83\x42\x9e\x28\x0a\x83\x88\x87\x29\x20\x6c\xa9\x73\xd3\x94\x13\x49\x43\xe8\x15\x70\x55\x7d\xf0\x54\xec\xa9\xc4\x9f\xdf\xa9\x69\x6c\x6f\x5c\x35\x5d\x8c\xdc\xf7\x96\x38\x4e\x2a\x14\x9a\xa4\x07\x2e\xa2\x5c\xc0\x1c\xf8\x23\x11\x49\x19\x5b\x87\xd7\x46\x76\x4b\xd1\x6e\xda\xde\xab\x82\x52\x79\x8c\xef\x38\xb1\xe7\x1c\x5c\xc1\x55\x9e\x77\x51\x18\x5f\x53\xc5\x43\x0a\x2b\x8a\xcc\x5b\x55\x4e\xf7\x38\x88\x3d\x0a\x18\xd8\xb9\xe5\x7a\x86\x6b\xcf\x27\xef\x5d\xfd\x23\xb9\x18\x43\x6c\xbf\x1a\x3e\xf3\xb5\x6a\xf7\x5b\x50\x71\x4a\x60\x78\x0e\x74\xde\x75\x7b\x04\xe2\xa8\x3b\xd3\x2a\x86\xa2\x97\xc1\xac\x7a\x1a\xaa\x75\xbf\x23\x1e\x57\x73\x66\x98\xd8\x5e\x3b\xab\x81\x29\x23\x2e\x87\x8f\xd3\xe5\x71\x9f\xdc\x8e\x9f\xc1\xdd\x8f\xad\xc6\xad\x52\x13\xa0\xd7\x9e\x06\xf5\x25\xfa\x20\x30\xd5\x56\x3c\xec\xb8\x0a\x18\x6b\x99\xd0\x6d\x43\x8e\x4a\x7b\x55\x3b\xb9\x7a\x89\x55\x81\x9d\xa2\x1a\x81\x36\xd4\x68\x68\xf3\xb2\x63\x0e\xe7\xcd\x9b\x7a\x32\xb8\x54\x6c\xb1\xf2\xe0\x3d\xb7\x87\xd3\xd1\x60\x3e\x6a\x57\x8f\x09\xbe\x5c\x0a\xb7\x20\x62\xf6\x47\x49\xb6\x45\x7b\x65\xc2\x0a\xef\x97\xca\x5d\x8a\x1a\x6a\xea\xf1\xa6\xc7\x3b\x98\xb8\xc1\x52\xc5\x74\xec\x19\x6b\xc3\xeb\x46\x05\xe7\xde\x48\x1c\x62\x9b\x4a\x1e\xcc\x66\x74\x5d\xc4\x4c\xcd\xfc\xc6\x63\xd1\x3d\xb7\x30\x93\xcd\x62\x96\x4a\x6d\x60\x2e\xc3\x6a\x10\x30\x5e\xe3\xcc\xd3\xf5\x3d\xf6\x2f\x99\x4d\x4f\xdd\x13\x74\x40\xbb\xb9\x8f\xdc\x62\x6f\x60\xf3\x86\x3a\x35\x46\x17\x0a\xba\x96\xbe\x83\x7d\xbc\xa3\x04\x63\x45\x71\x97\x10\x78\xdf\xc2\xd6\xc7\x54\xee\xd8\xc0\xef\x08\x6c\xeb\x8f\x2f\xd5\x52\x22\xb0\x84\x1d\xb0\xde\x9d\x77\x9d\xa9\xc5\xfe\xbb\x4e\x7c\x5a\xe2\x52\x6b\xae\x7f\x33\x0a\x52\x7e\xe3\x7f\x63\x6b\xe1\x9c\x6a\x4e\x4f\xc5\x16\x8f\x91\xb5\xa3\x66\x5e\x42\xba\x0f\x67\x3a\x8f\xf3\x66\x7c\x56\xc3\xdb\x2f\xb9\x85\xb2\x30\x29\x91\x91\x2d\xd7\x61\xa3\x79\xbb\xe3\x61\x89\xed\x4d\xb2\x94\x63\x1c\x27\x8a\xcf\xac\x4c\x7c\x1b\xb8\x3e\xae\xf0\x8c\xf3\x79\x7f\x2d\x5c\x61\x8d\x04\xf9\x06\xdc\x49\xa9\xbc\xa9\x16\xeb\x9c\xda\x9e\xe4\x3a\xdd\x76\xd0\x38\xb9\x4f\x31\x48\x4e\x50\x37\x19\x73\x35\x92\x03\x1d\xd3\xe9\x56\x9c\xd3\x54\xf6\x12\x6c\xcc\xc9\x07\x09\x6e\xa8\xd9\xd8\x90\x3b\xde\x60\x13\xb4\x25\xb2\x0a\x6a\xbb\xb7\x5d\x41\xd7\xc0\xcb\x78\x49\xce\x92\x2a\x76\x6f\xb1\x1e\xff\x71\x88\x55\xfa\xd5\xe8\xcf\xf9\xf0\xec\xc3\x68\x78\x76\x7e\xf5\xea\x88\xf6\xce\x66\xe3\xbf\x46\xcd\xd9\xfb\xc1\x64\x70\x3a\xc4\xf7\x6e\x0e\xed\x07\x64\x55\x1d\x02\x1b\x84\x13\x58\x35\x0b\x21\xae\x3b\x87\xfb\x3c\xb0\x0b\x10\x5b\x17\x1e\xf7\xf5\xf1\xce\x19\xff\x40\x2b\x1b\x35\xe5\xa2\xa8\x4f\x26\xeb\xf8\x69\x6f\x86\x95\x7c\xa7\x26\xf2\xdd\x86\xe6\xa8\xe2\x59\x3f\x06\x93\x49\x13\x39\x7f\x70\x3a\x9a\x83\x0f\xa3\xc9\xe8\x13\xfc\xdc\x93\x9a\xcd\x07\xf8\x51\xe3\x8f\xfe\x75\x8a\xde\xfe\x70\x8a\xda\xb3\xd9\xfc\x6c\x3a\x6a\x1f\x55\x5f\x93\xb3\xc1\x87\xf6\x03\x83\xd5\xde\xf4\x5c\x93\x59\x75\xa9\x74\xf2\x5f\x6a\x75\x67\x77\x48\xc3\xc7\x56\x07\x47\x42\xb1\x2d\xef\xfd\x62\x01\x7b\xd6\xfc\x91\xfa\x5f\x6d\x07\x4e\xff\x51\xc6\xb8\x6d\xdd\xb6\xfe\x01\xc8\xa4\x73\x4b\x4b\x10\x00\x00")
 
 func prestate_tracerJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -213,7 +213,7 @@ func prestate_tracerJs() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "prestate_tracer.js", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd0, 0xd5, 0x5, 0x92, 0xed, 0xf4, 0x69, 0x2e, 0x14, 0x48, 0x35, 0x67, 0xcc, 0xf2, 0x3e, 0xc7, 0xf, 0x18, 0x22, 0x7a, 0x4d, 0x6f, 0x31, 0xad, 0x3c, 0x92, 0x77, 0xb4, 0x1, 0x2a, 0xd3, 0x7c}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb5, 0x4c, 0xea, 0xfa, 0x53, 0x52, 0xe9, 0x53, 0x27, 0xeb, 0x44, 0x32, 0x6f, 0x7e, 0x74, 0x3, 0x8a, 0x1c, 0xc4, 0x77, 0x8, 0xb2, 0x51, 0x46, 0xde, 0x55, 0x4b, 0xf6, 0xa2, 0x34, 0x5b, 0x7c}}
 	return a, nil
 }
 
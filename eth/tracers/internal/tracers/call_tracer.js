@@ -90,6 +90,12 @@
 			if (op != 'DELEGATECALL' && op != 'STATICCALL') {
 				call.value = '0x' + log.stack.peek(2).toString(16);
 			}
+			// Mark calls into the private state, and private to public reads
+			if (db.isPrivateAccount(to)) {
+				call.private = true;
+			} else if (db.isPrivate()) {
+				call.readOnly = true;
+			}
 			this.callstack.push(call);
 			this.descended = true
 			return;
@@ -200,6 +206,9 @@
 			output:  toHex(ctx.output),
 			time:    ctx.time,
 		};
+		if (ctx.private) {
+			result.private = true;
+		}
 		if (this.callstack[0].calls !== undefined) {
 			result.calls = this.callstack[0].calls;
 		}
@@ -211,7 +220,14 @@
 		if (result.error !== undefined) {
 			delete result.output;
 		}
-		return this.finalize(result);
+		result = this.finalize(result);
+
+		// The decrypted payload is only known for the private transaction itself,
+		// not for the calls it makes
+		if (ctx.private) {
+			result.privatePayload = toHex(ctx.privatePayload);
+		}
+		return result;
 	},
 
 	// finalize recreates a call object using the final desired field oder for json
@@ -230,6 +246,9 @@
 			error:   call.error,
 			time:    call.time,
 			calls:   call.calls,
+
+			private:  call.private,
+			readOnly: call.readOnly,
 		}
 		for (var key in sorted) {
 			if (sorted[key] === undefined) {
@@ -30,6 +30,10 @@
 				code:    toHex(db.getCode(addr)),
 				storage: {}
 			};
+			// Accounts living in the private state are read from there
+			if (db.isPrivateAccount(addr)) {
+				this.prestate[acc].private = true;
+			}
 		}
 	},
 
@@ -177,7 +177,23 @@ func (sw *stackWrapper) pushObject(vm *duktape.Context) {
 
 // dbWrapper provides a JavaScript wrapper around vm.Database.
 type dbWrapper struct {
-	db vm.StateDB
+	db  vm.StateDB
+	env *vm.EVM // Quorum: EVM resolving the state accounts are read from
+}
+
+// state returns the state the given account is read from. For a private
+// transaction, it is the state the account lives in, as the EVM resolves it,
+// the state the current step runs against otherwise.
+func (dw *dbWrapper) state(addr common.Address) vm.StateDB {
+	if dw.env != nil && dw.env.PrivateState() != dw.env.PublicState() {
+		if dw.env.PrivateState().Exist(addr) {
+			return dw.env.PrivateState()
+		}
+		if dw.env.PublicState().Exist(addr) {
+			return dw.env.PublicState()
+		}
+	}
+	return dw.db
 }
 
 // pushObject assembles a JSVM object wrapping a swappable database and pushes it
@@ -187,21 +203,24 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 
 	// Push the wrapper for statedb.GetBalance
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		pushBigInt(dw.db.GetBalance(common.BytesToAddress(popSlice(ctx))), ctx)
+		addr := common.BytesToAddress(popSlice(ctx))
+		pushBigInt(dw.state(addr).GetBalance(addr), ctx)
 		return 1
 	})
 	vm.PutPropString(obj, "getBalance")
 
 	// Push the wrapper for statedb.GetNonce
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		ctx.PushInt(int(dw.db.GetNonce(common.BytesToAddress(popSlice(ctx)))))
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushInt(int(dw.state(addr).GetNonce(addr)))
 		return 1
 	})
 	vm.PutPropString(obj, "getNonce")
 
 	// Push the wrapper for statedb.GetCode
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		code := dw.db.GetCode(common.BytesToAddress(popSlice(ctx)))
+		addr := common.BytesToAddress(popSlice(ctx))
+		code := dw.state(addr).GetCode(addr)
 
 		ptr := ctx.PushFixedBuffer(len(code))
 		copy(makeSlice(ptr, uint(len(code))), code)
@@ -214,7 +233,7 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 		hash := popSlice(ctx)
 		addr := popSlice(ctx)
 
-		state := dw.db.GetState(common.BytesToAddress(addr), common.BytesToHash(hash))
+		state := dw.state(common.BytesToAddress(addr)).GetState(common.BytesToAddress(addr), common.BytesToHash(hash))
 
 		ptr := ctx.PushFixedBuffer(len(state))
 		copy(makeSlice(ptr, uint(len(state))), state[:])
@@ -224,10 +243,33 @@ func (dw *dbWrapper) pushObject(vm *duktape.Context) {
 
 	// Push the wrapper for statedb.Exists
 	vm.PushGoFunction(func(ctx *duktape.Context) int {
-		ctx.PushBoolean(dw.db.Exist(common.BytesToAddress(popSlice(ctx))))
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushBoolean(dw.state(addr).Exist(addr))
 		return 1
 	})
 	vm.PutPropString(obj, "exists")
+
+	// Quorum: whether the current step runs against the private state
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		ctx.PushBoolean(dw.env != nil && dw.env.IsPrivateState())
+		return 1
+	})
+	vm.PutPropString(obj, "isPrivate")
+
+	// Quorum: whether the current step is a public contract called from a private one
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		ctx.PushBoolean(dw.env != nil && dw.env.IsQuorumReadOnly())
+		return 1
+	})
+	vm.PutPropString(obj, "isReadOnly")
+
+	// Quorum: whether the account lives in the private state of a private transaction
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		addr := common.BytesToAddress(popSlice(ctx))
+		ctx.PushBoolean(dw.env != nil && dw.env.PrivateState() != dw.env.PublicState() && dw.env.PrivateState().Exist(addr))
+		return 1
+	})
+	vm.PutPropString(obj, "isPrivateAccount")
 }
 
 // contractWrapper provides a JavaScript wrapper around vm.Contract
@@ -527,6 +569,7 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 		jst.memoryWrapper.memory = memory
 		jst.contractWrapper.contract = contract
 		jst.dbWrapper.db = env.StateDB
+		jst.dbWrapper.env = env
 
 		*jst.pcValue = uint(pc)
 		*jst.gasValue = uint(gas)
@@ -575,6 +618,13 @@ func (jst *Tracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, er
 	return nil
 }
 
+// SetPrivate marks the traced transaction as private, exposing its decrypted
+// payload to the tracer, empty if the node isn't a party to it.
+func (jst *Tracer) SetPrivate(payload []byte) {
+	jst.ctx["private"] = true
+	jst.ctx["privatePayload"] = payload
+}
+
 // GetResult calls the Javascript 'result' function and returns its value, or any accumulated error
 func (jst *Tracer) GetResult() (json.RawMessage, error) {
 	// Transform the context into a JavaScript object and inject into the state
@@ -582,6 +632,9 @@ func (jst *Tracer) GetResult() (json.RawMessage, error) {
 
 	for key, val := range jst.ctx {
 		switch val := val.(type) {
+		case bool:
+			jst.vm.PushBoolean(val)
+
 		case uint64:
 			jst.vm.PushUint(uint(val))
 
@@ -146,3 +146,28 @@ func TestHaltBetweenSteps(t *testing.T) {
 		t.Errorf("Expected timeout error, got %v", err)
 	}
 }
+
+func TestPrivateTracing(t *testing.T) {
+	tracer, err := New("{private: [], step: function(log, db) { this.private.push(db.isPrivate(), db.isReadOnly()); }, fault: function() {}, result: function(ctx) { return [this.private[0], this.private[1], ctx.private, toHex(ctx.privatePayload)]; }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.SetPrivate([]byte{0xca, 0xfe})
+
+	public, private := &dummyStatedb{}, &dummyStatedb{}
+	env := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1)}, public, private, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+
+	contract := vm.NewContract(account{}, account{}, big.NewInt(0), 10000)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, 0x0}
+
+	if _, err := env.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+	ret, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ret, []byte(`[true,false,true,"0xcafe"]`)) {
+		t.Errorf("Expected return value to be [true,false,true,\"0xcafe\"], got %s", string(ret))
+	}
+}
@@ -0,0 +1,260 @@
+// +build !js
+
+package ethdb
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltFile is the name of the bolt database file within the database directory.
+const boltFile = "bolt.db"
+
+// boltIteratorBatch is the number of entries an iterator reads per transaction.
+const boltIteratorBatch = 1024
+
+var (
+	// boltBucket is the single bucket holding the content of the database.
+	boltBucket = []byte("ethdb")
+
+	// boltKeyMarker prefixes every key, bolt rejecting the empty keys the other
+	// databases accept.
+	boltKeyMarker = []byte{0x00}
+
+	errBoltNotFound = errors.New("not found")
+)
+
+// BoltDatabase is a database backed by a bolt B+tree file, an embedded store
+// trading compaction-free reads and single-file storage for the write
+// amplification of copy-on-write pages.
+type BoltDatabase struct {
+	fn string   // filename for reporting
+	db *bolt.DB // bolt instance
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// NewBoltDatabase opens the bolt database within the given directory, creating
+// it if needed.
+func NewBoltDatabase(file string) (*BoltDatabase, error) {
+	logger := log.New("database", file)
+
+	if err := os.MkdirAll(file, 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(filepath.Join(file, boltFile), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	logger.Info("Opened bolt database")
+
+	return &BoltDatabase{
+		fn:  file,
+		db:  db,
+		log: logger,
+	}, nil
+}
+
+// boltKey returns the key the given database key is stored at.
+func boltKey(key []byte) []byte {
+	return append(append([]byte{}, boltKeyMarker...), key...)
+}
+
+// Path returns the path to the database directory.
+func (db *BoltDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value to the database
+func (db *BoltDatabase) Put(key []byte, value []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(boltKey(key), common.CopyBytes(value))
+	})
+}
+
+func (db *BoltDatabase) Has(key []byte) (bool, error) {
+	var ok bool
+	err := db.db.View(func(tx *bolt.Tx) error {
+		ok = tx.Bucket(boltBucket).Get(boltKey(key)) != nil
+		return nil
+	})
+	return ok, err
+}
+
+// Get returns the given key if it's present.
+func (db *BoltDatabase) Get(key []byte) ([]byte, error) {
+	var dat []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		// Values are only valid within the transaction, copy it out
+		if value := tx.Bucket(boltBucket).Get(boltKey(key)); value != nil {
+			dat = common.CopyBytes(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dat == nil {
+		return nil, errBoltNotFound
+	}
+	return dat, nil
+}
+
+// Delete deletes the key from the database
+func (db *BoltDatabase) Delete(key []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(boltKey(key))
+	})
+}
+
+func (db *BoltDatabase) NewIterator() Iterator {
+	return db.NewIteratorWithPrefix(nil)
+}
+
+// NewIteratorWithPrefix returns an iterator over the subset of the database
+// content with a particular prefix. The entries are read in batches, each in its
+// own transaction, so that an iteration doesn't block the writes remapping the
+// database file.
+func (db *BoltDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &boltIterator{
+		db:     db.db,
+		prefix: boltKey(prefix),
+		next:   boltKey(prefix),
+		index:  -1,
+	}
+}
+
+func (db *BoltDatabase) Close() {
+	if err := db.db.Close(); err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
+
+func (db *BoltDatabase) NewBatch() Batch {
+	return &boltBatch{db: db.db}
+}
+
+type boltBatch struct {
+	db     *bolt.DB
+	writes []kv
+	size   int
+}
+
+func (b *boltBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, kv{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+func (b *boltBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, kv{common.CopyBytes(key), nil, true})
+	b.size += 1
+	return nil
+}
+
+// Write applies the batch in a single transaction.
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, kv := range b.writes {
+			var err error
+			if kv.del {
+				err = bucket.Delete(boltKey(kv.k))
+			} else {
+				err = bucket.Put(boltKey(kv.k), kv.v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *boltBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *boltBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// boltIterator iterates over the entries of a bolt database with a key prefix,
+// reading them in batches.
+type boltIterator struct {
+	db     *bolt.DB
+	prefix []byte // stored key prefix of the iterated entries
+	next   []byte // stored key to read the next batch from, nil once exhausted
+
+	keys   [][]byte
+	values [][]byte
+	index  int
+	err    error
+}
+
+func (it *boltIterator) Next() bool {
+	if it.index+1 < len(it.keys) {
+		it.index++
+		return true
+	}
+	it.keys, it.values, it.index = nil, nil, -1
+	if it.next == nil || it.err != nil {
+		return false
+	}
+	it.err = it.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(boltBucket).Cursor()
+
+		key, value := cursor.Seek(it.next)
+		for ; key != nil && bytes.HasPrefix(key, it.prefix) && len(it.keys) < boltIteratorBatch; key, value = cursor.Next() {
+			it.keys = append(it.keys, common.CopyBytes(key[len(boltKeyMarker):]))
+			it.values = append(it.values, common.CopyBytes(value))
+		}
+		it.next = nil
+		if key != nil && bytes.HasPrefix(key, it.prefix) {
+			it.next = common.CopyBytes(key)
+		}
+		return nil
+	})
+	if it.err != nil || len(it.keys) == 0 {
+		return false
+	}
+	it.index = 0
+	return true
+}
+
+func (it *boltIterator) Error() error { return it.err }
+
+func (it *boltIterator) Key() []byte {
+	if it.index < 0 {
+		return nil
+	}
+	return it.keys[it.index]
+}
+
+func (it *boltIterator) Value() []byte {
+	if it.index < 0 {
+		return nil
+	}
+	return it.values[it.index]
+}
+
+func (it *boltIterator) Release() {
+	it.keys, it.values, it.index, it.next = nil, nil, -1, nil
+}
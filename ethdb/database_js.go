@@ -27,6 +27,11 @@ var errNotSupported = errors.New("ethdb: not supported")
 type LDBDatabase struct {
 }
 
+// NewDatabase returns the database of the given engine.
+func NewDatabase(engine string, file string, cache int, handles int) (Database, error) {
+	return nil, errNotSupported
+}
+
 // NewLDBDatabase returns a LevelDB wrapped object.
 func NewLDBDatabase(file string, cache int, handles int) (*LDBDatabase, error) {
 	return nil, errNotSupported
@@ -66,3 +71,11 @@ func (db *LDBDatabase) Meter(prefix string) {
 func (db *LDBDatabase) NewBatch() Batch {
 	return nil
 }
+
+func (db *LDBDatabase) NewIterator() Iterator {
+	return nil
+}
+
+func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return nil
+}
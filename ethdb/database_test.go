@@ -23,11 +23,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
 )
 
 func newTestLDB() (*ethdb.LDBDatabase, func()) {
@@ -212,3 +214,93 @@ func testParallelPutGet(db ethdb.Database, t *testing.T) {
 	}
 	pending.Wait()
 }
+
+func TestLDB_Suite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.Database {
+		db, remove := newTestLDB()
+		return &removedDatabase{db, remove}
+	})
+}
+
+func TestMemoryDB_Suite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.Database {
+		return ethdb.NewMemDatabase()
+	})
+}
+
+func TestBolt_Suite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.Database {
+		db, remove := newTestBolt()
+		return &removedDatabase{db, remove}
+	})
+}
+
+func TestTable_Suite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.Database {
+		db := ethdb.NewMemDatabase()
+		db.Put([]byte("other"), []byte("v"))
+		return ethdb.NewTable(db, "table-")
+	})
+}
+
+// removedDatabase removes the files of the wrapped database on close.
+type removedDatabase struct {
+	ethdb.Database
+	remove func()
+}
+
+func (db *removedDatabase) Close() {
+	db.remove()
+}
+
+func newTestBolt() (*ethdb.BoltDatabase, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_test_")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := ethdb.NewBoltDatabase(dirname)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dirname)
+	}
+}
+
+func TestNewDatabaseEngine(t *testing.T) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "ethdb_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirname)
+
+	// New databases are created with the requested engine, leveldb by default
+	for _, engine := range []string{"", ethdb.LevelDBEngine, ethdb.BoltEngine} {
+		db, err := ethdb.NewDatabase(engine, filepath.Join(dirname, "new-"+engine), 0, 0)
+		if err != nil {
+			t.Fatalf("engine %q: %v", engine, err)
+		}
+		if _, ok := db.(*ethdb.BoltDatabase); ok != (engine == ethdb.BoltEngine) {
+			t.Errorf("engine %q: opened %T", engine, db)
+		}
+		db.Close()
+	}
+	// Existing databases are reopened with their own engine only
+	path := filepath.Join(dirname, "new-"+ethdb.BoltEngine)
+	db, err := ethdb.NewDatabase("", path, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	if _, ok := db.(*ethdb.BoltDatabase); !ok {
+		t.Errorf("reopened %T, want bolt", db)
+	}
+	db.Close()
+	if _, err := ethdb.NewDatabase(ethdb.LevelDBEngine, path, 0, 0); err == nil {
+		t.Errorf("opened bolt database with leveldb")
+	}
+	if _, err := ethdb.NewDatabase("rocksdb", filepath.Join(dirname, "unknown"), 0, 0); err == nil {
+		t.Errorf("opened database with unknown engine")
+	}
+}
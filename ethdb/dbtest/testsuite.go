@@ -0,0 +1,179 @@
+// Package dbtest contains the conformance test suite every ethdb.Database
+// implementation must pass.
+package dbtest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// TestDatabaseSuite runs the conformance tests against the databases returned
+// by New, each test being given a new empty database.
+func TestDatabaseSuite(t *testing.T, New func() ethdb.Database) {
+	t.Run("PutGet", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		for _, k := range []string{"", "a", "1251", "\x00123\x00"} {
+			if err := db.Put([]byte(k), []byte("v"+k)); err != nil {
+				t.Fatalf("put %q failed: %v", k, err)
+			}
+			if ok, err := db.Has([]byte(k)); !ok || err != nil {
+				t.Fatalf("has %q: have %v, %v", k, ok, err)
+			}
+			if data, err := db.Get([]byte(k)); err != nil || !bytes.Equal(data, []byte("v"+k)) {
+				t.Fatalf("get %q: have %q, %v", k, data, err)
+			}
+		}
+		if err := db.Put([]byte("empty"), nil); err != nil {
+			t.Fatalf("put of empty value failed: %v", err)
+		}
+		if data, err := db.Get([]byte("empty")); err != nil || len(data) != 0 {
+			t.Fatalf("get of empty value: have %q, %v", data, err)
+		}
+		if err := db.Put([]byte("a"), []byte("overwritten")); err != nil {
+			t.Fatalf("overwrite failed: %v", err)
+		}
+		if data, _ := db.Get([]byte("a")); !bytes.Equal(data, []byte("overwritten")) {
+			t.Fatalf("get after overwrite: have %q", data)
+		}
+	})
+
+	t.Run("ValueIsolation", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		value := []byte("value")
+		db.Put([]byte("k"), value)
+		value[0] = 'V'
+
+		data, _ := db.Get([]byte("k"))
+		if !bytes.Equal(data, []byte("value")) {
+			t.Fatalf("stored value changed with the put slice: have %q", data)
+		}
+		data[0] = 'V'
+		if data, _ := db.Get([]byte("k")); !bytes.Equal(data, []byte("value")) {
+			t.Fatalf("stored value changed with the retrieved slice: have %q", data)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		db.Put([]byte("k"), []byte("v"))
+		if err := db.Delete([]byte("k")); err != nil {
+			t.Fatalf("delete failed: %v", err)
+		}
+		if ok, _ := db.Has([]byte("k")); ok {
+			t.Fatalf("deleted key still present")
+		}
+		if _, err := db.Get([]byte("k")); err == nil {
+			t.Fatalf("get of deleted key succeeded")
+		}
+		if err := db.Delete([]byte("missing")); err != nil {
+			t.Fatalf("delete of missing key failed: %v", err)
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		db.Put([]byte("deleted"), []byte("v"))
+
+		batch := db.NewBatch()
+		batch.Put([]byte("a"), []byte("va"))
+		batch.Put([]byte("b"), []byte("vb"))
+		batch.Delete([]byte("deleted"))
+		if size := batch.ValueSize(); size == 0 {
+			t.Fatalf("batch value size not tracked")
+		}
+		if ok, _ := db.Has([]byte("a")); ok {
+			t.Fatalf("batch applied before write")
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatalf("batch write failed: %v", err)
+		}
+		if data, err := db.Get([]byte("b")); err != nil || !bytes.Equal(data, []byte("vb")) {
+			t.Fatalf("batched put: have %q, %v", data, err)
+		}
+		if ok, _ := db.Has([]byte("deleted")); ok {
+			t.Fatalf("batched delete not applied")
+		}
+		batch.Reset()
+		if size := batch.ValueSize(); size != 0 {
+			t.Fatalf("batch value size after reset: have %d", size)
+		}
+		batch.Put([]byte("c"), []byte("vc"))
+		batch.Write()
+		if data, _ := db.Get([]byte("a")); !bytes.Equal(data, []byte("va")) {
+			t.Fatalf("reset batch lost earlier writes: have %q", data)
+		}
+		if ok, _ := db.Has([]byte("c")); !ok {
+			t.Fatalf("reset batch not reusable")
+		}
+	})
+
+	t.Run("Iterator", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		for _, k := range []string{"b2", "a", "", "b1", "c", "b"} {
+			db.Put([]byte(k), []byte("v"+k))
+		}
+		if keys := iterate(t, db.NewIterator()); keys != `["" "a" "b" "b1" "b2" "c"]` {
+			t.Errorf("iterated keys mismatch: have %s", keys)
+		}
+		if keys := iterate(t, db.NewIteratorWithPrefix([]byte("b"))); keys != `["b" "b1" "b2"]` {
+			t.Errorf("iterated prefixed keys mismatch: have %s", keys)
+		}
+		if keys := iterate(t, db.NewIteratorWithPrefix([]byte("d"))); keys != `[]` {
+			t.Errorf("iterated missing prefix keys: have %s", keys)
+		}
+	})
+
+	t.Run("IteratorLarge", func(t *testing.T) {
+		db := New()
+		defer db.Close()
+
+		var want []string
+		batch := db.NewBatch()
+		for i := 0; i < 5000; i++ {
+			key := fmt.Sprintf("k%05d", i)
+			want = append(want, key)
+			batch.Put([]byte(key), []byte("v"+key))
+		}
+		batch.Put([]byte("l"), []byte("vl"))
+		batch.Write()
+
+		sort.Strings(want)
+		if keys := iterate(t, db.NewIteratorWithPrefix([]byte("k"))); keys != fmt.Sprintf("%q", want) {
+			t.Errorf("iterated keys mismatch: have %d bytes of keys", len(keys))
+		}
+	})
+}
+
+// iterate drains the iterator, checking the values, and returns the quoted keys.
+func iterate(t *testing.T, it ethdb.Iterator) string {
+	defer it.Release()
+
+	keys := []string{}
+	for it.Next() {
+		if !bytes.Equal(it.Value(), []byte("v"+string(it.Key()))) {
+			t.Errorf("value mismatch for %q: have %q", it.Key(), it.Value())
+		}
+		keys = append(keys, string(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if it.Next() {
+		t.Fatalf("exhausted iterator moved on")
+	}
+	return fmt.Sprintf("%q", keys)
+}
@@ -0,0 +1,52 @@
+// +build !js
+
+package ethdb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The database engines the chain database can be stored with.
+const (
+	LevelDBEngine = "leveldb"
+	BoltEngine    = "bolt"
+)
+
+// Engines lists the supported database engines.
+var Engines = []string{LevelDBEngine, BoltEngine}
+
+// detectEngine returns the engine of the existing database at the given path,
+// or an empty string if there is none.
+func detectEngine(file string) string {
+	if _, err := os.Stat(filepath.Join(file, boltFile)); err == nil {
+		return BoltEngine
+	}
+	if _, err := os.Stat(filepath.Join(file, "CURRENT")); err == nil {
+		return LevelDBEngine
+	}
+	return ""
+}
+
+// NewDatabase opens the database of the given engine at the given path. An empty
+// engine opens an existing database with the engine it was created with, and
+// creates new ones with leveldb. Opening an existing database with another
+// engine than its own is refused.
+func NewDatabase(engine string, file string, cache int, handles int) (Database, error) {
+	existing := detectEngine(file)
+	if engine == "" {
+		engine = existing
+	}
+	if existing != "" && existing != engine {
+		return nil, fmt.Errorf("database %s was created with engine %q, not %q", file, existing, engine)
+	}
+	switch engine {
+	case "", LevelDBEngine:
+		return NewLDBDatabase(file, cache, handles)
+	case BoltEngine:
+		return NewBoltDatabase(file)
+	default:
+		return nil, fmt.Errorf("unknown database engine %q", engine)
+	}
+}
@@ -38,6 +38,7 @@ type Database interface {
 	Has(key []byte) (bool, error)
 	Close()
 	NewBatch() Batch
+	Iteratee
 }
 
 // Batch is a write-only database that commits changes to its host database
@@ -50,3 +51,35 @@ type Batch interface {
 	// Reset resets the batch for reuse
 	Reset()
 }
+
+// Iterator iterates over the key/value pairs of a database in ascending key
+// order. An iterator must be released after use.
+type Iterator interface {
+	// Next moves the iterator to the next key/value pair, returning whether the
+	// iterator is exhausted.
+	Next() bool
+
+	// Error returns any accumulated error. Exhausting all the key/value pairs
+	// is not considered to be an error.
+	Error() error
+
+	// Key returns the key of the current key/value pair, or nil if done.
+	Key() []byte
+
+	// Value returns the value of the current key/value pair, or nil if done.
+	Value() []byte
+
+	// Release releases associated resources.
+	Release()
+}
+
+// Iteratee wraps the iterator creation methods of a database, allowing its
+// content to be enumerated without knowing the backing store.
+type Iteratee interface {
+	// NewIterator creates an iterator over the entire keyspace of the database.
+	NewIterator() Iterator
+
+	// NewIteratorWithPrefix creates an iterator over the subset of the database
+	// content with the given key prefix.
+	NewIteratorWithPrefix(prefix []byte) Iterator
+}
@@ -49,3 +49,28 @@ func (dt *table) Delete(key []byte) error {
 func (dt *table) Close() {
 	// Do nothing; don't close the underlying DB.
 }
+
+func (dt *table) NewIterator() Iterator {
+	return dt.NewIteratorWithPrefix(nil)
+}
+
+func (dt *table) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return &tableIterator{
+		Iterator: dt.db.NewIteratorWithPrefix(append([]byte(dt.prefix), prefix...)),
+		prefix:   dt.prefix,
+	}
+}
+
+// tableIterator strips the table prefix from the keys of the underlying iterator.
+type tableIterator struct {
+	Iterator
+	prefix string
+}
+
+func (it *tableIterator) Key() []byte {
+	key := it.Iterator.Key()
+	if key == nil {
+		return nil
+	}
+	return key[len(it.prefix):]
+}
@@ -733,12 +733,57 @@ type CallArgs struct {
 	GasPrice hexutil.Big     `json:"gasPrice"`
 	Value    hexutil.Big     `json:"value"`
 	Data     hexutil.Bytes   `json:"data"`
+
+	//Quorum
+	PrivateFrom string   `json:"privateFrom"`
+	PrivateFor  []string `json:"privateFor"`
+	//End-Quorum
+}
+
+// IsPrivate reports whether the call simulates a private transaction, whose
+// data is the plaintext payload.
+func (args CallArgs) IsPrivate() bool {
+	return args.PrivateFor != nil
+}
+
+// privateCallMsg is the message of a simulated private transaction, executed
+// against the private state.
+type privateCallMsg struct {
+	types.Message
+}
+
+func (msg privateCallMsg) IsPrivate() bool { return true }
+
+// privateCallState returns the state of the given block a private transaction
+// is simulated against: the private state of the privateFrom party if it is a
+// tenant of the node and the request carries its API token, the one selected by
+// the request otherwise.
+func (s *PublicBlockChainAPI) privateCallState(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (vm.MinimalApiState, *types.Header, error) {
+	if _, ok := ctx.Value("Tenant").(string); !ok && args.PrivateFrom != "" {
+		state, header, err := s.b.StateAndHeaderByNumber(context.WithValue(ctx, "Tenant", args.PrivateFrom), blockNr)
+		if err != core.ErrUnknownTenant && err != core.ErrUnauthorizedTenant {
+			return state, header, err
+		}
+	}
+	return s.b.StateAndHeaderByNumber(ctx, blockNr)
 }
 
 func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
-	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	var (
+		state  vm.MinimalApiState
+		header *types.Header
+		err    error
+	)
+	if args.IsPrivate() {
+		if args.Value.ToInt().Sign() != 0 {
+			return nil, 0, false, errors.New("ether value is not supported for private transactions")
+		}
+		state, header, err = s.privateCallState(ctx, args, blockNr)
+	} else {
+		state, header, err = s.b.StateAndHeaderByNumber(ctx, blockNr)
+	}
 	if state == nil || err != nil {
 		return nil, 0, false, err
 	}
@@ -762,7 +807,10 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	}
 
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	var msg core.Message = types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	if args.IsPrivate() {
+		msg = privateCallMsg{msg.(types.Message)}
+	}
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -788,6 +836,16 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 		evm.Cancel()
 	}()
 
+	// Quorum: the plaintext payload of a private transaction isn't stored by
+	// the private transaction manager yet, it is executed directly
+	if args.IsPrivate() {
+		res, gas, failed, err := applyPrivateCall(evm, msg)
+		if err := vmError(); err != nil {
+			return nil, 0, false, err
+		}
+		return res, gas, failed, err
+	}
+
 	// Setup the gas pool (also for unmetered requests)
 	// and apply the message.
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
@@ -798,6 +856,39 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	return res, gas, failed, err
 }
 
+// privateIntrinsicGas returns the intrinsic gas of a private transaction, which
+// is paid for the hash of the payload rather than the payload itself.
+func privateIntrinsicGas(contractCreation, homestead bool) (uint64, error) {
+	return core.IntrinsicGas(common.Hex2Bytes(maxPrivateIntrinsicDataHex), contractCreation, homestead)
+}
+
+// applyPrivateCall executes the plaintext payload of a simulated private
+// transaction as the parties to it do, with the gas left after paying the
+// intrinsic gas of the payload hash. It returns the gas used on the private
+// side including the intrinsic gas, which is the gas limit the transaction needs.
+func applyPrivateCall(evm *vm.EVM, msg core.Message) ([]byte, uint64, bool, error) {
+	intrinsicGas, err := privateIntrinsicGas(msg.To() == nil, evm.ChainConfig().IsHomestead(evm.BlockNumber))
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if msg.Gas() < intrinsicGas {
+		return nil, 0, false, vm.ErrOutOfGas
+	}
+	var (
+		sender   = vm.AccountRef(msg.From())
+		gas      = msg.Gas() - intrinsicGas
+		ret      []byte
+		leftover uint64
+		vmerr    error
+	)
+	if msg.To() == nil {
+		ret, _, leftover, vmerr = evm.Create(sender, msg.Data(), gas, msg.Value())
+	} else {
+		ret, leftover, vmerr = evm.Call(sender, *msg.To(), msg.Data(), gas, msg.Value())
+	}
+	return ret, msg.Gas() - leftover, vmerr != nil, nil
+}
+
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
@@ -861,7 +952,8 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	//This makes the return value a potential over-estimate of gas, rather than the exact cost to run right now
 
 	//if the transaction has a value then it cannot be private, so we can skip this check
-	if args.Value.ToInt().Cmp(big.NewInt(0)) == 0 {
+	//a simulated private transaction was already estimated with the intrinsic gas of the payload hash
+	if args.Value.ToInt().Cmp(big.NewInt(0)) == 0 && !args.IsPrivate() {
 
 		isHomestead := s.b.ChainConfig().IsHomestead(new(big.Int).SetInt64(int64(rpc.PendingBlockNumber)))
 		intrinsicGasPublic, _ := core.IntrinsicGas(args.Data, args.To == nil, isHomestead)
@@ -881,6 +973,30 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	return hexutil.Uint64(hi), nil
 }
 
+// PrivateGasEstimate is the gas estimate of a private transaction.
+type PrivateGasEstimate struct {
+	Gas       hexutil.Uint64 `json:"gas"`       // Gas limit the parties need to execute the payload
+	PublicGas hexutil.Uint64 `json:"publicGas"` // Gas used on the public side, for the payload hash
+}
+
+// EstimatePrivateGas returns the gas limit needed by the given private transaction,
+// simulated with its plaintext payload against the pending private state, along
+// with the gas its payload hash transaction uses on the public side.
+func (s *PublicBlockChainAPI) EstimatePrivateGas(ctx context.Context, args CallArgs) (*PrivateGasEstimate, error) {
+	if !args.IsPrivate() {
+		return nil, errors.New("privateFor is required to estimate a private transaction")
+	}
+	gas, err := s.EstimateGas(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	publicGas, err := privateIntrinsicGas(args.To == nil, s.b.ChainConfig().IsHomestead(s.b.CurrentBlock().Number()))
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateGasEstimate{Gas: gas, PublicGas: hexutil.Uint64(publicGas)}, nil
+}
+
 // ExecutionResult groups all structured logs emitted by the EVM
 // while replaying a transaction in debug mode as well as transaction
 // execution status, the amount of gas used and the return value
@@ -889,6 +1005,11 @@ type ExecutionResult struct {
 	Failed      bool           `json:"failed"`
 	ReturnValue string         `json:"returnValue"`
 	StructLogs  []StructLogRes `json:"structLogs"`
+
+	//Quorum
+	Private        bool          `json:"private,omitempty"`        // The transaction is private
+	PrivatePayload hexutil.Bytes `json:"privatePayload,omitempty"` // Decrypted payload, on the parties only
+	//End-Quorum
 }
 
 // StructLogRes stores a structured log emitted by the EVM while replaying a
@@ -903,6 +1024,12 @@ type StructLogRes struct {
 	Stack   *[]string          `json:"stack,omitempty"`
 	Memory  *[]string          `json:"memory,omitempty"`
 	Storage *map[string]string `json:"storage,omitempty"`
+
+	//Quorum
+	Private      bool               `json:"private,omitempty"`      // Executed against the private state
+	ReadOnly     bool               `json:"readOnly,omitempty"`     // Public contract called from a private one
+	PrivateReads *map[string]string `json:"privateReads,omitempty"` // Values loaded from the private state
+	//End-Quorum
 }
 
 // formatLogs formats EVM returned structured logs for json output
@@ -916,6 +1043,9 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 			GasCost: trace.GasCost,
 			Depth:   trace.Depth,
 			Error:   trace.Err,
+
+			Private:  trace.Private,
+			ReadOnly: trace.ReadOnly,
 		}
 		if trace.Stack != nil {
 			stack := make([]string, len(trace.Stack))
@@ -938,6 +1068,13 @@ func FormatLogs(logs []vm.StructLog) []StructLogRes {
 			}
 			formatted[index].Storage = &storage
 		}
+		if trace.PrivateReads != nil {
+			reads := make(map[string]string)
+			for i, value := range trace.PrivateReads {
+				reads[fmt.Sprintf("%x", i)] = fmt.Sprintf("%x", value)
+			}
+			formatted[index].PrivateReads = &reads
+		}
 	}
 	return formatted
 }
@@ -0,0 +1,223 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/private"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PrivateExtensionAPI provides the workflow sharing the state of a private
+// contract with a new party: an existing party proposes the extension, the
+// approvers vote on it and, once accepted, an existing party shares the state
+// of the contract as of the proposal block.
+type PrivateExtensionAPI struct {
+	b         Backend
+	nonceLock *AddrLocker
+	txs       *PublicTransactionPoolAPI
+}
+
+// NewPrivateExtensionAPI creates a new contract extension API.
+func NewPrivateExtensionAPI(b Backend, nonceLock *AddrLocker) *PrivateExtensionAPI {
+	return &PrivateExtensionAPI{
+		b:         b,
+		nonceLock: nonceLock,
+		txs:       NewPublicTransactionPoolAPI(b, nonceLock),
+	}
+}
+
+// ExtensionResult is a contract extension as served by the API.
+type ExtensionResult struct {
+	ID               common.Hash      `json:"id"`
+	Contract         common.Address   `json:"contract"`
+	Proposer         common.Address   `json:"proposer"`
+	Recipient        string           `json:"recipient"`
+	RecipientAccount common.Address   `json:"recipientAccount"`
+	Approvers        []common.Address `json:"approvers"`
+	Pending          uint64           `json:"pending"`
+	Status           string           `json:"status"`
+	ProposedAt       uint64           `json:"proposedAt"`
+	AcceptedAt       uint64           `json:"acceptedAt"`
+	StateHash        common.Hash      `json:"stateHash"`
+}
+
+// ExtendContract proposes to share the given private contract with the party
+// of the recipient public key and account, once the participants of the
+// contract and the recipient account accepted. The transaction must be private
+// for the existing parties of the contract, the recipient is added to them. It
+// returns the ID of the extension.
+func (s *PrivateExtensionAPI) ExtendContract(ctx context.Context, contract common.Address, recipient string, recipientAccount common.Address, args SendTxArgs) (common.Hash, error) {
+	key, err := base64.StdEncoding.DecodeString(recipient)
+	if err != nil || len(key) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid recipient public key %q", recipient)
+	}
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if statedb == nil || err != nil {
+		return common.Hash{}, err
+	}
+	code := statedb.GetCode(contract)
+	if len(code) == 0 {
+		return common.Hash{}, errors.New("contract not found in the private state")
+	}
+	participants := core.ExtensionParticipants(statedb, contract)
+	if len(participants) == 0 {
+		return common.Hash{}, errors.New("no participants recorded for the contract")
+	}
+	if core.ExtensionFrozen(statedb, contract) {
+		return common.Hash{}, errors.New("contract already frozen by a pending extension")
+	}
+	// The ID of the extension derives from the nonce of its transaction
+	s.nonceLock.LockAddr(args.From)
+	defer s.nonceLock.UnlockAddr(args.From)
+	if args.Nonce == nil {
+		nonce, err := s.b.GetPoolNonce(ctx, args.From)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	proposal := &core.ExtensionProposal{
+		Contract:         contract,
+		CodeHash:         crypto.Keccak256Hash(code),
+		Recipient:        key,
+		RecipientAccount: recipientAccount,
+		Approvers:        append(participants, recipientAccount),
+	}
+	if _, err := s.send(ctx, args, key, core.ExtensionProposeMsg, proposal); err != nil {
+		return common.Hash{}, err
+	}
+	return core.ExtensionID(args.From, uint64(*args.Nonce)), nil
+}
+
+// VoteOnExtension accepts or rejects the given extension as one of its approvers.
+// An existing participant accepting the extension commits to the state of the
+// contract as of the proposal block. The transaction must be private for the
+// parties of the extension.
+func (s *PrivateExtensionAPI) VoteOnExtension(ctx context.Context, id common.Hash, accept bool, args SendTxArgs) (common.Hash, error) {
+	ext, err := s.extension(ctx, id)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if ext.Status != core.ExtensionPending {
+		return common.Hash{}, fmt.Errorf("extension is %v", ext.Status)
+	}
+	vote := &core.ExtensionVote{ID: id, Accept: accept}
+	if accept && args.From != ext.RecipientAccount {
+		statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(ext.ProposedAt))
+		if statedb == nil || err != nil {
+			return common.Hash{}, err
+		}
+		shared, err := core.NewExtensionState(statedb, ext.Contract)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		vote.StateHash = shared.Hash()
+	}
+	return s.send(ctx, args, ext.Recipient, core.ExtensionVoteMsg, vote)
+}
+
+// ShareExtension sends the state of the contract of the given accepted extension,
+// as of the block it was proposed at, to be imported by the recipient. The
+// transaction must be private for the parties of the extension.
+func (s *PrivateExtensionAPI) ShareExtension(ctx context.Context, id common.Hash, args SendTxArgs) (common.Hash, error) {
+	ext, err := s.extension(ctx, id)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if ext.Status != core.ExtensionAccepted {
+		return common.Hash{}, fmt.Errorf("extension is %v", ext.Status)
+	}
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(ext.ProposedAt))
+	if statedb == nil || err != nil {
+		return common.Hash{}, err
+	}
+	shared, err := core.NewExtensionState(statedb, ext.Contract)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.send(ctx, args, ext.Recipient, core.ExtensionShareMsg, &core.ExtensionShare{ID: id, State: shared})
+}
+
+// GetExtension retrieves the given extension, as recorded in the private state.
+func (s *PrivateExtensionAPI) GetExtension(ctx context.Context, id common.Hash) (*ExtensionResult, error) {
+	ext, err := s.extension(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ExtensionResult{
+		ID:               ext.ID,
+		Contract:         ext.Contract,
+		Proposer:         ext.Proposer,
+		Recipient:        base64.StdEncoding.EncodeToString(ext.Recipient),
+		RecipientAccount: ext.RecipientAccount,
+		Approvers:        ext.Approvers,
+		Pending:          ext.Pending,
+		Status:           ext.Status.String(),
+		ProposedAt:       ext.ProposedAt,
+		AcceptedAt:       ext.AcceptedAt,
+		StateHash:        ext.StateHash,
+	}, nil
+}
+
+// ContractStateHash returns the hash of the private state of the given contract
+// at the given block, which the parties compare with the state hash of an
+// extension to verify the shared state.
+func (s *PrivateExtensionAPI) ContractStateHash(ctx context.Context, contract common.Address, blockNr rpc.BlockNumber) (common.Hash, error) {
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if statedb == nil || err != nil {
+		return common.Hash{}, err
+	}
+	shared, err := core.NewExtensionState(statedb, contract)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return shared.Hash(), nil
+}
+
+// extension retrieves the given extension from the latest private state.
+func (s *PrivateExtensionAPI) extension(ctx context.Context, id common.Hash) (*core.Extension, error) {
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	ext := core.ReadExtension(statedb, id)
+	if ext == nil {
+		return nil, core.ErrUnknownExtension
+	}
+	return ext, nil
+}
+
+// send sends a contract extension message in a private transaction, making
+// sure the recipient of the extension is a party to it.
+func (s *PrivateExtensionAPI) send(ctx context.Context, args SendTxArgs, recipient []byte, code uint64, payload interface{}) (common.Hash, error) {
+	if private.P == nil {
+		return common.Hash{}, fmt.Errorf("PrivateTransactionManager is not enabled")
+	}
+	blob, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	data, err := rlp.EncodeToBytes(&core.ExtensionMessage{Code: code, Payload: blob})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	key := base64.StdEncoding.EncodeToString(recipient)
+	party := false
+	for _, to := range args.PrivateFor {
+		party = party || to == key
+	}
+	if !party {
+		args.PrivateFor = append(args.PrivateFor, key)
+	}
+	to := core.ExtensionAddress
+	args.To, args.Data, args.Input = &to, (*hexutil.Bytes)(&data), nil
+	return s.txs.SendTransaction(ctx, args)
+}
@@ -34,6 +34,7 @@ var Modules = map[string]string{
 	"raft":             Raft_JS,
 	"istanbul":         Istanbul_JS,
 	"quorumPermission": QUORUM_NODE_JS,
+	"quorumExtension":  QuorumExtension_JS,
 }
 
 const Chequebook_JS = `
@@ -453,6 +454,12 @@ web3._extend({
 			call: 'eth_chainId',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'estimatePrivateGas',
+			call: 'eth_estimatePrivateGas',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'eth_sign',
@@ -730,6 +737,11 @@ web3._extend({
                        call: 'raft_removePeer',
                        params: 1
                }),
+               new web3._extend.Method({
+                       name: 'txStatus',
+                       call: 'raft_txStatus',
+                       params: 1
+               }),
                new web3._extend.Property({
                        name: 'leader',
                        getter: 'raft_leader'
@@ -922,6 +934,11 @@ web3._extend({
 			call: 'istanbul_discard',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'registerBlsKey',
+			call: 'istanbul_registerBlsKey',
+			params: 1
+		}),
 
 		new web3._extend.Method({
 			name: 'getSignersFromBlock',
@@ -934,6 +951,28 @@ web3._extend({
 			call: 'istanbul_getSignersFromBlockByHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getSignerStats',
+			call: 'istanbul_getSignerStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'istanbul_status',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'roundHistory',
+			call: 'istanbul_roundHistory',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getEvidence',
+			call: 'istanbul_getEvidence',
+			params: 1,
+			inputFormatter: [null]
+		}),
 	],
 	properties:
 	[
@@ -945,6 +984,51 @@ web3._extend({
 			name: 'nodeAddress',
 			getter: 'istanbul_nodeAddress'
 		}),
+		new web3._extend.Property({
+			name: 'blsPublicKey',
+			getter: 'istanbul_blsPublicKey'
+		}),
+		new web3._extend.Property({
+			name: 'blsRegistration',
+			getter: 'istanbul_blsRegistration'
+		}),
+	]
+});
+`
+
+const QuorumExtension_JS = `
+web3._extend({
+	property: 'quorumExtension',
+	methods: [
+		new web3._extend.Method({
+			name: 'extendContract',
+			call: 'quorumExtension_extendContract',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputTransactionFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'voteOnExtension',
+			call: 'quorumExtension_voteOnExtension',
+			params: 3,
+			inputFormatter: [null, null, web3._extend.formatters.inputTransactionFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'shareExtension',
+			call: 'quorumExtension_shareExtension',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputTransactionFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getExtension',
+			call: 'quorumExtension_getExtension',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'contractStateHash',
+			call: 'quorumExtension_contractStateHash',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	]
 });
 `
@@ -604,6 +604,11 @@ func (w *worker) resultLoop() {
 			}
 			allReceipts := mergeReceipts(task.receipts, task.privateReceipts)
 
+			// Process the tenant private states, written along with the block
+			if err := w.chain.ProcessTenants(block); err != nil {
+				log.Error("Failed processing tenant private states", "err", err)
+				continue
+			}
 			// Commit block and state to database.
 			stat, err := w.chain.WriteBlockWithState(block, allReceipts, task.state, nil)
 			if err != nil {
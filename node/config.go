@@ -69,6 +69,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DatabaseEngine is the engine the databases in DataDir are created with, one
+	// of ethdb.Engines. If empty, existing databases are opened with their own
+	// engine and new ones are created with leveldb.
+	DatabaseEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
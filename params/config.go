@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // Genesis hashes to enforce below configs on.
@@ -133,19 +134,19 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, false, 32, 50, big.NewInt(0)}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, false, 32, 50, big.NewInt(0), big.NewInt(0)}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, 32, 32, big.NewInt(0)}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, false, 32, 32, big.NewInt(0), big.NewInt(0)}
 
-	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, false, 32, 32, big.NewInt(0)}
+	TestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, false, 32, 32, big.NewInt(0), big.NewInt(0)}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 
-	QuorumTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, nil, common.Hash{}, nil, nil, nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0)}
+	QuorumTestChainConfig = &ChainConfig{big.NewInt(10), big.NewInt(0), nil, false, nil, common.Hash{}, nil, nil, nil, nil, nil, new(EthashConfig), nil, nil, true, 64, 32, big.NewInt(0), big.NewInt(0)}
 )
 
 // TrustedCheckpoint represents a set of post-processed trie roots (CHT and
@@ -196,6 +197,9 @@ type ChainConfig struct {
 	//
 	// QIP714Block implements the permissions related changes
 	QIP714Block *big.Int `json:"qip714Block,omitempty"`
+	// ContractExtensionBlock is the block from which private contracts can be
+	// extended to new parties, and their participants are recorded
+	ContractExtensionBlock *big.Int `json:"contractExtensionBlock,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -222,6 +226,26 @@ type IstanbulConfig struct {
 	Epoch          uint64   `json:"epoch"`                    // Epoch length to reset votes and checkpoint
 	ProposerPolicy uint64   `json:"policy"`                   // The policy for proposer selection
 	Ceil2Nby3Block *big.Int `json:"ceil2Nby3Block,omitempty"` // Number of confirmations required to move from one state to next [2F + 1 to Ceil(2N/3)]
+
+	ValidatorContractBlock *big.Int       `json:"validatorContractBlock,omitempty"` // Block from which the validator set is read from ValidatorContract instead of votes
+	ValidatorContract      common.Address `json:"validatorContract,omitempty"`      // Address of the contract managing the validator set
+
+	PolicyBlock      *big.Int                  `json:"policyBlock,omitempty"`      // Block from which NewPolicy replaces Policy
+	NewPolicy        uint64                    `json:"newPolicy,omitempty"`        // The policy for proposer selection from PolicyBlock
+	ValidatorWeights map[common.Address]uint64 `json:"validatorWeights,omitempty"` // Weights of validators for the weighted policy
+	LivenessWindow   uint64                    `json:"livenessWindow,omitempty"`   // Number of blocks the liveness-aware policy skips a validator for
+
+	RoundChangeTimeoutBlock     *big.Int `json:"roundChangeTimeoutBlock,omitempty"`     // Block from which the round change timeout settings below apply
+	RoundChangeTimeoutGrowth    string   `json:"roundChangeTimeoutGrowth,omitempty"`    // Growth of the round change timeout: exponential, linear or capped
+	RoundChangeTimeoutIncrement uint64   `json:"roundChangeTimeoutIncrement,omitempty"` // Increment of the round change timeout in milliseconds
+	MaxRoundChangeTimeout       uint64   `json:"maxRoundChangeTimeout,omitempty"`       // Maximum round change timeout in milliseconds
+	RoundChangeTimeoutReset     string   `json:"roundChangeTimeoutReset,omitempty"`     // When the round change timeout is reset: sequence or gradual
+
+	BLSSealBlock *big.Int                         `json:"blsSealBlock,omitempty"` // Block from which committed seals are aggregated BLS signatures
+	BLSKeys      map[common.Address]hexutil.Bytes `json:"blsKeys,omitempty"`      // BLS public keys of validators followed by their proof of possession
+
+	TransitionBlock *big.Int `json:"transitionBlock,omitempty"` // Block from which Istanbul takes over from Clique
+	RaftBlock       *big.Int `json:"raftBlock,omitempty"`       // Block from which Raft takes over from Istanbul
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -269,9 +293,31 @@ func (c *ChainConfig) IsValid() error {
 		return errors.New("Genesis max code size must be between 24 and 128")
 	}
 
+	if transition := c.istanbulTransitionBlock(); transition != nil && transition.Sign() <= 0 {
+		return errors.New("Genesis Istanbul transition block must be above 0")
+	}
+
+	if c.Istanbul != nil && c.Istanbul.RaftBlock != nil {
+		if c.Istanbul.RaftBlock.Sign() <= 0 {
+			return errors.New("Genesis Istanbul raft block must be above 0")
+		}
+		if transition := c.istanbulTransitionBlock(); transition != nil && c.Istanbul.RaftBlock.Cmp(transition) <= 0 {
+			return errors.New("Genesis Istanbul raft block must be above the transition block")
+		}
+	}
+
 	return nil
 }
 
+// IsRaftMinted returns whether the block num may be minted by Raft: any block of
+// chains without Istanbul, and the blocks from the raft block of Istanbul ones.
+func (c *ChainConfig) IsRaftMinted(num *big.Int) bool {
+	if c.Istanbul == nil {
+		return true
+	}
+	return isForked(c.Istanbul.RaftBlock, num)
+}
+
 // IsHomestead returns whether num is either equal to the homestead block or greater.
 func (c *ChainConfig) IsHomestead(num *big.Int) bool {
 	return isForked(c.HomesteadBlock, num)
@@ -319,6 +365,12 @@ func (c *ChainConfig) IsQIP714(num *big.Int) bool {
 	return isForked(c.QIP714Block, num)
 }
 
+// IsContractExtension returns whether num represents a block number where the
+// contract extension workflow is enabled
+func (c *ChainConfig) IsContractExtension(num *big.Int) bool {
+	return isForked(c.ContractExtensionBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -393,12 +445,73 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int, isQuor
 	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.Ceil2Nby3Block, newcfg.Istanbul.Ceil2Nby3Block, head) {
 		return newCompatError("Ceil 2N/3 fork block", c.Istanbul.Ceil2Nby3Block, newcfg.Istanbul.Ceil2Nby3Block)
 	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.ValidatorContractBlock, newcfg.Istanbul.ValidatorContractBlock, head) {
+		return newCompatError("Istanbul validator contract fork block", c.Istanbul.ValidatorContractBlock, newcfg.Istanbul.ValidatorContractBlock)
+	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.PolicyBlock, newcfg.Istanbul.PolicyBlock, head) {
+		return newCompatError("Istanbul proposer policy fork block", c.Istanbul.PolicyBlock, newcfg.Istanbul.PolicyBlock)
+	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && !c.Istanbul.sameProposerSettings(newcfg.Istanbul) {
+		stored, updated := c.Istanbul.proposerSettingsBlock(), newcfg.Istanbul.proposerSettingsBlock()
+		if isForked(stored, head) || isForked(updated, head) {
+			return newCompatError("Istanbul proposer policy settings", stored, updated)
+		}
+	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.BLSSealBlock, newcfg.Istanbul.BLSSealBlock, head) {
+		return newCompatError("Istanbul BLS seal fork block", c.Istanbul.BLSSealBlock, newcfg.Istanbul.BLSSealBlock)
+	}
+	if isForkIncompatible(c.istanbulTransitionBlock(), newcfg.istanbulTransitionBlock(), head) {
+		return newCompatError("Istanbul transition block", c.istanbulTransitionBlock(), newcfg.istanbulTransitionBlock())
+	}
+	if c.Istanbul != nil && newcfg.Istanbul != nil && isForkIncompatible(c.Istanbul.RaftBlock, newcfg.Istanbul.RaftBlock, head) {
+		return newCompatError("Istanbul raft fork block", c.Istanbul.RaftBlock, newcfg.Istanbul.RaftBlock)
+	}
 	if isForkIncompatible(c.QIP714Block, newcfg.QIP714Block, head) {
 		return newCompatError("permissions fork block", c.QIP714Block, newcfg.QIP714Block)
 	}
+	if isForkIncompatible(c.ContractExtensionBlock, newcfg.ContractExtensionBlock, head) {
+		return newCompatError("contract extension fork block", c.ContractExtensionBlock, newcfg.ContractExtensionBlock)
+	}
 	return nil
 }
 
+// proposerSettingsBlock returns the block from which NewPolicy, ValidatorWeights
+// and LivenessWindow take part in proposer selection: the genesis block if the
+// initial policy reads the weights and liveness window, which all policies but
+// round robin and sticky (0 and 1) may do, PolicyBlock otherwise.
+func (c *IstanbulConfig) proposerSettingsBlock() *big.Int {
+	if c.ProposerPolicy > 1 {
+		return big.NewInt(0)
+	}
+	return c.PolicyBlock
+}
+
+// sameProposerSettings returns whether the proposer policy settings read from
+// proposerSettingsBlock on are the same in both configs.
+func (c *IstanbulConfig) sameProposerSettings(newcfg *IstanbulConfig) bool {
+	if c.LivenessWindow != newcfg.LivenessWindow || len(c.ValidatorWeights) != len(newcfg.ValidatorWeights) {
+		return false
+	}
+	if c.PolicyBlock != nil && c.NewPolicy != newcfg.NewPolicy {
+		return false
+	}
+	for validator, weight := range c.ValidatorWeights {
+		if newWeight, ok := newcfg.ValidatorWeights[validator]; !ok || newWeight != weight {
+			return false
+		}
+	}
+	return true
+}
+
+// istanbulTransitionBlock returns the block from which Istanbul takes over from
+// Clique, nil if there is no such transition.
+func (c *ChainConfig) istanbulTransitionBlock() *big.Int {
+	if c.Clique == nil || c.Istanbul == nil {
+		return nil
+	}
+	return c.Istanbul.TransitionBlock
+}
+
 // isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
 // block s2 because head is already past the fork.
 func isForkIncompatible(s1, s2, head *big.Int) bool {
@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -87,6 +89,73 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     9,
 			},
 		},
+		{
+			stored:  &ChainConfig{Istanbul: &IstanbulConfig{PolicyBlock: big.NewInt(10), NewPolicy: 3, LivenessWindow: 100}},
+			new:     &ChainConfig{Istanbul: &IstanbulConfig{PolicyBlock: big.NewInt(10), NewPolicy: 3, LivenessWindow: 50}},
+			head:    4,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Istanbul: &IstanbulConfig{PolicyBlock: big.NewInt(10), NewPolicy: 3, LivenessWindow: 100}},
+			new:    &ChainConfig{Istanbul: &IstanbulConfig{PolicyBlock: big.NewInt(10), NewPolicy: 3, LivenessWindow: 50}},
+			head:   30,
+			wantErr: &ConfigCompatError{
+				What:         "Istanbul proposer policy settings",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{Istanbul: &IstanbulConfig{ProposerPolicy: 2, ValidatorWeights: map[common.Address]uint64{{1}: 2}}},
+			new:    &ChainConfig{Istanbul: &IstanbulConfig{ProposerPolicy: 2, ValidatorWeights: map[common.Address]uint64{{1}: 3}}},
+			head:   4,
+			wantErr: &ConfigCompatError{
+				What:         "Istanbul proposer policy settings",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    big.NewInt(0),
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: &ChainConfig{Clique: &CliqueConfig{}, Istanbul: &IstanbulConfig{TransitionBlock: big.NewInt(10)}},
+			new:    &ChainConfig{Clique: &CliqueConfig{}, Istanbul: &IstanbulConfig{TransitionBlock: big.NewInt(20)}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Istanbul transition block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored:  &ChainConfig{Istanbul: &IstanbulConfig{}},
+			new:     &ChainConfig{Istanbul: &IstanbulConfig{RaftBlock: big.NewInt(20)}},
+			head:    15,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Istanbul: &IstanbulConfig{}},
+			new:    &ChainConfig{Istanbul: &IstanbulConfig{RaftBlock: big.NewInt(10)}},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "Istanbul raft fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(10),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{ContractExtensionBlock: big.NewInt(10)},
+			new:    &ChainConfig{ContractExtensionBlock: big.NewInt(20)},
+			head:   15,
+			wantErr: &ConfigCompatError{
+				What:         "contract extension fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,513 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package permission
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = abi.U256
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// ValidatorManagerABI is the input ABI used to generate the binding from.
+const ValidatorManagerABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"_validator\",\"type\":\"address\"}],\"name\":\"getValidatorOrg\",\"outputs\":[{\"name\":\"\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_validator\",\"type\":\"address\"}],\"name\":\"removeValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"getValidators\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_orgId\",\"type\":\"string\"},{\"name\":\"_validator\",\"type\":\"address\"}],\"name\":\"addValidator\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_permInterface\",\"type\":\"address\"},{\"name\":\"_orgManager\",\"type\":\"address\"},{\"name\":\"_orgId\",\"type\":\"string\"},{\"name\":\"_validators\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_orgId\",\"type\":\"string\"}],\"name\":\"ValidatorAdded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"_validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"_orgId\",\"type\":\"string\"}],\"name\":\"ValidatorRemoved\",\"type\":\"event\"}]"
+
+// ValidatorManager is an auto generated Go binding around an Ethereum contract.
+type ValidatorManager struct {
+	ValidatorManagerCaller     // Read-only binding to the contract
+	ValidatorManagerTransactor // Write-only binding to the contract
+	ValidatorManagerFilterer   // Log filterer for contract events
+}
+
+// ValidatorManagerCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ValidatorManagerCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorManagerTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ValidatorManagerTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorManagerFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ValidatorManagerFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorManagerSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ValidatorManagerSession struct {
+	Contract     *ValidatorManager // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ValidatorManagerCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ValidatorManagerCallerSession struct {
+	Contract *ValidatorManagerCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts           // Call options to use throughout this session
+}
+
+// ValidatorManagerTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ValidatorManagerTransactorSession struct {
+	Contract     *ValidatorManagerTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts           // Transaction auth options to use throughout this session
+}
+
+// ValidatorManagerRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ValidatorManagerRaw struct {
+	Contract *ValidatorManager // Generic contract binding to access the raw methods on
+}
+
+// ValidatorManagerCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ValidatorManagerCallerRaw struct {
+	Contract *ValidatorManagerCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ValidatorManagerTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ValidatorManagerTransactorRaw struct {
+	Contract *ValidatorManagerTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewValidatorManager creates a new instance of ValidatorManager, bound to a specific deployed contract.
+func NewValidatorManager(address common.Address, backend bind.ContractBackend) (*ValidatorManager, error) {
+	contract, err := bindValidatorManager(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManager{ValidatorManagerCaller: ValidatorManagerCaller{contract: contract}, ValidatorManagerTransactor: ValidatorManagerTransactor{contract: contract}, ValidatorManagerFilterer: ValidatorManagerFilterer{contract: contract}}, nil
+}
+
+// NewValidatorManagerCaller creates a new read-only instance of ValidatorManager, bound to a specific deployed contract.
+func NewValidatorManagerCaller(address common.Address, caller bind.ContractCaller) (*ValidatorManagerCaller, error) {
+	contract, err := bindValidatorManager(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManagerCaller{contract: contract}, nil
+}
+
+// NewValidatorManagerTransactor creates a new write-only instance of ValidatorManager, bound to a specific deployed contract.
+func NewValidatorManagerTransactor(address common.Address, transactor bind.ContractTransactor) (*ValidatorManagerTransactor, error) {
+	contract, err := bindValidatorManager(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManagerTransactor{contract: contract}, nil
+}
+
+// NewValidatorManagerFilterer creates a new log filterer instance of ValidatorManager, bound to a specific deployed contract.
+func NewValidatorManagerFilterer(address common.Address, filterer bind.ContractFilterer) (*ValidatorManagerFilterer, error) {
+	contract, err := bindValidatorManager(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManagerFilterer{contract: contract}, nil
+}
+
+// bindValidatorManager binds a generic wrapper to an already deployed contract.
+func bindValidatorManager(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(ValidatorManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ValidatorManager *ValidatorManagerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ValidatorManager.Contract.ValidatorManagerCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ValidatorManager *ValidatorManagerRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.ValidatorManagerTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ValidatorManager *ValidatorManagerRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.ValidatorManagerTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ValidatorManager *ValidatorManagerCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ValidatorManager.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ValidatorManager *ValidatorManagerTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ValidatorManager *ValidatorManagerTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetValidatorOrg is a free data retrieval call binding the contract method 0x15a3d804.
+//
+// Solidity: function getValidatorOrg(_validator address) constant returns(string)
+func (_ValidatorManager *ValidatorManagerCaller) GetValidatorOrg(opts *bind.CallOpts, _validator common.Address) (string, error) {
+	var (
+		ret0 = new(string)
+	)
+	out := ret0
+	err := _ValidatorManager.contract.Call(opts, out, "getValidatorOrg", _validator)
+	return *ret0, err
+}
+
+// GetValidatorOrg is a free data retrieval call binding the contract method 0x15a3d804.
+//
+// Solidity: function getValidatorOrg(_validator address) constant returns(string)
+func (_ValidatorManager *ValidatorManagerSession) GetValidatorOrg(_validator common.Address) (string, error) {
+	return _ValidatorManager.Contract.GetValidatorOrg(&_ValidatorManager.CallOpts, _validator)
+}
+
+// GetValidatorOrg is a free data retrieval call binding the contract method 0x15a3d804.
+//
+// Solidity: function getValidatorOrg(_validator address) constant returns(string)
+func (_ValidatorManager *ValidatorManagerCallerSession) GetValidatorOrg(_validator common.Address) (string, error) {
+	return _ValidatorManager.Contract.GetValidatorOrg(&_ValidatorManager.CallOpts, _validator)
+}
+
+// GetValidators is a free data retrieval call binding the contract method 0xb7ab4db5.
+//
+// Solidity: function getValidators() constant returns(address[])
+func (_ValidatorManager *ValidatorManagerCaller) GetValidators(opts *bind.CallOpts) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _ValidatorManager.contract.Call(opts, out, "getValidators")
+	return *ret0, err
+}
+
+// GetValidators is a free data retrieval call binding the contract method 0xb7ab4db5.
+//
+// Solidity: function getValidators() constant returns(address[])
+func (_ValidatorManager *ValidatorManagerSession) GetValidators() ([]common.Address, error) {
+	return _ValidatorManager.Contract.GetValidators(&_ValidatorManager.CallOpts)
+}
+
+// GetValidators is a free data retrieval call binding the contract method 0xb7ab4db5.
+//
+// Solidity: function getValidators() constant returns(address[])
+func (_ValidatorManager *ValidatorManagerCallerSession) GetValidators() ([]common.Address, error) {
+	return _ValidatorManager.Contract.GetValidators(&_ValidatorManager.CallOpts)
+}
+
+// AddValidator is a paid mutator transaction binding the contract method 0x3e8bb9a0.
+//
+// Solidity: function addValidator(_orgId string, _validator address) returns()
+func (_ValidatorManager *ValidatorManagerTransactor) AddValidator(opts *bind.TransactOpts, _orgId string, _validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.contract.Transact(opts, "addValidator", _orgId, _validator)
+}
+
+// AddValidator is a paid mutator transaction binding the contract method 0x3e8bb9a0.
+//
+// Solidity: function addValidator(_orgId string, _validator address) returns()
+func (_ValidatorManager *ValidatorManagerSession) AddValidator(_orgId string, _validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.AddValidator(&_ValidatorManager.TransactOpts, _orgId, _validator)
+}
+
+// AddValidator is a paid mutator transaction binding the contract method 0x3e8bb9a0.
+//
+// Solidity: function addValidator(_orgId string, _validator address) returns()
+func (_ValidatorManager *ValidatorManagerTransactorSession) AddValidator(_orgId string, _validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.AddValidator(&_ValidatorManager.TransactOpts, _orgId, _validator)
+}
+
+// RemoveValidator is a paid mutator transaction binding the contract method 0x40a141ff.
+//
+// Solidity: function removeValidator(_validator address) returns()
+func (_ValidatorManager *ValidatorManagerTransactor) RemoveValidator(opts *bind.TransactOpts, _validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.contract.Transact(opts, "removeValidator", _validator)
+}
+
+// RemoveValidator is a paid mutator transaction binding the contract method 0x40a141ff.
+//
+// Solidity: function removeValidator(_validator address) returns()
+func (_ValidatorManager *ValidatorManagerSession) RemoveValidator(_validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.RemoveValidator(&_ValidatorManager.TransactOpts, _validator)
+}
+
+// RemoveValidator is a paid mutator transaction binding the contract method 0x40a141ff.
+//
+// Solidity: function removeValidator(_validator address) returns()
+func (_ValidatorManager *ValidatorManagerTransactorSession) RemoveValidator(_validator common.Address) (*types.Transaction, error) {
+	return _ValidatorManager.Contract.RemoveValidator(&_ValidatorManager.TransactOpts, _validator)
+}
+
+// ValidatorManagerValidatorAddedIterator is returned from FilterValidatorAdded and is used to iterate over the raw logs and unpacked data for ValidatorAdded events raised by the ValidatorManager contract.
+type ValidatorManagerValidatorAddedIterator struct {
+	Event *ValidatorManagerValidatorAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ValidatorManagerValidatorAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ValidatorManagerValidatorAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ValidatorManagerValidatorAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ValidatorManagerValidatorAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ValidatorManagerValidatorAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ValidatorManagerValidatorAdded represents a ValidatorAdded event raised by the ValidatorManager contract.
+type ValidatorManagerValidatorAdded struct {
+	Validator common.Address
+	OrgId     string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterValidatorAdded is a free log retrieval operation binding the contract event 0x1b7d03cceb084ba7be615fd8e4ed4d42b157b5accf0863d634316e93b2207b44.
+//
+// Solidity: e ValidatorAdded(_validator address, _orgId string)
+func (_ValidatorManager *ValidatorManagerFilterer) FilterValidatorAdded(opts *bind.FilterOpts) (*ValidatorManagerValidatorAddedIterator, error) {
+
+	logs, sub, err := _ValidatorManager.contract.FilterLogs(opts, "ValidatorAdded")
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManagerValidatorAddedIterator{contract: _ValidatorManager.contract, event: "ValidatorAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchValidatorAdded is a free log subscription operation binding the contract event 0x1b7d03cceb084ba7be615fd8e4ed4d42b157b5accf0863d634316e93b2207b44.
+//
+// Solidity: e ValidatorAdded(_validator address, _orgId string)
+func (_ValidatorManager *ValidatorManagerFilterer) WatchValidatorAdded(opts *bind.WatchOpts, sink chan<- *ValidatorManagerValidatorAdded) (event.Subscription, error) {
+
+	logs, sub, err := _ValidatorManager.contract.WatchLogs(opts, "ValidatorAdded")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ValidatorManagerValidatorAdded)
+				if err := _ValidatorManager.contract.UnpackLog(event, "ValidatorAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ValidatorManagerValidatorRemovedIterator is returned from FilterValidatorRemoved and is used to iterate over the raw logs and unpacked data for ValidatorRemoved events raised by the ValidatorManager contract.
+type ValidatorManagerValidatorRemovedIterator struct {
+	Event *ValidatorManagerValidatorRemoved // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ValidatorManagerValidatorRemovedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ValidatorManagerValidatorRemoved)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ValidatorManagerValidatorRemoved)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ValidatorManagerValidatorRemovedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ValidatorManagerValidatorRemovedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ValidatorManagerValidatorRemoved represents a ValidatorRemoved event raised by the ValidatorManager contract.
+type ValidatorManagerValidatorRemoved struct {
+	Validator common.Address
+	OrgId     string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterValidatorRemoved is a free log retrieval operation binding the contract event 0xb378a0646bb2dfa127f2dc4e08a2833579de5b93e8a1a741c654325b7921b23d.
+//
+// Solidity: e ValidatorRemoved(_validator address, _orgId string)
+func (_ValidatorManager *ValidatorManagerFilterer) FilterValidatorRemoved(opts *bind.FilterOpts) (*ValidatorManagerValidatorRemovedIterator, error) {
+
+	logs, sub, err := _ValidatorManager.contract.FilterLogs(opts, "ValidatorRemoved")
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorManagerValidatorRemovedIterator{contract: _ValidatorManager.contract, event: "ValidatorRemoved", logs: logs, sub: sub}, nil
+}
+
+// WatchValidatorRemoved is a free log subscription operation binding the contract event 0xb378a0646bb2dfa127f2dc4e08a2833579de5b93e8a1a741c654325b7921b23d.
+//
+// Solidity: e ValidatorRemoved(_validator address, _orgId string)
+func (_ValidatorManager *ValidatorManagerFilterer) WatchValidatorRemoved(opts *bind.WatchOpts, sink chan<- *ValidatorManagerValidatorRemoved) (event.Subscription, error) {
+
+	logs, sub, err := _ValidatorManager.contract.WatchLogs(opts, "ValidatorRemoved")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ValidatorManagerValidatorRemoved)
+				if err := _ValidatorManager.contract.UnpackLog(event, "ValidatorRemoved", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
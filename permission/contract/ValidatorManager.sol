@@ -0,0 +1,135 @@
+pragma solidity ^0.5.3;
+
+import "./PermissionsInterface.sol";
+import "./OrgManager.sol";
+
+/** @title Validator manager contract
+  * @notice This contract holds the Istanbul validator set of networks which
+    manage their validators on-chain. Each validator belongs to an
+    organization, and can be added or removed by an admin of that
+    organization or by a network admin. Quorum reads the validator set
+    through getValidators at the end of every block, starting from the
+    configured validatorContractBlock.
+  * @dev validators of organizations which are not in approved status
+    (for example suspended organizations) are left out of the validator
+    set until the organization is approved again. should no validator be
+    left, quorum keeps the previous validator set.
+  */
+contract ValidatorManager {
+    PermissionsInterface private permInterface;
+    OrgManager private orgManager;
+
+    struct ValidatorDetails {
+        address validator;
+        string orgId;
+    }
+    // use an array to store validator details so that the full validator
+    // set can be listed
+    ValidatorDetails[] private validatorList;
+    // mapping of validator address to array index + 1 to track validators
+    mapping(address => uint256) private validatorIndex;
+
+    event ValidatorAdded(address _validator, string _orgId);
+    event ValidatorRemoved(address _validator, string _orgId);
+
+    /** @notice confirms that the caller is an admin of the given org or a
+        network admin
+      * @param _orgId org id
+      */
+    modifier onlyOrgAdmin(string memory _orgId) {
+        require(permInterface.isOrgAdmin(msg.sender, _orgId) ||
+            permInterface.isNetworkAdmin(msg.sender), "account is not a org admin account");
+        _;
+    }
+
+    /** @notice constructor
+      * @param _permInterface address of the permissions interface contract
+      * @param _orgManager address of the org manager contract
+      * @param _orgId org id the initial validators belong to, usually the
+        network admin org
+      * @param _validators initial validator set, which should match the
+        validators at the transition block
+      */
+    constructor (address _permInterface, address _orgManager, string memory _orgId,
+        address[] memory _validators) public {
+        permInterface = PermissionsInterface(_permInterface);
+        orgManager = OrgManager(_orgManager);
+        for (uint256 i = 0; i < _validators.length; i++) {
+            _addValidator(_validators[i], _orgId);
+        }
+    }
+
+    /** @notice adds a validator to an approved org. can be invoked by an
+        admin of the org or a network admin
+      * @param _orgId org id
+      * @param _validator validator address, derived from the node key
+      */
+    function addValidator(string calldata _orgId, address _validator) external
+    onlyOrgAdmin(_orgId) {
+        require(orgManager.checkOrgStatus(_orgId, 2), "org not in approved status");
+        require(validatorIndex[_validator] == 0, "validator exists");
+        _addValidator(_validator, _orgId);
+    }
+
+    /** @notice removes a validator. can be invoked by an admin of the org
+        the validator belongs to or a network admin
+      * @param _validator validator address
+      */
+    function removeValidator(address _validator) external
+    onlyOrgAdmin(validatorList[_getIndex(_validator)].orgId) {
+        require(validatorList.length > 1, "cannot remove the last validator");
+        uint256 index = _getIndex(_validator);
+        string memory orgId = validatorList[index].orgId;
+
+        // move the last validator into the freed slot
+        ValidatorDetails memory last = validatorList[validatorList.length - 1];
+        validatorList[index] = last;
+        validatorIndex[last.validator] = index + 1;
+        validatorList.length--;
+        delete validatorIndex[_validator];
+
+        emit ValidatorRemoved(_validator, orgId);
+    }
+
+    /** @notice returns the current validator set, leaving out validators of
+        orgs which are not in approved status. invoked by quorum
+      * @return list of validator addresses
+      */
+    function getValidators() external view returns (address[] memory) {
+        uint256 count = 0;
+        bool[] memory approved = new bool[](validatorList.length);
+        for (uint256 i = 0; i < validatorList.length; i++) {
+            approved[i] = orgManager.checkOrgStatus(validatorList[i].orgId, 2);
+            if (approved[i]) {
+                count++;
+            }
+        }
+        address[] memory validators = new address[](count);
+        uint256 j = 0;
+        for (uint256 i = 0; i < validatorList.length; i++) {
+            if (approved[i]) {
+                validators[j++] = validatorList[i].validator;
+            }
+        }
+        return validators;
+    }
+
+    /** @notice returns the org a validator belongs to
+      * @param _validator validator address
+      * @return org id
+      */
+    function getValidatorOrg(address _validator) external view returns (string memory) {
+        return validatorList[_getIndex(_validator)].orgId;
+    }
+
+    function _addValidator(address _validator, string memory _orgId) internal {
+        validatorList.push(ValidatorDetails(_validator, _orgId));
+        validatorIndex[_validator] = validatorList.length;
+        emit ValidatorAdded(_validator, _orgId);
+    }
+
+    function _getIndex(address _validator) internal view returns (uint256) {
+        require(validatorIndex[_validator] != 0, "validator does not exist");
+        return validatorIndex[_validator] - 1;
+    }
+}
@@ -13,6 +13,7 @@
 //go:generate solc --abi --bin -o . --overwrite ../PermissionsInterface.sol
 //go:generate solc --abi --bin -o . --overwrite ../PermissionsUpgradable.sol
 //go:generate solc --abi --bin -o . --overwrite ../RoleManager.sol
+//go:generate solc --abi --bin -o . --overwrite ../ValidatorManager.sol
 //go:generate solc --abi --bin -o . --overwrite ../VoterManager.sol
 
 //go:generate abigen -pkg permission -abi  ./AccountManager.abi            -bin  ./AccountManager.bin            -type AcctManager   -out ../../bind/accounts.go
@@ -22,6 +23,7 @@
 //go:generate abigen -pkg permission -abi  ./PermissionsInterface.abi      -bin  ./PermissionsInterface.bin      -type PermInterface -out ../../bind/permission_interface.go
 //go:generate abigen -pkg permission -abi  ./PermissionsUpgradable.abi     -bin  ./PermissionsUpgradable.bin     -type PermUpgr      -out ../../bind/permission_upgr.go
 //go:generate abigen -pkg permission -abi  ./RoleManager.abi               -bin  ./RoleManager.bin               -type RoleManager   -out ../../bind/roles.go
+//go:generate abigen -pkg permission -abi  ./ValidatorManager.abi          -bin  ./ValidatorManager.bin          -type ValidatorManager -out ../../bind/validator.go
 //go:generate abigen -pkg permission -abi  ./VoterManager.abi              -bin  ./VoterManager.bin              -type VoterManager  -out ../../bind/voter.go
 
 package gen
@@ -21,7 +21,10 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/istanbul"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
@@ -189,6 +192,7 @@ func (p *PermissionCtrl) AfterStart() error {
 		p.manageNodePermissions,    // monitor org  level node management events
 		p.manageRolePermissions,    // monitor org level role management events
 		p.manageAccountPermissions, // monitor org level account management events
+		p.manageEquivocations,      // deactivate the validators caught equivocating
 	} {
 		if err := f(); err != nil {
 			return err
@@ -451,6 +455,59 @@ func (p *PermissionCtrl) manageNodePermissions() error {
 	return nil
 }
 
+// Monitors the Istanbul validators caught equivocating and, if enabled in the
+// permission config, removes their nodes from the permissioned nodes
+func (p *PermissionCtrl) manageEquivocations() error {
+	if !p.permConfig.DeactivateEquivocatingNodes {
+		return nil
+	}
+	subscriber, ok := p.eth.Engine().(istanbul.EquivocationSubscriber)
+	if !ok {
+		log.Warn("deactivateEquivocatingNodes is only supported with Istanbul consensus")
+		return nil
+	}
+	chEquivocation := make(chan istanbul.EquivocationEvent, 1)
+	sub := subscriber.SubscribeEquivocationEvent(chEquivocation)
+
+	go func() {
+		defer sub.Unsubscribe()
+		stopChan, stopSubscription := p.subscribeStopEvent()
+		defer stopSubscription.Unsubscribe()
+		for {
+			select {
+			case evt := <-chEquivocation:
+				url := validatorNodeUrl(evt.Evidence.Validator, types.NodeInfoMap.GetNodeList())
+				if url == "" {
+					log.Warn("no permissioned node found for equivocating validator", "validator", evt.Evidence.Validator)
+					continue
+				}
+				log.Warn("deactivating node of equivocating validator", "validator", evt.Evidence.Validator, "enode", url)
+				p.updatePermissionedNodes(url, NodeDelete)
+
+			case <-stopChan:
+				log.Info("quit equivocation watch")
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// returns the url of the node signing with the key of the given Istanbul
+// validator, or an empty string if there is none
+func validatorNodeUrl(validator common.Address, nodes []types.NodeInfo) string {
+	for _, n := range nodes {
+		node, err := enode.ParseV4(n.Url)
+		if err != nil {
+			continue
+		}
+		if crypto.PubkeyToAddress(*node.Pubkey()) == validator {
+			return n.Url
+		}
+	}
+	return ""
+}
+
 // adds or deletes and entry from a given file
 func (p *PermissionCtrl) updateFile(fileName, enodeId string, operation NodeOperation, createFile bool) {
 	// Load the nodes from the config file
@@ -20,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/eth"
@@ -621,3 +622,16 @@ func TestParsePermissionConfig(t *testing.T) {
 	permConfig, err := ParsePermissionConfig(d)
 	assert.False(t, permConfig.IsEmpty(), "expected non empty object")
 }
+
+func TestValidatorNodeUrl(t *testing.T) {
+	node, err := enode.ParseV4(arbitraryNode2)
+	assert.NoError(t, err)
+	validator := crypto.PubkeyToAddress(*node.Pubkey())
+
+	nodes := []types.NodeInfo{
+		{OrgId: arbitraryNetworkAdminOrg, Url: arbitraryNode1, Status: types.NodeApproved},
+		{OrgId: arbitraryNetworkAdminOrg, Url: arbitraryNode2, Status: types.NodeApproved},
+	}
+	assert.Equal(t, arbitraryNode2, validatorNodeUrl(validator, nodes))
+	assert.Equal(t, "", validatorNodeUrl(common.Address{}, nodes))
+}
@@ -0,0 +1,257 @@
+// Package archive implements the encrypted archive carrying the private state of
+// an exported chain: the private state root and the decrypted private payloads
+// of each block, so that an import can rebuild the private state without a
+// transaction manager.
+package archive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// KeyLength is the length of the operator key encrypting an archive.
+const KeyLength = 32
+
+// archiveMagic identifies an archive stream, followed by its format version.
+const (
+	archiveMagic   = "quorum-private-archive"
+	archiveVersion = 1
+)
+
+var (
+	errInvalidArchive = errors.New("not a private state archive")
+	errTruncated      = errors.New("private state archive truncated")
+	errDecrypt        = errors.New("failed to decrypt private state archive, wrong key or corrupted archive")
+	errReadOnly       = errors.New("archived private payloads are read-only")
+	errNoRecipients   = errors.New("archived private payloads carry no recipients")
+)
+
+// Entry is the private state of a block.
+type Entry struct {
+	Number      uint64
+	Hash        common.Hash // Hash of the block
+	PrivateRoot common.Hash // Private state root after the block
+	Payloads    []Payload   // Private payloads of the block the node is party to
+}
+
+// Payload is a decrypted private payload, along with the transaction manager
+// hash the private transaction carries.
+type Payload struct {
+	Hash []byte
+	Data []byte
+}
+
+// header is the first item of an archive stream.
+type header struct {
+	Magic   string
+	Version uint
+}
+
+// record is an encrypted entry, an empty plaintext marking the end of the archive.
+type record struct {
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+// LoadKey reads a hex encoded operator key from the given file.
+func LoadKey(file string) ([]byte, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(blob)), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive key: %v", err)
+	}
+	if len(key) != KeyLength {
+		return nil, fmt.Errorf("invalid archive key length: have %d bytes, want %d", len(key), KeyLength)
+	}
+	return key, nil
+}
+
+// newAEAD creates the authenticated cipher of the given operator key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sequence returns the additional data authenticating the position of a record,
+// so that records can't be reordered, dropped or replayed.
+func sequence(n uint64) []byte {
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], n)
+	return seq[:]
+}
+
+// Writer encrypts entries into an archive stream.
+type Writer struct {
+	w    io.Writer
+	aead cipher.AEAD
+	seq  uint64
+}
+
+// NewWriter starts an archive stream encrypted with the given operator key.
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := rlp.Encode(w, &header{Magic: archiveMagic, Version: archiveVersion}); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, aead: aead}, nil
+}
+
+// Write appends the given entry to the archive.
+func (w *Writer) Write(entry *Entry) error {
+	blob, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		return err
+	}
+	return w.seal(blob)
+}
+
+// Close ends the archive, without closing the underlying writer. An archive
+// not closed is rejected as truncated.
+func (w *Writer) Close() error {
+	return w.seal(nil)
+}
+
+func (w *Writer) seal(plaintext []byte) error {
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	ciphertext := w.aead.Seal(nil, nonce, plaintext, sequence(w.seq))
+	w.seq++
+	return rlp.Encode(w.w, &record{Nonce: nonce, Ciphertext: ciphertext})
+}
+
+// Reader decrypts the entries of an archive stream.
+type Reader struct {
+	stream *rlp.Stream
+	aead   cipher.AEAD
+	seq    uint64
+	done   bool
+}
+
+// NewReader opens an archive stream encrypted with the given operator key.
+func NewReader(r io.Reader, key []byte) (*Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	stream := rlp.NewStream(r, 0)
+
+	var head header
+	if err := stream.Decode(&head); err != nil || head.Magic != archiveMagic {
+		return nil, errInvalidArchive
+	}
+	if head.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported private state archive version %d", head.Version)
+	}
+	return &Reader{stream: stream, aead: aead}, nil
+}
+
+// Next returns the next entry of the archive, or io.EOF at its end.
+func (r *Reader) Next() (*Entry, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	var rec record
+	if err := r.stream.Decode(&rec); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errTruncated
+		}
+		return nil, err
+	}
+	if len(rec.Nonce) != r.aead.NonceSize() {
+		return nil, errDecrypt
+	}
+	plaintext, err := r.aead.Open(nil, rec.Nonce, rec.Ciphertext, sequence(r.seq))
+	if err != nil {
+		return nil, errDecrypt
+	}
+	r.seq++
+	if len(plaintext) == 0 {
+		r.done = true
+		return nil, io.EOF
+	}
+	entry := new(Entry)
+	if err := rlp.DecodeBytes(plaintext, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Store serves archived private payloads in place of a transaction manager, the
+// payloads of the transactions the node wasn't party to being empty.
+type Store struct {
+	payloads map[string][]byte
+	lock     sync.RWMutex
+}
+
+// NewStore creates an empty payload store.
+func NewStore() *Store {
+	return &Store{payloads: make(map[string][]byte)}
+}
+
+// Add makes the payloads of the given entry available.
+func (s *Store) Add(entry *Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, payload := range entry.Payloads {
+		s.payloads[string(payload.Hash)] = payload.Data
+	}
+}
+
+// Reset drops all the payloads.
+func (s *Store) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.payloads = make(map[string][]byte)
+}
+
+// Receive returns the archived payload of the given transaction manager hash,
+// nil if the node wasn't party to the transaction.
+func (s *Store) Receive(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.payloads[string(data)], nil
+}
+
+// ReceiveFor is not supported, the archive not recording the recipients.
+func (s *Store) ReceiveFor(data []byte, to string) ([]byte, error) {
+	return nil, errNoRecipients
+}
+
+// Send is not supported by an archive.
+func (s *Store) Send(data []byte, from string, to []string) ([]byte, error) {
+	return nil, errReadOnly
+}
+
+// SendSignedTx is not supported by an archive.
+func (s *Store) SendSignedTx(data []byte, to []string) ([]byte, error) {
+	return nil, errReadOnly
+}
@@ -0,0 +1,148 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var testKey = bytes.Repeat([]byte{0x42}, KeyLength)
+
+func testEntries() []*Entry {
+	return []*Entry{
+		{Number: 0, Hash: common.Hash{0x01}},
+		{Number: 1, Hash: common.Hash{0x02}, PrivateRoot: common.Hash{0x03}, Payloads: []Payload{{Hash: []byte("hash a"), Data: []byte("payload a")}}},
+		{Number: 2, Hash: common.Hash{0x04}, PrivateRoot: common.Hash{0x05}, Payloads: []Payload{{Hash: []byte("hash b"), Data: []byte("payload b")}, {Hash: []byte("hash c"), Data: []byte("payload c")}}},
+	}
+}
+
+func writeArchive(t *testing.T, entries []*Entry, close bool) []byte {
+	buf := new(bytes.Buffer)
+	w, err := NewWriter(buf, testKey)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			t.Fatalf("failed to write entry %d: %v", entry.Number, err)
+		}
+	}
+	if close {
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close writer: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func readArchive(blob []byte, key []byte) ([]*Entry, error) {
+	r, err := NewReader(bytes.NewReader(blob), key)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for {
+		entry, err := r.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Tests that the entries written to an archive are read back.
+func TestArchiveRoundtrip(t *testing.T) {
+	blob := writeArchive(t, testEntries(), true)
+	if bytes.Contains(blob, []byte("payload a")) {
+		t.Fatalf("payload stored in the clear")
+	}
+	entries, err := readArchive(blob, testKey)
+	if err != nil {
+		t.Fatalf("failed to read archive: %v", err)
+	}
+	want := testEntries()
+	want[0].Payloads = []Payload{} // RLP decodes empty lists as such
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries mismatch:\nhave %+v\nwant %+v", entries, want)
+	}
+}
+
+// Tests that archives are rejected when decrypted with another key, truncated
+// or reordered.
+func TestArchiveIntegrity(t *testing.T) {
+	if _, err := readArchive(writeArchive(t, testEntries(), true), bytes.Repeat([]byte{0x24}, KeyLength)); err != errDecrypt {
+		t.Errorf("wrong key error mismatch: have %v, want %v", err, errDecrypt)
+	}
+	if _, err := readArchive(writeArchive(t, testEntries(), false), testKey); err != errTruncated {
+		t.Errorf("truncated archive error mismatch: have %v, want %v", err, errTruncated)
+	}
+	if _, err := readArchive([]byte("not an archive"), testKey); err != errInvalidArchive {
+		t.Errorf("invalid archive error mismatch: have %v, want %v", err, errInvalidArchive)
+	}
+	// Swap the first two records
+	blob := writeArchive(t, testEntries(), true)
+	stream := rlp.NewStream(bytes.NewReader(blob), 0)
+
+	var items [][]byte
+	for {
+		raw, err := stream.Raw()
+		if err != nil {
+			break
+		}
+		items = append(items, raw)
+	}
+	items[1], items[2] = items[2], items[1]
+	if _, err := readArchive(bytes.Join(items, nil), testKey); err != errDecrypt {
+		t.Errorf("reordered archive error mismatch: have %v, want %v", err, errDecrypt)
+	}
+}
+
+// Tests that the store serves the payloads of the entries added.
+func TestStore(t *testing.T) {
+	store := NewStore()
+	for _, entry := range testEntries() {
+		store.Add(entry)
+	}
+	if payload, err := store.Receive([]byte("hash b")); err != nil || string(payload) != "payload b" {
+		t.Errorf("payload mismatch: have %q, %v", payload, err)
+	}
+	if payload, err := store.Receive([]byte("unknown")); err != nil || payload != nil {
+		t.Errorf("unknown payload served: %q, %v", payload, err)
+	}
+	store.Reset()
+	if payload, _ := store.Receive([]byte("hash b")); payload != nil {
+		t.Errorf("payload served after reset: %q", payload)
+	}
+	if _, err := store.Send(nil, "", nil); err != errReadOnly {
+		t.Errorf("send error mismatch: have %v, want %v", err, errReadOnly)
+	}
+}
+
+// Tests that operator keys are loaded from hex files.
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "key")
+	ioutil.WriteFile(fn, []byte(common.Bytes2Hex(testKey)+"\n"), 0600)
+	if key, err := LoadKey(fn); err != nil || !bytes.Equal(key, testKey) {
+		t.Errorf("key mismatch: have %x, %v", key, err)
+	}
+	ioutil.WriteFile(fn, []byte("0x1234"), 0600)
+	if _, err := LoadKey(fn); err == nil {
+		t.Errorf("short key accepted")
+	}
+}
@@ -65,6 +65,24 @@ func (g *Constellation) Receive(data []byte) ([]byte, error) {
 	return pl, nil
 }
 
+func (g *Constellation) ReceiveFor(data []byte, to string) ([]byte, error) {
+	if g.isConstellationNotInUse {
+		return nil, nil
+	}
+	if len(data) == 0 {
+		return data, nil
+	}
+	// As with Receive, not being a recipient isn't an error
+	dataStr := to + "/" + string(data)
+	x, found := g.c.Get(dataStr)
+	if found {
+		return x.([]byte), nil
+	}
+	pl, _ := g.node.ReceivePayloadFor(data, to)
+	g.c.Set(dataStr, pl, cache.DefaultExpiration)
+	return pl, nil
+}
+
 func New(path string) (*Constellation, error) {
 	info, err := os.Lstat(path)
 	if err != nil {
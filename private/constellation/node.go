@@ -132,11 +132,20 @@ func (c *Client) SendSignedPayload(signedPayload []byte, b64To []string) ([]byte
 }
 
 func (c *Client) ReceivePayload(key []byte) ([]byte, error) {
+	return c.ReceivePayloadFor(key, "")
+}
+
+// ReceivePayloadFor retrieves the payload for the given recipient public key,
+// any local key if it is empty.
+func (c *Client) ReceivePayloadFor(key []byte, to string) ([]byte, error) {
 	req, err := http.NewRequest("GET", "http+unix://c/receiveraw", nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("c11n-key", base64.StdEncoding.EncodeToString(key))
+	if to != "" {
+		req.Header.Set("c11n-to", to)
+	}
 	res, err := c.httpClient.Do(req)
 
 	if res != nil {
@@ -10,6 +10,9 @@ type PrivateTransactionManager interface {
 	Send(data []byte, from string, to []string) ([]byte, error)
 	SendSignedTx(data []byte, to []string) ([]byte, error)
 	Receive(data []byte) ([]byte, error)
+	// ReceiveFor retrieves the payload only if the given enclave key is
+	// one of its recipients.
+	ReceiveFor(data []byte, to string) ([]byte, error)
 }
 
 func FromEnvironmentOrNil(name string) PrivateTransactionManager {
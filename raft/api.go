@@ -1,5 +1,7 @@
 package raft
 
+import "github.com/ethereum/go-ethereum/common"
+
 type RaftNodeInfo struct {
 	ClusterSize    int        `json:"clusterSize"`
 	Role           string     `json:"role"`
@@ -47,3 +49,9 @@ func (s *PublicRaftAPI) Cluster() []*Address {
 func (s *PublicRaftAPI) GetRaftId(enodeId string) (uint16, error) {
 	return s.raftService.raftProtocolManager.FetchRaftId(enodeId)
 }
+
+// TxStatus reports where a transaction submitted to this node stands on its way
+// to the raft leader and into a block.
+func (s *PublicRaftAPI) TxStatus(hash common.Hash) *TxStatus {
+	return s.raftService.forwarder.status(hash)
+}
@@ -2,6 +2,7 @@ package raft
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"sync"
 	"time"
 
@@ -34,11 +35,16 @@ type RaftService struct {
 	// we need an event mux to instantiate the blockchain
 	eventMux         *event.TypeMux
 	minter           *minter
+	forwarder        *txForwarder
 	nodeKey          *ecdsa.PrivateKey
 	calcGasLimitFunc func(block *types.Block) uint64
 }
 
-func New(ctx *node.ServiceContext, chainConfig *params.ChainConfig, raftId, raftPort uint16, joinExisting bool, blockTime time.Duration, e *eth.Ethereum, startPeers []*enode.Node, datadir string, useDns bool) (*RaftService, error) {
+func New(ctx *node.ServiceContext, chainConfig *params.ChainConfig, raftId, raftPort uint16, joinExisting bool, forceNewCluster bool, blockTime time.Duration, mintingPolicy MintingPolicy, e *eth.Ethereum, startPeers []*enode.Node, datadir string, useDns bool, tlsConfig *TLSConfig) (*RaftService, error) {
+	// Raft can only take over an Istanbul chain at the configured fork block
+	if chainConfig.Istanbul != nil && chainConfig.Istanbul.RaftBlock == nil {
+		return nil, errors.New("raft requires the raftBlock of the Istanbul genesis config to take over from Istanbul")
+	}
 	service := &RaftService{
 		eventMux:         ctx.EventMux,
 		chainDb:          e.ChainDb(),
@@ -51,12 +57,24 @@ func New(ctx *node.ServiceContext, chainConfig *params.ChainConfig, raftId, raft
 		calcGasLimitFunc: e.CalcGasLimit,
 	}
 
-	service.minter = newMinter(chainConfig, service, blockTime)
+	if mintingPolicy == nil {
+		mintingPolicy = NewFixedMintingPolicy(blockTime)
+	}
+	service.minter = newMinter(chainConfig, service, mintingPolicy)
 
 	var err error
-	if service.raftProtocolManager, err = NewProtocolManager(raftId, raftPort, service.blockchain, service.eventMux, startPeers, joinExisting, datadir, service.minter, service.downloader, useDns); err != nil {
+	if service.raftProtocolManager, err = NewProtocolManager(raftId, raftPort, service.blockchain, service.eventMux, startPeers, joinExisting, forceNewCluster, datadir, service.minter, service.downloader, useDns); err != nil {
 		return nil, err
 	}
+	if tlsConfig != nil && tlsConfig.Enabled {
+		pm := service.raftProtocolManager
+		if pm.tls, err = newRaftTLS(tlsConfig, service.nodeKey, pm.checkPeerIdentity, pm.checkDialedIdentity); err != nil {
+			pm.quorumRaftDb.Close()
+			return nil, err
+		}
+	}
+
+	service.forwarder = newTxForwarder(service)
 
 	return service, nil
 }
@@ -72,7 +90,9 @@ func (service *RaftService) TxPool() *core.TxPool              { return service.
 
 // node.Service interface methods:
 
-func (service *RaftService) Protocols() []p2p.Protocol { return []p2p.Protocol{} }
+func (service *RaftService) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{service.forwarder.protocol()}
+}
 func (service *RaftService) APIs() []rpc.API {
 	return []rpc.API{
 		{
@@ -88,12 +108,14 @@ func (service *RaftService) APIs() []rpc.API {
 // of the protocol.
 func (service *RaftService) Start(p2pServer *p2p.Server) error {
 	service.raftProtocolManager.Start(p2pServer)
+	service.forwarder.start()
 	return nil
 }
 
 // Stop implements node.Service, stopping the background data propagation thread
 // of the protocol.
 func (service *RaftService) Stop() error {
+	service.forwarder.stop()
 	service.blockchain.Stop()
 	service.raftProtocolManager.Stop()
 	service.minter.stop()
@@ -10,6 +10,15 @@ const (
 
 	raftMsg = 0x00
 
+	// Sub-protocol forwarding transactions from followers to the leader
+	forwardProtocolName              = "raftfwd"
+	forwardProtocolVersion    uint   = 1
+	forwardProtocolLength     uint64 = 2
+	forwardProtocolMaxMsgSize        = 10 * 1024 * 1024
+
+	forwardTxMsg  = 0x00
+	forwardAckMsg = 0x01
+
 	minterRole   = etcdRaft.LEADER
 	verifierRole = etcdRaft.NOT_LEADER
 
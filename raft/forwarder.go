@@ -0,0 +1,401 @@
+package raft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// Statuses of a transaction submitted to this node, as reported by raft_txStatus.
+const (
+	TxStatusUnknown      = "unknown"      // Neither known to the pool nor the chain
+	TxStatusPending      = "pending"      // In the local pool, but not forwarded to a leader
+	TxStatusQueued       = "queued"       // Waiting for a leader to forward to
+	TxStatusForwarded    = "forwarded"    // Sent to the leader, awaiting acknowledgement
+	TxStatusAcknowledged = "acknowledged" // Accepted into the leader's transaction pool
+	TxStatusRejected     = "rejected"     // Refused by the leader
+	TxStatusIncluded     = "included"     // Included in a block
+)
+
+const (
+	forwardRetryInterval = time.Second      // Interval to retry queued and unacknowledged transactions
+	forwardAckTimeout    = 5 * time.Second  // Time to wait for an acknowledgement before resubmitting
+	forwardRejectedTTL   = 10 * time.Minute // Time to report a rejection before forgetting the transaction
+	forwardAckedTTL      = 10 * time.Minute // Time to wait for an acknowledged transaction to be minted before forgetting it
+	maxForwardedTxs      = 4096             // Maximum number of transactions tracked at once
+)
+
+// forwardAck is sent by the leader for every forwarded transaction. An empty
+// Error denotes that the transaction was accepted into its pool.
+type forwardAck struct {
+	Hash  common.Hash
+	Error string
+}
+
+// TxStatus describes where a transaction submitted to this node stands.
+type TxStatus struct {
+	Status      string          `json:"status"`
+	Leader      uint16          `json:"leader,omitempty"`   // Raft ID of the leader the tx was last forwarded to
+	Attempts    int             `json:"attempts,omitempty"` // Number of times the tx was forwarded
+	Error       string          `json:"error,omitempty"`    // Reason given by the leader for rejecting the tx
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+}
+
+type forwardedTx struct {
+	tx       *types.Transaction
+	status   string
+	leader   uint16
+	attempts int
+	err      string
+	updated  time.Time
+}
+
+// txForwarder forwards transactions submitted to a follower directly to the raft
+// leader over a dedicated sub-protocol, rather than relying on eth gossip alone.
+// Forwarded transactions are resubmitted until the leader acknowledges them, and
+// again whenever the leadership changes, until they are included in a block.
+type txForwarder struct {
+	service *RaftService
+
+	mu     sync.Mutex
+	txs    map[common.Hash]*forwardedTx
+	peers  map[enode.ID]p2p.MsgReadWriter
+	leader uint16 // Raft ID of the leader transactions were last forwarded to
+
+	txsCh        chan core.NewTxsEvent
+	txsSub       event.Subscription
+	chainHeadCh  chan core.ChainHeadEvent
+	chainHeadSub event.Subscription
+	quit         chan struct{}
+}
+
+func newTxForwarder(service *RaftService) *txForwarder {
+	return &txForwarder{
+		service:     service,
+		txs:         make(map[common.Hash]*forwardedTx),
+		peers:       make(map[enode.ID]p2p.MsgReadWriter),
+		txsCh:       make(chan core.NewTxsEvent, 4096),
+		chainHeadCh: make(chan core.ChainHeadEvent, core.GetChainHeadChannleSize()),
+		quit:        make(chan struct{}),
+	}
+}
+
+func (f *txForwarder) start() {
+	f.txsSub = f.service.TxPool().SubscribeNewTxsEvent(f.txsCh)
+	f.chainHeadSub = f.service.BlockChain().SubscribeChainHeadEvent(f.chainHeadCh)
+
+	go f.loop()
+}
+
+func (f *txForwarder) stop() {
+	close(f.quit)
+}
+
+func (f *txForwarder) protocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    forwardProtocolName,
+		Version: forwardProtocolVersion,
+		Length:  forwardProtocolLength,
+		Run:     f.runPeer,
+	}
+}
+
+func (f *txForwarder) loop() {
+	defer f.txsSub.Unsubscribe()
+	defer f.chainHeadSub.Unsubscribe()
+
+	ticker := time.NewTicker(forwardRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-f.txsCh:
+			f.track(ev.Txs)
+			f.flush()
+
+		case ev := <-f.chainHeadCh:
+			f.mu.Lock()
+			for _, tx := range ev.Block.Transactions() {
+				delete(f.txs, tx.Hash())
+			}
+			f.mu.Unlock()
+
+		case <-ticker.C:
+			f.expire()
+			f.reconcile(func(hash common.Hash) bool { return f.service.TxPool().Get(hash) != nil })
+			f.flush()
+
+		case <-f.txsSub.Err():
+			return
+		case <-f.chainHeadSub.Err():
+			return
+		case <-f.quit:
+			return
+		}
+	}
+}
+
+// track starts tracking the locally submitted transactions among txs.
+func (f *txForwarder) track(txs []*types.Transaction) {
+	pool, chain := f.service.TxPool(), f.service.BlockChain()
+
+	locals := make(map[common.Address]bool)
+	for _, addr := range pool.Locals() {
+		locals[addr] = true
+	}
+	if len(locals) == 0 {
+		return
+	}
+	signer := types.MakeSigner(chain.Config(), chain.CurrentBlock().Number())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, tx := range txs {
+		if len(f.txs) >= maxForwardedTxs {
+			log.Warn("Too many transactions awaiting forwarding, relying on gossip", "hash", tx.Hash())
+			continue
+		}
+		if from, err := types.Sender(signer, tx); err != nil || !locals[from] {
+			continue
+		}
+		if _, ok := f.txs[tx.Hash()]; !ok {
+			f.txs[tx.Hash()] = &forwardedTx{tx: tx, status: TxStatusQueued, updated: time.Now()}
+		}
+	}
+}
+
+// requeue marks every tracked transaction for resubmission, as a new leader
+// does not necessarily know about the ones acknowledged by its predecessor.
+// The caller must hold f.mu.
+func (f *txForwarder) requeue() {
+	for _, ftx := range f.txs {
+		if ftx.status != TxStatusRejected {
+			ftx.status = TxStatusQueued
+		}
+	}
+}
+
+// expire requeues transactions which were not acknowledged in time, and forgets
+// rejected ones, as well as acknowledged ones the leader never minted, after a
+// while.
+func (f *txForwarder) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for hash, ftx := range f.txs {
+		switch {
+		case ftx.status == TxStatusForwarded && time.Since(ftx.updated) > forwardAckTimeout:
+			ftx.status = TxStatusQueued
+		case ftx.status == TxStatusRejected && time.Since(ftx.updated) > forwardRejectedTTL:
+			delete(f.txs, hash)
+		case ftx.status == TxStatusAcknowledged && time.Since(ftx.updated) > forwardAckedTTL:
+			delete(f.txs, hash)
+		}
+	}
+}
+
+// reconcile forgets the tracked transactions which are no longer pending in the
+// local pool, as they were dropped, replaced or included in a block. Rejected
+// transactions are kept until they expire, to report the rejection.
+func (f *txForwarder) reconcile(pending func(common.Hash) bool) {
+	f.mu.Lock()
+	hashes := make([]common.Hash, 0, len(f.txs))
+	for hash, ftx := range f.txs {
+		if ftx.status != TxStatusRejected {
+			hashes = append(hashes, hash)
+		}
+	}
+	f.mu.Unlock()
+
+	// Query the pool without holding the lock, the tracked transactions left
+	// untouched in the meantime being reconciled on the next round
+	var gone []common.Hash
+	for _, hash := range hashes {
+		if !pending(hash) {
+			gone = append(gone, hash)
+		}
+	}
+	f.mu.Lock()
+	for _, hash := range gone {
+		if ftx := f.txs[hash]; ftx != nil && ftx.status != TxStatusRejected {
+			delete(f.txs, hash)
+		}
+	}
+	f.mu.Unlock()
+}
+
+// flush forwards all queued transactions to the current leader, resubmitting
+// every tracked one if the leadership changed since the last flush. If this
+// node is the leader it mints them itself, so they are no longer tracked.
+func (f *txForwarder) flush() {
+	leaderId, leaderNode, err := f.service.raftProtocolManager.leaderNode()
+	if err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	if leaderNode == nil {
+		f.txs = make(map[common.Hash]*forwardedTx)
+		f.leader = leaderId
+		f.mu.Unlock()
+		return
+	}
+	if leaderId != f.leader {
+		log.Debug("Raft leader changed, resubmitting forwarded transactions", "leader", leaderId, "count", len(f.txs))
+		f.requeue()
+		f.leader = leaderId
+	}
+	rw := f.peers[leaderNode.ID()]
+	if rw == nil {
+		f.mu.Unlock()
+		return
+	}
+	var (
+		txs []*types.Transaction
+		now = time.Now()
+	)
+	for _, ftx := range f.txs {
+		if ftx.status != TxStatusQueued {
+			continue
+		}
+		txs = append(txs, ftx.tx)
+		ftx.status, ftx.leader, ftx.updated = TxStatusForwarded, leaderId, now
+		ftx.attempts++
+	}
+	f.mu.Unlock()
+
+	if len(txs) == 0 {
+		return
+	}
+	// Transactions which failed to send are retried once the ack timeout expires.
+	if err := p2p.Send(rw, forwardTxMsg, txs); err != nil {
+		log.Debug("Failed to forward transactions to raft leader", "leader", leaderId, "count", len(txs), "err", err)
+	}
+}
+
+// runPeer serves the forwarding protocol for a connected peer: as a leader it
+// adds forwarded transactions to the pool, as a follower it processes the
+// leader's acknowledgements.
+func (f *txForwarder) runPeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	id := p.ID()
+
+	f.mu.Lock()
+	f.peers[id] = rw
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.peers, id)
+		f.mu.Unlock()
+	}()
+
+	// Forward anything that was waiting for the leader to connect.
+	go f.flush()
+
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		if msg.Size > forwardProtocolMaxMsgSize {
+			msg.Discard()
+			return fmt.Errorf("message too large: %v > %v", msg.Size, forwardProtocolMaxMsgSize)
+		}
+		switch msg.Code {
+		case forwardTxMsg:
+			var txs []*types.Transaction
+			if err := msg.Decode(&txs); err != nil {
+				return fmt.Errorf("invalid forwarded transactions: %v", err)
+			}
+			if err := p2p.Send(rw, forwardAckMsg, f.accept(txs)); err != nil {
+				return err
+			}
+
+		case forwardAckMsg:
+			var acks []forwardAck
+			if err := msg.Decode(&acks); err != nil {
+				return fmt.Errorf("invalid forwarding acknowledgements: %v", err)
+			}
+			f.acknowledge(acks)
+
+		default:
+			msg.Discard()
+			return fmt.Errorf("invalid message code: %v", msg.Code)
+		}
+	}
+}
+
+// accept adds forwarded transactions to the local pool, returning the
+// acknowledgement for each of them.
+func (f *txForwarder) accept(txs []*types.Transaction) []forwardAck {
+	pool := f.service.TxPool()
+
+	acks := make([]forwardAck, len(txs))
+	for i, tx := range txs {
+		acks[i].Hash = tx.Hash()
+		if pool.Get(tx.Hash()) != nil {
+			continue
+		}
+		if err := pool.AddRemote(tx); err != nil {
+			acks[i].Error = err.Error()
+		}
+	}
+	return acks
+}
+
+func (f *txForwarder) acknowledge(acks []forwardAck) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	for _, ack := range acks {
+		ftx := f.txs[ack.Hash]
+		if ftx == nil || ftx.status != TxStatusForwarded {
+			continue
+		}
+		if ack.Error == "" {
+			ftx.status = TxStatusAcknowledged
+		} else {
+			ftx.status, ftx.err = TxStatusRejected, ack.Error
+			log.Warn("Raft leader rejected forwarded transaction", "hash", ack.Hash, "leader", ftx.leader, "err", ack.Error)
+		}
+		ftx.updated = now
+	}
+}
+
+// status reports where a transaction submitted to this node stands.
+func (f *txForwarder) status(hash common.Hash) *TxStatus {
+	if blockHash, blockNumber, _ := rawdb.ReadTxLookupEntry(f.service.ChainDb(), hash); blockHash != (common.Hash{}) {
+		number := hexutil.Uint64(blockNumber)
+		return &TxStatus{Status: TxStatusIncluded, BlockHash: &blockHash, BlockNumber: &number}
+	}
+
+	f.mu.Lock()
+	ftx := f.txs[hash]
+	var status *TxStatus
+	if ftx != nil {
+		status = &TxStatus{Status: ftx.status, Leader: ftx.leader, Attempts: ftx.attempts, Error: ftx.err}
+	}
+	f.mu.Unlock()
+
+	switch {
+	case status != nil:
+		return status
+	case f.service.TxPool().Get(hash) != nil:
+		return &TxStatus{Status: TxStatusPending}
+	default:
+		return &TxStatus{Status: TxStatusUnknown}
+	}
+}
@@ -0,0 +1,92 @@
+package raft
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestForwarderAcknowledgements(t *testing.T) {
+	// Without an elected leader nothing is forwarded, so only acks are processed.
+	f := &txForwarder{
+		service: &RaftService{raftProtocolManager: &ProtocolManager{peers: make(map[uint16]*Peer)}},
+		txs:     make(map[common.Hash]*forwardedTx),
+		peers:   make(map[enode.ID]p2p.MsgReadWriter),
+	}
+
+	var txs []*types.Transaction
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+		txs = append(txs, tx)
+		f.txs[tx.Hash()] = &forwardedTx{tx: tx, status: TxStatusForwarded, leader: 1, attempts: 1, updated: time.Now()}
+	}
+	// The third transaction was forwarded long ago and never acknowledged.
+	f.txs[txs[2].Hash()].updated = time.Now().Add(-2 * forwardAckTimeout)
+
+	// Feed the acknowledgements through the protocol handler.
+	local, remote := p2p.MsgPipe()
+	defer remote.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- f.runPeer(p2p.NewPeer(enode.ID{1}, "leader", nil), local) }()
+
+	acks := []forwardAck{{Hash: txs[0].Hash()}, {Hash: txs[1].Hash(), Error: "nonce too low"}}
+	if err := p2p.Send(remote, forwardAckMsg, acks); err != nil {
+		t.Fatalf("failed to send acknowledgements: %v", err)
+	}
+	local.Close()
+	<-done
+
+	f.expire()
+
+	for i, want := range []string{TxStatusAcknowledged, TxStatusRejected, TxStatusQueued} {
+		if have := f.txs[txs[i].Hash()].status; have != want {
+			t.Errorf("tx %d: status mismatch: have %s, want %s", i, have, want)
+		}
+	}
+	if have := f.txs[txs[1].Hash()].err; have != "nonce too low" {
+		t.Errorf("rejection reason mismatch: have %q", have)
+	}
+
+	// A leadership change resubmits everything but rejected transactions.
+	f.mu.Lock()
+	f.requeue()
+	f.mu.Unlock()
+	for i, want := range []string{TxStatusQueued, TxStatusRejected, TxStatusQueued} {
+		if have := f.txs[txs[i].Hash()].status; have != want {
+			t.Errorf("tx %d: status after leader change mismatch: have %s, want %s", i, have, want)
+		}
+	}
+}
+
+func TestForwarderForgetsStaleTransactions(t *testing.T) {
+	f := &txForwarder{txs: make(map[common.Hash]*forwardedTx)}
+
+	var txs []*types.Transaction
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(0), nil)
+		txs = append(txs, tx)
+		f.txs[tx.Hash()] = &forwardedTx{tx: tx, status: TxStatusAcknowledged, leader: 1, attempts: 1, updated: time.Now()}
+	}
+	// The first transaction was acknowledged long ago but never minted.
+	f.txs[txs[0].Hash()].updated = time.Now().Add(-2 * forwardAckedTTL)
+	// The second and third ones were dropped from the local pool, the third one
+	// after being rejected.
+	f.txs[txs[2].Hash()].status = TxStatusRejected
+	// The fourth one is still queued, the fifth one acknowledged.
+	f.txs[txs[3].Hash()].status = TxStatusQueued
+
+	f.expire()
+	f.reconcile(func(hash common.Hash) bool { return hash != txs[1].Hash() && hash != txs[2].Hash() })
+
+	for i, tracked := range []bool{false, false, true, true, true} {
+		if _, ok := f.txs[txs[i].Hash()]; ok != tracked {
+			t.Errorf("tx %d: tracked mismatch: have %v, want %v", i, ok, tracked)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package raft
 
 import (
+	"crypto/ecdsa"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -40,10 +42,11 @@ type ProtocolManager struct {
 	stopped  bool
 
 	// Static configuration
-	joinExisting   bool // Whether to join an existing cluster when a WAL doesn't already exist
-	bootstrapNodes []*enode.Node
-	raftId         uint16
-	raftPort       uint16
+	joinExisting    bool // Whether to join an existing cluster when a WAL doesn't already exist
+	forceNewCluster bool // Whether to restart from an existing WAL as the sole member of a new cluster
+	bootstrapNodes  []*enode.Node
+	raftId          uint16
+	raftPort        uint16
 
 	// Local peer state (protected by mu vs concurrent access via JS)
 	address       *Address
@@ -76,6 +79,7 @@ type ProtocolManager struct {
 	// Raft transport
 	unsafeRawNode etcdRaft.Node
 	transport     *rafthttp.Transport
+	tls           *raftTLS // nil unless the raft transport is secured with TLS
 	httpstopc     chan struct{}
 	httpdonec     chan struct{}
 
@@ -97,10 +101,8 @@ type ProtocolManager struct {
 // Public interface
 //
 
-func NewProtocolManager(raftId uint16, raftPort uint16, blockchain *core.BlockChain, mux *event.TypeMux, bootstrapNodes []*enode.Node, joinExisting bool, datadir string, minter *minter, downloader *downloader.Downloader, useDns bool) (*ProtocolManager, error) {
-	waldir := fmt.Sprintf("%s/raft-wal", datadir)
-	snapdir := fmt.Sprintf("%s/raft-snap", datadir)
-	quorumRaftDbLoc := fmt.Sprintf("%s/quorum-raft-state", datadir)
+func NewProtocolManager(raftId uint16, raftPort uint16, blockchain *core.BlockChain, mux *event.TypeMux, bootstrapNodes []*enode.Node, joinExisting bool, forceNewCluster bool, datadir string, minter *minter, downloader *downloader.Downloader, useDns bool) (*ProtocolManager, error) {
+	waldir, snapdir, quorumRaftDbLoc := raftDirs(datadir)
 
 	manager := &ProtocolManager{
 		bootstrapNodes:      bootstrapNodes,
@@ -108,6 +110,7 @@ func NewProtocolManager(raftId uint16, raftPort uint16, blockchain *core.BlockCh
 		leader:              uint16(etcdRaft.None),
 		removedPeers:        mapset.NewSet(),
 		joinExisting:        joinExisting,
+		forceNewCluster:     forceNewCluster,
 		blockchain:          blockchain,
 		eventMux:            mux,
 		blockProposalC:      make(chan *types.Block, 10),
@@ -408,6 +411,9 @@ func (pm *ProtocolManager) startRaft() {
 		}
 	}
 	walExisted := wal.Exist(pm.waldir)
+	if pm.forceNewCluster && !walExisted {
+		fatalf("forcing a new raft cluster requires existing raft state in %s", pm.waldir)
+	}
 	lastAppliedIndex := pm.loadAppliedIndex()
 
 	ss := &stats.ServerStats{}
@@ -420,6 +426,9 @@ func (pm *ProtocolManager) startRaft() {
 		LeaderStats: stats.NewLeaderStats(strconv.Itoa(int(pm.raftId))),
 		ErrorC:      make(chan error),
 	}
+	if pm.tls != nil {
+		pm.transport.ConfigureClientTransport = pm.tls.configureTransport
+	}
 	pm.transport.Start()
 
 	// We load the snapshot to connect to prev peers before replaying the WAL,
@@ -585,10 +594,14 @@ func (pm *ProtocolManager) serveRaft() {
 		fatalf("Failed parsing URL (%v)", err)
 	}
 
-	listener, err := newStoppableListener(url.Host, pm.httpstopc)
+	stoppable, err := newStoppableListener(url.Host, pm.httpstopc)
 	if err != nil {
 		fatalf("Failed to listen rafthttp (%v)", err)
 	}
+	var listener net.Listener = stoppable
+	if pm.tls != nil {
+		listener = tls.NewListener(stoppable, pm.tls.serverConfig())
+	}
 	err = (&http.Server{Handler: pm.transport.Handler()}).Serve(listener)
 	select {
 	case <-pm.httpstopc:
@@ -701,20 +714,80 @@ func (pm *ProtocolManager) entriesToApply(allEntries []raftpb.Entry) (entriesToA
 }
 
 func (pm *ProtocolManager) raftUrl(address *Address) string {
+	scheme := "http"
+	if pm.tls != nil {
+		scheme = "https"
+	}
+
 	if !pm.useDns {
 		parsedIp := net.ParseIP(address.Hostname)
-		return fmt.Sprintf("http://%s:%d", parsedIp.To4(), address.RaftPort)
+		return fmt.Sprintf("%s://%s:%d", scheme, parsedIp.To4(), address.RaftPort)
 	}
 
 	if parsedIp := net.ParseIP(address.Hostname); parsedIp != nil {
 		if ipv4 := parsedIp.To4(); ipv4 != nil {
 			//this is an IPv4 address
-			return fmt.Sprintf("http://%s:%d", ipv4, address.RaftPort)
+			return fmt.Sprintf("%s://%s:%d", scheme, ipv4, address.RaftPort)
 		}
 		//this is an IPv6 address
-		return fmt.Sprintf("http://[%s]:%d", parsedIp, address.RaftPort)
+		return fmt.Sprintf("%s://[%s]:%d", scheme, parsedIp, address.RaftPort)
 	}
-	return fmt.Sprintf("http://%s:%d", address.Hostname, address.RaftPort)
+	return fmt.Sprintf("%s://%s:%d", scheme, address.Hostname, address.RaftPort)
+}
+
+// checkPeerIdentity verifies that the node owning a raft TLS certificate is a
+// member of the cluster. A node joining an existing cluster does not know the
+// membership until it receives a snapshot, so until then it accepts the nodes
+// it is connected to over p2p, which are subject to the usual p2p permissioning.
+func (pm *ProtocolManager) checkPeerIdentity(pub *ecdsa.PublicKey) error {
+	id := enode.PubkeyToIDV4(pub)
+
+	pm.mu.RLock()
+	for _, peer := range pm.peers {
+		if peer.p2pNode.ID() == id {
+			pm.mu.RUnlock()
+			return nil
+		}
+	}
+	joining := len(pm.peers) == 0
+	p2pServer := pm.p2pServer
+	pm.mu.RUnlock()
+
+	if joining && p2pServer != nil {
+		for _, peer := range p2pServer.Peers() {
+			if peer.ID() == id {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("raft peer %x is not a member of the cluster", id[:8])
+}
+
+// checkDialedIdentity verifies that the node owning a raft TLS certificate is the
+// cluster member whose raft transport listens at the dialed address. Addresses
+// of no known member, as the ones dialed while joining a cluster, fall back to
+// the membership check.
+func (pm *ProtocolManager) checkDialedIdentity(addr string, pub *ecdsa.PublicKey) error {
+	id := enode.PubkeyToIDV4(pub)
+
+	pm.mu.RLock()
+	var expected []enode.ID
+	for _, peer := range pm.peers {
+		if u, err := url.Parse(pm.raftUrl(peer.address)); err == nil && u.Host == addr {
+			expected = append(expected, peer.p2pNode.ID())
+		}
+	}
+	pm.mu.RUnlock()
+
+	if len(expected) == 0 {
+		return pm.checkPeerIdentity(pub)
+	}
+	for _, want := range expected {
+		if id == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("raft peer at %s is %x, expected %x", addr, id[:8], expected[0][:8])
 }
 
 func (pm *ProtocolManager) addPeer(address *Address) {
@@ -1011,6 +1084,20 @@ func (pm *ProtocolManager) LeaderAddress() (*Address, error) {
 	return nil, errors.New("no leader is currently elected")
 }
 
+// leaderNode returns the raft ID and p2p node of the current leader. The node
+// is nil if this node is the leader.
+func (pm *ProtocolManager) leaderNode() (uint16, *enode.Node, error) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	if minterRole == pm.role {
+		return pm.raftId, nil, nil
+	} else if l, ok := pm.peers[pm.leader]; ok {
+		return pm.leader, l.p2pNode, nil
+	}
+	return 0, nil, errors.New("no leader is currently elected")
+}
+
 // Returns the raft id for a given enodeId
 func (pm *ProtocolManager) FetchRaftId(enodeId string) (uint16, error) {
 	node, err := enode.ParseV4(enodeId)
@@ -0,0 +1,416 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/coreos/etcd/pkg/fileutil"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/snap"
+	"github.com/coreos/etcd/wal"
+	"github.com/coreos/etcd/wal/walpb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// RaftState is an offline view of the raft state a node persisted under its
+// data directory: the latest snapshot, the WAL entries following it and the
+// last-applied index. It is meant for inspection and repair tooling, and must
+// only be used while the node is stopped.
+type RaftState struct {
+	Snapshot     *raftpb.Snapshot       // Latest raft snapshot, nil if none was taken yet
+	SnapshotData *SnapshotWithHostnames // Decoded membership and head stored in Snapshot
+	HardState    raftpb.HardState       // Term, vote and durably committed index from the WAL
+	Entries      []raftpb.Entry         // WAL entries following the snapshot
+	AppliedIndex uint64                 // Last-applied index from the quorum raft database
+
+	datadir string
+}
+
+// EntryInfo is a human readable summary of a single raft log entry.
+type EntryInfo struct {
+	Index       uint64       `json:"index"`
+	Term        uint64       `json:"term"`
+	Type        string       `json:"type"`
+	BlockNumber uint64       `json:"blockNumber,omitempty"`
+	BlockHash   *common.Hash `json:"blockHash,omitempty"`
+	ParentHash  *common.Hash `json:"parentHash,omitempty"`
+	ConfChange  string       `json:"confChange,omitempty"`
+	RaftId      uint16       `json:"raftId,omitempty"`
+}
+
+// StateIssue is an inconsistency found by RaftState.Verify. Critical issues
+// prevent the node from restarting correctly, the others are resolved by the
+// node itself on restart.
+type StateIssue struct {
+	Critical bool
+	Message  string
+}
+
+func (issue StateIssue) String() string {
+	if issue.Critical {
+		return "ERROR: " + issue.Message
+	}
+	return "WARN:  " + issue.Message
+}
+
+// raftDirs returns the locations of the raft WAL, snapshots and quorum raft
+// database under datadir.
+func raftDirs(datadir string) (waldir, snapdir, quorumRaftDbLoc string) {
+	return fmt.Sprintf("%s/raft-wal", datadir), fmt.Sprintf("%s/raft-snap", datadir), fmt.Sprintf("%s/quorum-raft-state", datadir)
+}
+
+// ReadRaftState loads the raft state persisted under datadir.
+func ReadRaftState(datadir string) (*RaftState, error) {
+	waldir, snapdir, quorumRaftDbLoc := raftDirs(datadir)
+	if !wal.Exist(waldir) {
+		return nil, fmt.Errorf("no raft WAL found in %s", waldir)
+	}
+	state := &RaftState{datadir: datadir}
+
+	if fileutil.Exist(snapdir) {
+		snapshot, err := snap.New(snapdir).Load()
+		if err != nil && err != snap.ErrNoSnapshot {
+			return nil, fmt.Errorf("failed to load raft snapshot: %v", err)
+		}
+		if snapshot != nil {
+			state.Snapshot, state.SnapshotData = snapshot, bytesToSnapshot(snapshot.Data)
+		}
+	}
+
+	hardState, entries, err := readWAL(waldir, state.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raft WAL: %v", err)
+	}
+	state.HardState, state.Entries = hardState, entries
+
+	db, err := openQuorumRaftDb(quorumRaftDbLoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s (is the node still running?): %v", quorumRaftDbLoc, err)
+	}
+	defer db.Close()
+
+	if state.AppliedIndex, err = readAppliedIndex(db); err != nil {
+		return nil, fmt.Errorf("failed to read applied index: %v", err)
+	}
+	return state, nil
+}
+
+// SnapshotIndex returns the index of the latest snapshot, or zero if there is
+// none.
+func (s *RaftState) SnapshotIndex() uint64 {
+	if s.Snapshot == nil {
+		return 0
+	}
+	return s.Snapshot.Metadata.Index
+}
+
+// LastIndex returns the index of the last entry in the WAL, or the snapshot
+// index if the WAL holds no entries after it.
+func (s *RaftState) LastIndex() uint64 {
+	if len(s.Entries) == 0 {
+		return s.SnapshotIndex()
+	}
+	return s.Entries[len(s.Entries)-1].Index
+}
+
+// DescribeEntries summarises the WAL entries following the snapshot.
+func (s *RaftState) DescribeEntries() []EntryInfo {
+	infos := make([]EntryInfo, len(s.Entries))
+	for i, entry := range s.Entries {
+		info := EntryInfo{Index: entry.Index, Term: entry.Term}
+
+		switch entry.Type {
+		case raftpb.EntryNormal:
+			if len(entry.Data) == 0 {
+				info.Type = "empty"
+				break
+			}
+			var block types.Block
+			if err := rlp.DecodeBytes(entry.Data, &block); err != nil {
+				info.Type = "invalid"
+				break
+			}
+			hash, parent := block.Hash(), block.ParentHash()
+			info.Type, info.BlockNumber, info.BlockHash, info.ParentHash = "block", block.NumberU64(), &hash, &parent
+
+		case raftpb.EntryConfChange:
+			var cc raftpb.ConfChange
+			if err := cc.Unmarshal(entry.Data); err != nil {
+				info.Type = "invalid"
+				break
+			}
+			info.Type, info.ConfChange, info.RaftId = "confChange", cc.Type.String(), uint16(cc.NodeID)
+		}
+		infos[i] = info
+	}
+	return infos
+}
+
+// Membership replays the conf changes in the WAL on top of the snapshot,
+// returning the cluster members and permanently removed raft IDs as of the
+// given index. This mirrors the conf change handling in the raft event loop.
+func (s *RaftState) Membership(index uint64) ([]Address, []uint16) {
+	members := make(map[uint16]Address)
+	removed := make(map[uint16]bool)
+	if s.SnapshotData != nil {
+		for _, address := range s.SnapshotData.Addresses {
+			members[address.RaftId] = address
+		}
+		for _, raftId := range s.SnapshotData.RemovedRaftIds {
+			removed[raftId] = true
+		}
+	}
+	for _, entry := range s.Entries {
+		if entry.Index > index {
+			break
+		}
+		if entry.Type != raftpb.EntryConfChange {
+			continue
+		}
+		var cc raftpb.ConfChange
+		if err := cc.Unmarshal(entry.Data); err != nil {
+			continue
+		}
+		raftId := uint16(cc.NodeID)
+
+		switch cc.Type {
+		case raftpb.ConfChangeAddNode:
+			if _, ok := members[raftId]; !ok && !removed[raftId] && len(cc.Context) > 0 {
+				members[raftId] = *bytesToAddress(cc.Context)
+			}
+		case raftpb.ConfChangeRemoveNode:
+			if !removed[raftId] {
+				delete(members, raftId)
+				removed[raftId] = true
+			}
+		}
+	}
+
+	addresses := make([]Address, 0, len(members))
+	for _, address := range members {
+		addresses = append(addresses, address)
+	}
+	sort.Sort(ByRaftId(addresses))
+
+	removedRaftIds := make([]uint16, 0, len(removed))
+	for raftId := range removed {
+		removedRaftIds = append(removedRaftIds, raftId)
+	}
+	sort.Slice(removedRaftIds, func(i, j int) bool { return removedRaftIds[i] < removedRaftIds[j] })
+
+	return addresses, removedRaftIds
+}
+
+// headAt replays the block entries up to and including index on top of start,
+// returning the chain head they produce. As in the raft event loop, only blocks
+// extending the current head are applied; the rest are no-ops.
+func (s *RaftState) headAt(start common.Hash, index uint64, visit func(entry raftpb.Entry, block *types.Block)) common.Hash {
+	head := start
+	for _, entry := range s.Entries {
+		if entry.Index > index {
+			break
+		}
+		if entry.Type != raftpb.EntryNormal || len(entry.Data) == 0 {
+			continue
+		}
+		var block types.Block
+		if err := rlp.DecodeBytes(entry.Data, &block); err != nil {
+			continue
+		}
+		if block.ParentHash() != head {
+			continue
+		}
+		if visit != nil {
+			visit(entry, &block)
+		}
+		head = block.Hash()
+	}
+	return head
+}
+
+// baseHead returns the chain head as of the snapshot, or the genesis block if
+// no snapshot was taken yet.
+func (s *RaftState) baseHead(chain *core.BlockChain) common.Hash {
+	if s.SnapshotData != nil {
+		return s.SnapshotData.HeadBlockHash
+	}
+	return chain.Genesis().Hash()
+}
+
+// ChainHeadIndex returns the raft index at which the current head of chain was
+// applied: the index of the entry carrying it, or the snapshot index if it is
+// the snapshot head.
+func (s *RaftState) ChainHeadIndex(chain *core.BlockChain) (uint64, error) {
+	current := chain.CurrentBlock().Hash()
+
+	index, found := uint64(0), false
+	if s.baseHead(chain) == current {
+		index, found = s.SnapshotIndex(), true
+	}
+	s.headAt(s.baseHead(chain), s.LastIndex(), func(entry raftpb.Entry, block *types.Block) {
+		if block.Hash() == current {
+			index, found = entry.Index, true
+		}
+	})
+	if !found {
+		return 0, fmt.Errorf("chain head %x is not part of the raft log", current)
+	}
+	return index, nil
+}
+
+// Verify cross-checks the raft state against the chain, returning every
+// inconsistency found.
+func (s *RaftState) Verify(chain *core.BlockChain) []StateIssue {
+	var issues []StateIssue
+	report := func(critical bool, format string, args ...interface{}) {
+		issues = append(issues, StateIssue{Critical: critical, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.AppliedIndex > s.HardState.Commit {
+		report(false, "applied index %d is ahead of the durably committed index %d and will be rolled back on restart", s.AppliedIndex, s.HardState.Commit)
+	}
+	if s.AppliedIndex < s.SnapshotIndex() {
+		report(false, "applied index %d is behind the snapshot index %d and will be advanced on restart", s.AppliedIndex, s.SnapshotIndex())
+	}
+	if s.AppliedIndex > s.LastIndex() {
+		report(true, "applied index %d is beyond the last raft log entry %d", s.AppliedIndex, s.LastIndex())
+	}
+	if s.SnapshotData != nil && chain.GetBlockByHash(s.SnapshotData.HeadBlockHash) == nil {
+		report(false, "snapshot head %x is missing from the chain and will be synchronised from peers on restart", s.SnapshotData.HeadBlockHash)
+	}
+
+	headIndex, err := s.ChainHeadIndex(chain)
+	if err != nil {
+		current := chain.CurrentBlock()
+		if s.SnapshotData != nil && chain.GetBlockByHash(s.SnapshotData.HeadBlockHash) == nil {
+			// The chain is behind the snapshot, which has already been reported.
+			return issues
+		}
+		report(true, "chain head %d [%x] is not part of the raft log", current.NumberU64(), current.Hash())
+		return issues
+	}
+
+	// Every block raft considers applied must be in the chain, since restarting
+	// only replays the entries after the applied index.
+	if s.AppliedIndex > headIndex {
+		s.headAt(chain.CurrentBlock().Hash(), s.AppliedIndex, func(entry raftpb.Entry, block *types.Block) {
+			if entry.Index > headIndex && !chain.HasBlock(block.Hash(), block.NumberU64()) {
+				report(true, "raft entry %d (block %d [%x]) is marked as applied but missing from the chain", entry.Index, block.NumberU64(), block.Hash())
+			}
+		})
+	}
+	return issues
+}
+
+// ResetAppliedIndex overwrites the persisted applied index. The index must lie
+// between the snapshot and the durably committed index, so that every entry
+// after it can be replayed from the WAL on restart.
+func (s *RaftState) ResetAppliedIndex(index uint64) error {
+	if index < s.SnapshotIndex() {
+		return fmt.Errorf("index %d is behind the snapshot index %d", index, s.SnapshotIndex())
+	}
+	if index > s.HardState.Commit {
+		return fmt.Errorf("index %d is ahead of the durably committed index %d", index, s.HardState.Commit)
+	}
+	_, _, quorumRaftDbLoc := raftDirs(s.datadir)
+
+	db, err := openQuorumRaftDb(quorumRaftDbLoc)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := storeAppliedIndex(db, index, &opt.WriteOptions{Sync: true}); err != nil {
+		return err
+	}
+	s.AppliedIndex = index
+	return nil
+}
+
+// Compact takes a new raft snapshot at the applied index, so that restarting
+// the node only replays the WAL entries after it. The snapshot records the
+// membership and chain head the node had reached at that index.
+func (s *RaftState) Compact(chain *core.BlockChain, useDns bool) (*raftpb.Snapshot, error) {
+	index := s.AppliedIndex
+	if index <= s.SnapshotIndex() {
+		return nil, errors.New("nothing to compact: the applied index is not beyond the latest snapshot")
+	}
+	if index > s.HardState.Commit {
+		return nil, fmt.Errorf("applied index %d is ahead of the durably committed index %d", index, s.HardState.Commit)
+	}
+	var term uint64
+	for _, entry := range s.Entries {
+		if entry.Index == index {
+			term = entry.Term
+		}
+	}
+	if term == 0 {
+		return nil, fmt.Errorf("applied index %d is not in the raft log", index)
+	}
+
+	head := s.headAt(s.baseHead(chain), index, nil)
+	if chain.GetBlockByHash(head) == nil {
+		return nil, fmt.Errorf("head %x at applied index %d is missing from the chain", head, index)
+	}
+	addresses, removedRaftIds := s.Membership(index)
+
+	confState := raftpb.ConfState{}
+	for _, address := range addresses {
+		confState.Nodes = append(confState.Nodes, uint64(address.RaftId))
+	}
+	snapshotData := &SnapshotWithHostnames{
+		Addresses:      addresses,
+		RemovedRaftIds: removedRaftIds,
+		HeadBlockHash:  head,
+	}
+	snapshot := raftpb.Snapshot{
+		Data: snapshotData.toBytes(useDns),
+		Metadata: raftpb.SnapshotMetadata{
+			ConfState: confState,
+			Index:     index,
+			Term:      term,
+		},
+	}
+
+	// Hold the quorum raft database while rewriting, so that a node starting up
+	// concurrently fails rather than reading a half-written state.
+	waldir, snapdir, quorumRaftDbLoc := raftDirs(s.datadir)
+	db, err := openQuorumRaftDb(quorumRaftDbLoc)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	w, err := wal.Open(waldir, walSnapshot(s.Snapshot))
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+	if _, _, _, err := w.ReadAll(); err != nil {
+		return nil, err
+	}
+
+	// Save the snapshot before recording it in the WAL, in the same order as the
+	// running node does.
+	if err := fileutil.TouchDirAll(snapdir); err != nil {
+		return nil, err
+	}
+	if err := snap.New(snapdir).SaveSnap(snapshot); err != nil {
+		return nil, err
+	}
+	if err := w.SaveSnapshot(walpb.Snapshot{Index: index, Term: term}); err != nil {
+		return nil, err
+	}
+	if err := w.ReleaseLockTo(index); err != nil {
+		return nil, err
+	}
+	s.Snapshot, s.SnapshotData = &snapshot, snapshotData
+
+	return &snapshot, nil
+}
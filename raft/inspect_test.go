@@ -0,0 +1,122 @@
+package raft
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/wal"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// writeTestRaftState persists a WAL with two peers joining, a block, and the
+// second peer being removed, committed up to the last entry.
+func writeTestRaftState(t *testing.T, datadir string, appliedIndex uint64) []raftpb.Entry {
+	waldir, _, quorumRaftDbLoc := raftDirs(datadir)
+
+	var entries []raftpb.Entry
+	for i, raftId := range []uint16{1, 2} {
+		address := &Address{RaftId: raftId, Hostname: "127.0.0.1", P2pPort: enr.TCP(21000 + raftId), RaftPort: enr.RaftPort(50400 + raftId)}
+		cc := raftpb.ConfChange{Type: raftpb.ConfChangeAddNode, NodeID: uint64(raftId), Context: address.toBytes(true)}
+		data, _ := cc.Marshal()
+		entries = append(entries, raftpb.Entry{Index: uint64(i + 1), Term: 1, Type: raftpb.EntryConfChange, Data: data})
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), ParentHash: common.Hash{1}, Difficulty: common.Big0})
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries = append(entries, raftpb.Entry{Index: 3, Term: 1, Type: raftpb.EntryNormal, Data: data})
+
+	cc := raftpb.ConfChange{Type: raftpb.ConfChangeRemoveNode, NodeID: 2}
+	data, _ = cc.Marshal()
+	entries = append(entries, raftpb.Entry{Index: 4, Term: 1, Type: raftpb.EntryConfChange, Data: data})
+
+	w, err := wal.Create(waldir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(raftpb.HardState{Term: 1, Commit: 4}, entries); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	db, err := openQuorumRaftDb(quorumRaftDbLoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storeAppliedIndex(db, appliedIndex, noFsync)
+	db.Close()
+
+	return entries
+}
+
+func TestReadRaftState(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "raft-inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	writeTestRaftState(t, datadir, 3)
+
+	state, err := ReadRaftState(datadir)
+	if err != nil {
+		t.Fatalf("failed to read raft state: %v", err)
+	}
+	if state.Snapshot != nil {
+		t.Errorf("unexpected snapshot: %v", state.Snapshot)
+	}
+	if state.AppliedIndex != 3 || state.HardState.Commit != 4 || state.LastIndex() != 4 {
+		t.Errorf("unexpected indexes: applied %d, commit %d, last %d", state.AppliedIndex, state.HardState.Commit, state.LastIndex())
+	}
+
+	infos := state.DescribeEntries()
+	if len(infos) != 4 || infos[2].Type != "block" || infos[2].BlockNumber != 1 || infos[3].ConfChange != "ConfChangeRemoveNode" {
+		t.Errorf("unexpected entry summary: %+v", infos)
+	}
+
+	addresses, removed := state.Membership(3)
+	if len(addresses) != 2 || addresses[0].RaftId != 1 || addresses[1].RaftId != 2 || len(removed) != 0 {
+		t.Errorf("membership at index 3: have %v removed %v", addresses, removed)
+	}
+	addresses, removed = state.Membership(4)
+	if len(addresses) != 1 || addresses[0].RaftId != 1 || len(removed) != 1 || removed[0] != 2 {
+		t.Errorf("membership at index 4: have %v removed %v", addresses, removed)
+	}
+}
+
+func TestResetAppliedIndex(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "raft-inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	writeTestRaftState(t, datadir, 4)
+
+	state, err := ReadRaftState(datadir)
+	if err != nil {
+		t.Fatalf("failed to read raft state: %v", err)
+	}
+	if err := state.ResetAppliedIndex(5); err == nil {
+		t.Errorf("expected resetting beyond the committed index to fail")
+	}
+	if err := state.ResetAppliedIndex(2); err != nil {
+		t.Fatalf("failed to reset applied index: %v", err)
+	}
+
+	state, err = ReadRaftState(datadir)
+	if err != nil {
+		t.Fatalf("failed to re-read raft state: %v", err)
+	}
+	if state.AppliedIndex != 2 {
+		t.Errorf("applied index mismatch: have %d, want 2", state.AppliedIndex)
+	}
+}
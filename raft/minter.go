@@ -62,7 +62,7 @@ type minter struct {
 	coinbase         common.Address
 	minting          int32 // Atomic status counter
 	shouldMine       *channels.RingChannel
-	blockTime        time.Duration
+	policy           MintingPolicy
 	speculativeChain *speculativeChain
 
 	invalidRaftOrderingChan chan InvalidRaftOrdering
@@ -77,7 +77,7 @@ type extraSeal struct {
 	Signature []byte // Signature of the block minter
 }
 
-func newMinter(config *params.ChainConfig, eth *RaftService, blockTime time.Duration) *minter {
+func newMinter(config *params.ChainConfig, eth *RaftService, policy MintingPolicy) *minter {
 	minter := &minter{
 		config:           config,
 		eth:              eth,
@@ -85,7 +85,7 @@ func newMinter(config *params.ChainConfig, eth *RaftService, blockTime time.Dura
 		chainDb:          eth.ChainDb(),
 		chain:            eth.BlockChain(),
 		shouldMine:       channels.NewRingChannel(1),
-		blockTime:        blockTime,
+		policy:           policy,
 		speculativeChain: newSpeculativeChain(),
 
 		invalidRaftOrderingChan: make(chan InvalidRaftOrdering, 1),
@@ -196,48 +196,75 @@ func (minter *minter) eventLoop() {
 	}
 }
 
-// Returns a wrapper around no-arg func `f` which can be called without limit
-// and returns immediately: this will call the underlying func `f` at most once
-// every `rate`. If this function is called more than once before the underlying
-// `f` is invoked (per this rate limiting), `f` will only be called *once*.
-//
-// TODO(joel): this has a small bug in that you can't call it *immediately* when
-// first allocated.
-func throttle(rate time.Duration, f func()) func() {
-	request := channels.NewRingChannel(1)
+// This function spins continuously, blocking until a block should be created
+// (via requestMinting()). Whether and when a block is actually minted is left
+// to `minter.policy`, which is consulted on every request and re-consulted after
+// any delay it asks for.
+func (minter *minter) mintingLoop() {
+	var (
+		lastMint     time.Time
+		firstPending time.Time
+		wait         <-chan time.Time
+	)
+	for {
+		select {
+		case <-minter.shouldMine.Out():
+		case <-wait:
+		}
+		wait = nil
 
-	// every tick, block waiting for another request. then serve it immediately
-	go func() {
-		ticker := time.NewTicker(rate)
-		defer ticker.Stop()
+		if atomic.LoadInt32(&minter.minting) == 0 {
+			firstPending = time.Time{}
+			continue
+		}
 
-		for range ticker.C {
-			<-request.Out()
-			f()
+		batch := minter.pendingBatch()
+		if batch.Txs == 0 {
+			mintEmptyMeter.Mark(1)
+			firstPending = time.Time{}
+			continue
+		}
+		now := time.Now()
+		if firstPending.IsZero() {
+			firstPending = now
 		}
-	}()
 
-	return func() {
-		request.In() <- struct{}{}
+		mint, delay := minter.policy.Decide(batch, now.Sub(lastMint), now.Sub(firstPending))
+		if !mint {
+			if delay > 0 {
+				wait = time.After(delay)
+			}
+			continue
+		}
+		if minter.mintNewBlock() {
+			lastMint = now
+			mintLatencyTimer.UpdateSince(firstPending)
+			firstPending = time.Time{}
+		}
 	}
 }
 
-// This function spins continuously, blocking until a block should be created
-// (via requestMinting()). This is throttled by `minter.blockTime`:
-//
-//   1. A block is guaranteed to be minted within `blockTime` of being
-//      requested.
-//   2. We never mint a block more frequently than `blockTime`.
-func (minter *minter) mintingLoop() {
-	throttledMintNewBlock := throttle(minter.blockTime, func() {
-		if atomic.LoadInt32(&minter.minting) == 1 {
-			minter.mintNewBlock()
-		}
-	})
+// pendingBatch summarises the pending transactions which are not yet part of
+// the speculative chain.
+func (minter *minter) pendingBatch() PendingBatch {
+	allAddrTxes, err := minter.eth.TxPool().Pending()
+	if err != nil {
+		log.Warn("Failed to retrieve pending transactions", "err", err)
+		return PendingBatch{}
+	}
+
+	minter.mu.Lock()
+	addrTxes := minter.speculativeChain.withoutProposedTxes(allAddrTxes)
+	minter.mu.Unlock()
 
-	for range minter.shouldMine.Out() {
-		throttledMintNewBlock()
+	var batch PendingBatch
+	for _, txes := range addrTxes {
+		batch.Txs += len(txes)
+		for _, tx := range txes {
+			batch.Gas += tx.Gas()
+		}
 	}
+	return batch
 }
 
 func generateNanoTimestamp(parent *types.Block) (tstamp int64) {
@@ -306,11 +333,17 @@ func (minter *minter) firePendingBlockEvents(logs []*types.Log) {
 	}()
 }
 
-func (minter *minter) mintNewBlock() {
+// mintNewBlock mints a block on top of the speculative chain head, returning
+// whether a block was minted at all.
+func (minter *minter) mintNewBlock() bool {
 	minter.mu.Lock()
 	defer minter.mu.Unlock()
 
 	work := minter.createWork()
+	if !minter.config.IsRaftMinted(work.header.Number) {
+		log.Warn("Not minting a new block before the transition from Istanbul", "number", work.header.Number, "raftBlock", minter.config.Istanbul.RaftBlock)
+		return false
+	}
 	transactions := minter.getTransactions()
 
 	committedTxes, publicReceipts, privateReceipts, logs := work.commitTransactions(transactions, minter.chain)
@@ -318,7 +351,7 @@ func (minter *minter) mintNewBlock() {
 
 	if txCount == 0 {
 		log.Info("Not minting a new block since there are no pending transactions")
-		return
+		return false
 	}
 
 	minter.firePendingBlockEvents(logs)
@@ -365,6 +398,11 @@ func (minter *minter) mintNewBlock() {
 
 	elapsed := time.Since(time.Unix(0, header.Time.Int64()))
 	log.Info("🔨  Mined block", "number", block.Number(), "hash", fmt.Sprintf("%x", block.Hash().Bytes()[:4]), "elapsed", elapsed)
+
+	mintBatchTxsHistogram.Update(int64(txCount))
+	mintBatchGasHistogram.Update(int64(header.GasUsed))
+
+	return true
 }
 
 func (env *work) commitTransactions(txes *types.TransactionsByPriceAndNonce, bc *core.BlockChain) (types.Transactions, types.Receipts, types.Receipts, []*types.Log) {
@@ -67,3 +67,46 @@ func TestSignHeader(t *testing.T) {
 	}
 
 }
+
+func TestFixedMintingPolicy(t *testing.T) {
+	policy := NewFixedMintingPolicy(50 * time.Millisecond)
+
+	if mint, wait := policy.Decide(PendingBatch{}, time.Second, 0); mint || wait != 0 {
+		t.Errorf("empty batch: expected to idle, got mint=%v wait=%v", mint, wait)
+	}
+	if mint, wait := policy.Decide(PendingBatch{Txs: 1}, 20*time.Millisecond, 0); mint || wait != 30*time.Millisecond {
+		t.Errorf("throttled batch: expected to wait 30ms, got mint=%v wait=%v", mint, wait)
+	}
+	if mint, _ := policy.Decide(PendingBatch{Txs: 1}, 50*time.Millisecond, 0); !mint {
+		t.Errorf("expected to mint once blockTime has elapsed")
+	}
+}
+
+func TestAdaptiveMintingPolicy(t *testing.T) {
+	policy := &AdaptiveMintingPolicy{
+		MinInterval: 50 * time.Millisecond,
+		MaxLatency:  time.Second,
+		MaxBatchTxs: 100,
+		MaxBatchGas: 1000000,
+	}
+	tests := []struct {
+		batch             PendingBatch
+		sinceLastMint     time.Duration
+		sinceFirstPending time.Duration
+		mint              bool
+		wait              time.Duration
+	}{
+		{PendingBatch{}, time.Minute, 0, false, 0},
+		{PendingBatch{Txs: 100}, 10 * time.Millisecond, 0, false, 40 * time.Millisecond},
+		{PendingBatch{Txs: 100}, time.Minute, 0, true, 0},
+		{PendingBatch{Txs: 1, Gas: 1000000}, time.Minute, 0, true, 0},
+		{PendingBatch{Txs: 1, Gas: 21000}, time.Minute, 400 * time.Millisecond, false, 600 * time.Millisecond},
+		{PendingBatch{Txs: 1, Gas: 21000}, time.Minute, time.Second, true, 0},
+	}
+	for i, tt := range tests {
+		mint, wait := policy.Decide(tt.batch, tt.sinceLastMint, tt.sinceFirstPending)
+		if mint != tt.mint || wait != tt.wait {
+			t.Errorf("test %d: have mint=%v wait=%v, want mint=%v wait=%v", i, mint, wait, tt.mint, tt.wait)
+		}
+	}
+}
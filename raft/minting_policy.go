@@ -0,0 +1,84 @@
+package raft
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	mintBatchTxsHistogram = metrics.NewRegisteredHistogram("raft/minter/batch/txs", nil, metrics.NewExpDecaySample(1028, 0.015))
+	mintBatchGasHistogram = metrics.NewRegisteredHistogram("raft/minter/batch/gas", nil, metrics.NewExpDecaySample(1028, 0.015))
+	mintLatencyTimer      = metrics.NewRegisteredTimer("raft/minter/latency", nil) // Time from the first pending tx to its block being minted
+	mintEmptyMeter        = metrics.NewRegisteredMeter("raft/minter/empty", nil)   // Minting requests suppressed because there was nothing to include
+)
+
+// PendingBatch summarises the transactions which would be included if a block
+// were minted right now.
+type PendingBatch struct {
+	Txs int    // Number of pending transactions not yet in the speculative chain
+	Gas uint64 // Sum of the gas limits of those transactions
+}
+
+// MintingPolicy decides when the minter produces a new block.
+//
+// Decide is consulted whenever minting is requested or a previously requested
+// delay has elapsed. sinceLastMint is the time since the last block was minted and
+// sinceFirstPending the time since the oldest transaction in the batch was first
+// seen. If mint is true a block is minted right away; otherwise a non-zero wait
+// asks for the decision to be revisited after that long, and a zero wait idles
+// until the next minting request.
+type MintingPolicy interface {
+	Decide(batch PendingBatch, sinceLastMint, sinceFirstPending time.Duration) (mint bool, wait time.Duration)
+}
+
+// fixedMintingPolicy mints whenever there are pending transactions, but never
+// more frequently than once every blockTime. This is the classic raft behaviour.
+type fixedMintingPolicy struct {
+	blockTime time.Duration
+}
+
+// NewFixedMintingPolicy returns a policy minting at most once every blockTime,
+// as soon as there is anything to mint.
+func NewFixedMintingPolicy(blockTime time.Duration) MintingPolicy {
+	return &fixedMintingPolicy{blockTime: blockTime}
+}
+
+func (p *fixedMintingPolicy) Decide(batch PendingBatch, sinceLastMint, sinceFirstPending time.Duration) (bool, time.Duration) {
+	if batch.Txs == 0 {
+		return false, 0
+	}
+	if sinceLastMint < p.blockTime {
+		return false, p.blockTime - sinceLastMint
+	}
+	return true, 0
+}
+
+// AdaptiveMintingPolicy lets the block time follow the load: under heavy load a
+// block is minted as soon as a full batch is pending, while under light load the
+// minter waits up to MaxLatency to collect a larger batch.
+type AdaptiveMintingPolicy struct {
+	MinInterval time.Duration // Blocks are never minted more frequently than this
+	MaxLatency  time.Duration // Upper bound on the time a pending transaction waits to be minted
+	MaxBatchTxs int           // Mint immediately once this many transactions are pending (0 = no limit)
+	MaxBatchGas uint64        // Mint immediately once this much gas is pending (0 = no limit)
+}
+
+func (p *AdaptiveMintingPolicy) Decide(batch PendingBatch, sinceLastMint, sinceFirstPending time.Duration) (bool, time.Duration) {
+	if batch.Txs == 0 {
+		return false, 0
+	}
+	if sinceLastMint < p.MinInterval {
+		return false, p.MinInterval - sinceLastMint
+	}
+	if p.MaxBatchTxs > 0 && batch.Txs >= p.MaxBatchTxs {
+		return true, 0
+	}
+	if p.MaxBatchGas > 0 && batch.Gas >= p.MaxBatchGas {
+		return true, 0
+	}
+	if sinceFirstPending >= p.MaxLatency {
+		return true, 0
+	}
+	return false, p.MaxLatency - sinceFirstPending
+}
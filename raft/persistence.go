@@ -30,14 +30,9 @@ func openQuorumRaftDb(path string) (db *leveldb.DB, err error) {
 }
 
 func (pm *ProtocolManager) loadAppliedIndex() uint64 {
-	dat, err := pm.quorumRaftDb.Get(appliedDbKey, nil)
-	var lastAppliedIndex uint64
-	if err == errors.ErrNotFound {
-		lastAppliedIndex = 0
-	} else if err != nil {
+	lastAppliedIndex, err := readAppliedIndex(pm.quorumRaftDb)
+	if err != nil {
 		fatalf("loadAppliedIndex error: %s", err)
-	} else {
-		lastAppliedIndex = binary.LittleEndian.Uint64(dat)
 	}
 
 	pm.mu.Lock()
@@ -51,7 +46,25 @@ func (pm *ProtocolManager) loadAppliedIndex() uint64 {
 
 func (pm *ProtocolManager) writeAppliedIndex(index uint64) {
 	log.Info("persisted the latest applied index", "index", index)
+	storeAppliedIndex(pm.quorumRaftDb, index, noFsync)
+}
+
+// readAppliedIndex retrieves the last-applied raft index from the quorum raft
+// database, defaulting to zero if none has been persisted yet.
+func readAppliedIndex(db *leveldb.DB) (uint64, error) {
+	dat, err := db.Get(appliedDbKey, nil)
+	if err == errors.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(dat), nil
+}
+
+// storeAppliedIndex persists the last-applied raft index into the quorum raft
+// database.
+func storeAppliedIndex(db *leveldb.DB, index uint64, wo *opt.WriteOptions) error {
 	buf := make([]byte, 8)
 	binary.LittleEndian.PutUint64(buf, index)
-	pm.quorumRaftDb.Put(appliedDbKey, buf, noFsync)
+	return db.Put(appliedDbKey, buf, wo)
 }
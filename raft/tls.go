@@ -0,0 +1,262 @@
+package raft
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// TLSConfig configures TLS for the raft transport. When enabled, every raft
+// connection is mutually authenticated, and the enode identity carried by the
+// peer's certificate must belong to the cluster.
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string // PEM certificate; derived from the node key if empty
+	KeyFile  string // PEM private key matching CertFile
+	CAFile   string // PEM CA bundle verifying peer certificates issued from files
+}
+
+var (
+	// certIdentityOID identifies the certificate extension holding the node key
+	// signature which binds a derived certificate to an enode ID.
+	certIdentityOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 53594, 1, 1}
+
+	certIdentityPrefix = []byte("quorum-raft-tls:")
+
+	errNoCertIdentity = errors.New("peer certificate carries no enode identity")
+)
+
+// raftTLS holds the TLS material for the raft transport and verifies peer
+// certificates against the cluster membership.
+type raftTLS struct {
+	certificate tls.Certificate
+	roots       *x509.CertPool // nil for certificates derived from node keys
+
+	// trusted reports whether the node with the given public key may take part
+	// in the raft transport.
+	trusted func(*ecdsa.PublicKey) error
+
+	// dialed reports whether the node with the given public key is the one
+	// expected to listen at the given address.
+	dialed func(addr string, pub *ecdsa.PublicKey) error
+}
+
+// newRaftTLS loads or derives the TLS material of the node.
+func newRaftTLS(config *TLSConfig, nodeKey *ecdsa.PrivateKey, trusted func(*ecdsa.PublicKey) error, dialed func(string, *ecdsa.PublicKey) error) (*raftTLS, error) {
+	t := &raftTLS{trusted: trusted, dialed: dialed}
+
+	if config.CertFile == "" {
+		if config.KeyFile != "" || config.CAFile != "" {
+			return nil, errors.New("raft TLS key and CA files require a certificate file")
+		}
+		cert, err := deriveCertificate(nodeKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive raft TLS certificate: %v", err)
+		}
+		t.certificate = cert
+		return t, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load raft TLS key pair: %v", err)
+	}
+	t.certificate = cert
+
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read raft TLS CA file: %v", err)
+		}
+		t.roots = x509.NewCertPool()
+		if !t.roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in raft TLS CA file %s", config.CAFile)
+		}
+	}
+	return t, nil
+}
+
+// serverConfig returns the TLS configuration for the raft listener.
+func (t *raftTLS) serverConfig() *tls.Config {
+	return &tls.Config{
+		Certificates:          []tls.Certificate{t.certificate},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: t.verifyPeerCertificate,
+		MinVersion:            tls.VersionTLS12,
+	}
+}
+
+// clientConfig returns the TLS configuration for dialing the raft peer at the
+// given address. The peer's certificate chain, if certificates are CA-issued,
+// is verified along with the enode identity it carries, which must be the one
+// of the node expected at that address.
+func (t *raftTLS) clientConfig(addr string) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{t.certificate},
+		// Certificates are verified against the enode identities instead of
+		// host names, derived ones being self-signed
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			pub, err := t.peerIdentity(rawCerts)
+			if err != nil {
+				return err
+			}
+			return t.dialed(addr, pub)
+		},
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// configureTransport makes a rafthttp client transport dial the raft peers over
+// TLS, authenticating each of them with clientConfig.
+func (t *raftTLS) configureTransport(tr *http.Transport) {
+	dial := tr.Dial
+	if dial == nil {
+		dial = net.Dial
+	}
+	timeout := tr.TLSHandshakeTimeout
+	tr.DialTLS = func(network, addr string) (net.Conn, error) {
+		conn, err := dial(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if timeout > 0 {
+			conn.SetDeadline(time.Now().Add(timeout))
+		}
+		tlsConn := tls.Client(conn, t.clientConfig(addr))
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn.SetDeadline(time.Time{})
+		return tlsConn, nil
+	}
+}
+
+// verifyPeerCertificate checks that the enode identity carried by the
+// certificate of a dialing peer is trusted.
+func (t *raftTLS) verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	pub, err := t.peerIdentity(rawCerts)
+	if err != nil {
+		return err
+	}
+	return t.trusted(pub)
+}
+
+// peerIdentity checks the peer's certificate chain, if certificates are
+// CA-issued, and returns the node public key the certificate belongs to.
+func (t *raftTLS) peerIdentity(rawCerts [][]byte) (*ecdsa.PublicKey, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.New("no peer certificate presented")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		certs[i] = cert
+	}
+	leaf := certs[0]
+
+	if t.roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		opts := x509.VerifyOptions{
+			Roots:         t.roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		if _, err := leaf.Verify(opts); err != nil {
+			return nil, err
+		}
+	}
+	return certificateIdentity(leaf, t.roots != nil)
+}
+
+// deriveCertificate creates a self-signed certificate for a fresh TLS key, and
+// binds it to the node's enode identity by signing the TLS public key with the
+// node key.
+func deriveCertificate(nodeKey *ecdsa.PrivateKey) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	spki, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(certIdentityPrefix, spki), nodeKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	ext, err := asn1.Marshal(sig)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	tmpl := x509.Certificate{
+		SerialNumber:    serial,
+		Subject:         pkix.Name{CommonName: enode.PubkeyToIDV4(&nodeKey.PublicKey).String()},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		ExtraExtensions: []pkix.Extension{{Id: certIdentityOID, Value: ext}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// certificateIdentity extracts the node public key a certificate belongs to.
+// Derived certificates carry a node key signature over their TLS key. CA-issued
+// certificates may instead name the node through an enode URI subject
+// alternative name, since the CA vouches for the binding.
+func certificateIdentity(cert *x509.Certificate, caIssued bool) (*ecdsa.PublicKey, error) {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(certIdentityOID) {
+			continue
+		}
+		var sig []byte
+		if _, err := asn1.Unmarshal(ext.Value, &sig); err != nil {
+			return nil, err
+		}
+		return crypto.SigToPub(crypto.Keccak256(certIdentityPrefix, cert.RawSubjectPublicKeyInfo), sig)
+	}
+	if caIssued {
+		for _, uri := range cert.URIs {
+			if uri.Scheme != "enode" || uri.User == nil {
+				continue
+			}
+			raw, err := hex.DecodeString(uri.User.Username())
+			if err != nil {
+				return nil, fmt.Errorf("invalid enode URI in peer certificate: %v", err)
+			}
+			return crypto.UnmarshalPubkey(append([]byte{0x04}, raw...))
+		}
+	}
+	return nil, errNoCertIdentity
+}
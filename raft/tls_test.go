@@ -0,0 +1,128 @@
+package raft
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// handshake runs a TLS handshake between a raft client and server over an
+// in-memory connection, returning the client and server errors.
+func handshake(client, server *raftTLS) (error, error) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	clientConfig := client.clientConfig("127.0.0.1:50400")
+	serverErr := make(chan error, 1)
+	go func() {
+		conn := tls.Server(serverConn, server.serverConfig())
+		err := conn.Handshake()
+		serverConn.Close()
+		serverErr <- err
+	}()
+	clientErr := tls.Client(clientConn, clientConfig).Handshake()
+	clientConn.Close()
+
+	return clientErr, <-serverErr
+}
+
+func trustKeys(keys ...*ecdsa.PrivateKey) func(*ecdsa.PublicKey) error {
+	return func(pub *ecdsa.PublicKey) error {
+		for _, key := range keys {
+			if key.PublicKey.X.Cmp(pub.X) == 0 && key.PublicKey.Y.Cmp(pub.Y) == 0 {
+				return nil
+			}
+		}
+		return errors.New("untrusted")
+	}
+}
+
+// expectKeys returns a dialed peer check accepting the given keys only.
+func expectKeys(keys ...*ecdsa.PrivateKey) func(string, *ecdsa.PublicKey) error {
+	trusted := trustKeys(keys...)
+	return func(_ string, pub *ecdsa.PublicKey) error {
+		return trusted(pub)
+	}
+}
+
+func TestDerivedCertificateIdentity(t *testing.T) {
+	nodeKey, _ := crypto.GenerateKey()
+
+	cert, err := deriveCertificate(nodeKey)
+	if err != nil {
+		t.Fatalf("failed to derive certificate: %v", err)
+	}
+	if err := (&raftTLS{trusted: trustKeys(nodeKey)}).verifyPeerCertificate(cert.Certificate, nil); err != nil {
+		t.Errorf("derived certificate rejected: %v", err)
+	}
+	otherKey, _ := crypto.GenerateKey()
+	if err := (&raftTLS{trusted: trustKeys(otherKey)}).verifyPeerCertificate(cert.Certificate, nil); err == nil {
+		t.Errorf("derived certificate of an untrusted node accepted")
+	}
+}
+
+func TestRaftTLSHandshake(t *testing.T) {
+	clientKey, _ := crypto.GenerateKey()
+	serverKey, _ := crypto.GenerateKey()
+	strangerKey, _ := crypto.GenerateKey()
+
+	client, err := newRaftTLS(&TLSConfig{Enabled: true}, clientKey, trustKeys(serverKey), expectKeys(serverKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := newRaftTLS(&TLSConfig{Enabled: true}, serverKey, trustKeys(clientKey), expectKeys(clientKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clientErr, serverErr := handshake(client, server); clientErr != nil || serverErr != nil {
+		t.Fatalf("handshake between members failed: client %v, server %v", clientErr, serverErr)
+	}
+
+	stranger, err := newRaftTLS(&TLSConfig{Enabled: true}, strangerKey, trustKeys(clientKey, serverKey), expectKeys(serverKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, serverErr := handshake(stranger, server); serverErr == nil {
+		t.Fatalf("server accepted a client outside the cluster")
+	}
+	// The stranger impersonating the server is refused by the dialing member
+	if clientErr, _ := handshake(client, stranger); clientErr == nil {
+		t.Fatalf("client accepted a server impersonating a member")
+	}
+}
+
+func TestCheckDialedIdentity(t *testing.T) {
+	memberKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	strangerKey, _ := crypto.GenerateKey()
+
+	pm := &ProtocolManager{peers: make(map[uint16]*Peer)}
+	for i, key := range []*ecdsa.PrivateKey{memberKey, otherKey} {
+		node := enode.NewV4(&key.PublicKey, net.IP{127, 0, 0, 1}, 21000+i, 0, 50400+i)
+		address := &Address{RaftId: uint16(i + 1), Hostname: "127.0.0.1", RaftPort: enr.RaftPort(50400 + i)}
+		pm.peers[address.RaftId] = &Peer{address, node}
+	}
+	tests := []struct {
+		addr string
+		key  *ecdsa.PrivateKey
+		ok   bool
+	}{
+		{"127.0.0.1:50400", memberKey, true},
+		{"127.0.0.1:50400", otherKey, false},    // another member listening at the address
+		{"127.0.0.1:50400", strangerKey, false}, // a node outside the cluster
+		{"127.0.0.1:50402", otherKey, true},     // an unknown address, any member
+		{"127.0.0.1:50402", strangerKey, false},
+	}
+	for i, tt := range tests {
+		if err := pm.checkDialedIdentity(tt.addr, &tt.key.PublicKey); (err == nil) != tt.ok {
+			t.Errorf("test %d: dialing %s: have %v, want ok %v", i, tt.addr, err, tt.ok)
+		}
+	}
+}
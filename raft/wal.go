@@ -2,6 +2,7 @@ package raft
 
 import (
 	"os"
+	"sort"
 
 	"github.com/coreos/etcd/raft/raftpb"
 	"github.com/coreos/etcd/wal"
@@ -22,14 +23,10 @@ func (pm *ProtocolManager) openWAL(maybeRaftSnapshot *raftpb.Snapshot) *wal.WAL
 		wal.Close()
 	}
 
-	walsnap := walpb.Snapshot{}
+	walsnap := walSnapshot(maybeRaftSnapshot)
 
 	log.Info("loading WAL", "term", walsnap.Term, "index", walsnap.Index)
 
-	if maybeRaftSnapshot != nil {
-		walsnap.Index, walsnap.Term = maybeRaftSnapshot.Metadata.Index, maybeRaftSnapshot.Metadata.Term
-	}
-
 	wal, err := wal.Open(pm.waldir, walsnap)
 	if err != nil {
 		fatalf("error loading WAL: %s", err)
@@ -47,8 +44,94 @@ func (pm *ProtocolManager) replayWAL(maybeRaftSnapshot *raftpb.Snapshot) (*wal.W
 		fatalf("failed to read WAL: %s", err)
 	}
 
+	if pm.forceNewCluster {
+		hardState, entries = pm.forceSoleMember(wal, maybeRaftSnapshot, hardState, entries)
+	}
+
 	pm.raftStorage.SetHardState(hardState)
 	pm.raftStorage.Append(entries)
 
 	return wal, entries
 }
+
+// forceSoleMember turns this node into the sole member of a new cluster, for
+// recovering from the permanent loss of a majority of the cluster. Like etcd's
+// --force-new-cluster, it discards the uncommitted entries and appends committed
+// conf changes removing every other member. Applying these through the usual
+// conf change handling updates the membership and snapshot, while the chain and
+// applied index are left intact.
+func (pm *ProtocolManager) forceSoleMember(w *wal.WAL, maybeRaftSnapshot *raftpb.Snapshot, hardState raftpb.HardState, entries []raftpb.Entry) (raftpb.HardState, []raftpb.Entry) {
+	committed := entries[:0:0]
+	for _, entry := range entries {
+		if entry.Index <= hardState.Commit {
+			committed = append(committed, entry)
+		}
+	}
+	if discarded := len(entries) - len(committed); discarded > 0 {
+		log.Warn("discarding uncommitted raft entries to force a new cluster", "count", discarded)
+	}
+
+	state := &RaftState{Snapshot: maybeRaftSnapshot, Entries: committed}
+	if maybeRaftSnapshot != nil {
+		state.SnapshotData = bytesToSnapshot(maybeRaftSnapshot.Data)
+	}
+	addresses, _ := state.Membership(hardState.Commit)
+
+	var otherIds []uint16
+	for _, address := range addresses {
+		if address.RaftId != pm.raftId {
+			otherIds = append(otherIds, address.RaftId)
+		}
+	}
+	sort.Slice(otherIds, func(i, j int) bool { return otherIds[i] < otherIds[j] })
+
+	index := hardState.Commit
+	if index < state.SnapshotIndex() {
+		index = state.SnapshotIndex()
+	}
+	var removals []raftpb.Entry
+	for _, raftId := range otherIds {
+		cc := raftpb.ConfChange{Type: raftpb.ConfChangeRemoveNode, NodeID: uint64(raftId)}
+		data, err := cc.Marshal()
+		if err != nil {
+			fatalf("failed to encode conf change: %s", err)
+		}
+		index++
+		removals = append(removals, raftpb.Entry{Type: raftpb.EntryConfChange, Term: hardState.Term, Index: index, Data: data})
+
+		log.Warn("forcing removal of raft peer to form a new cluster", "raft id", raftId)
+	}
+	hardState.Commit = index
+
+	// Entries overwrite any uncommitted entries with the same index when the WAL
+	// is read back.
+	if err := w.Save(hardState, removals); err != nil {
+		fatalf("failed to save forced conf changes to WAL: %s", err)
+	}
+	log.Warn("forced a new raft cluster with this node as its sole member", "raft id", pm.raftId, "commit index", hardState.Commit)
+
+	return hardState, append(committed, removals...)
+}
+
+// walSnapshot returns the WAL position to start reading from given the latest
+// raft snapshot, if any.
+func walSnapshot(maybeRaftSnapshot *raftpb.Snapshot) walpb.Snapshot {
+	walsnap := walpb.Snapshot{}
+	if maybeRaftSnapshot != nil {
+		walsnap.Index, walsnap.Term = maybeRaftSnapshot.Metadata.Index, maybeRaftSnapshot.Metadata.Term
+	}
+	return walsnap
+}
+
+// readWAL reads the hard state and all entries following the given snapshot
+// from the WAL in waldir, without locking it for writing.
+func readWAL(waldir string, maybeRaftSnapshot *raftpb.Snapshot) (raftpb.HardState, []raftpb.Entry, error) {
+	w, err := wal.OpenForRead(waldir, walSnapshot(maybeRaftSnapshot))
+	if err != nil {
+		return raftpb.HardState{}, nil, err
+	}
+	defer w.Close()
+
+	_, hardState, entries, err := w.ReadAll()
+	return hardState, entries, err
+}
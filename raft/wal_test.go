@@ -0,0 +1,68 @@
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	etcdRaft "github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/wal"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+func TestForceNewCluster(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "raft-force")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+	waldir, _, _ := raftDirs(datadir)
+
+	// Three members joined, followed by an uncommitted empty entry.
+	var entries []raftpb.Entry
+	for _, raftId := range []uint16{1, 2, 3} {
+		address := &Address{RaftId: raftId, Hostname: "127.0.0.1", P2pPort: enr.TCP(21000 + raftId), RaftPort: enr.RaftPort(50400 + raftId)}
+		cc := raftpb.ConfChange{Type: raftpb.ConfChangeAddNode, NodeID: uint64(raftId), Context: address.toBytes(true)}
+		data, _ := cc.Marshal()
+		entries = append(entries, raftpb.Entry{Index: uint64(raftId), Term: 2, Type: raftpb.EntryConfChange, Data: data})
+	}
+	entries = append(entries, raftpb.Entry{Index: 4, Term: 2, Type: raftpb.EntryNormal})
+
+	w, err := wal.Create(waldir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Save(raftpb.HardState{Term: 2, Commit: 3}, entries); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	pm := &ProtocolManager{raftId: 2, forceNewCluster: true, waldir: waldir, raftStorage: etcdRaft.NewMemoryStorage()}
+	w, replayed := pm.replayWAL(nil)
+	w.Close()
+
+	hardState, persisted, err := readWAL(waldir, nil)
+	if err != nil {
+		t.Fatalf("failed to re-read WAL: %v", err)
+	}
+	for _, have := range [][]raftpb.Entry{replayed, persisted} {
+		if len(have) != 5 {
+			t.Fatalf("entry count mismatch: have %d, want 5", len(have))
+		}
+		for i, wantId := range []uint64{1, 3} {
+			entry := have[3+i]
+			var cc raftpb.ConfChange
+			if err := cc.Unmarshal(entry.Data); err != nil || entry.Type != raftpb.EntryConfChange || cc.Type != raftpb.ConfChangeRemoveNode || cc.NodeID != wantId {
+				t.Errorf("entry %d: expected removal of raft ID %d, have %v %v", entry.Index, wantId, entry.Type, cc)
+			}
+			if entry.Index != uint64(4+i) || entry.Term != 2 {
+				t.Errorf("entry %d: unexpected position, term %d", entry.Index, entry.Term)
+			}
+		}
+	}
+	if hardState.Commit != 5 {
+		t.Errorf("commit index mismatch: have %d, want 5", hardState.Commit)
+	}
+}
@@ -244,6 +244,14 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if origin := r.Header.Get("Origin"); origin != "" {
 		ctx = context.WithValue(ctx, "Origin", origin)
 	}
+	// Quorum: the tenant whose private state the request is served from, and
+	// its API token
+	if tenant := r.Header.Get("Tenant"); tenant != "" {
+		ctx = context.WithValue(ctx, "Tenant", tenant)
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		ctx = context.WithValue(ctx, "TenantToken", strings.TrimPrefix(auth, "Bearer "))
+	}
 
 	body := io.LimitReader(r.Body, maxRequestContentLength)
 	codec := NewJSONCodec(&httpReadWriteNopCloser{body, w})